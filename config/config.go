@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -10,22 +12,52 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	GitHub   GitHubConfig
-	CORS     CORSConfig
-	LogLevel string
+	Server      ServerConfig
+	GitHub      GitHubConfig
+	CORS        CORSConfig
+	Badges      BadgeConfig
+	Webhooks    WebhookConfig
+	Fanout      FanoutConfig
+	Streams     StreamConfig
+	Auth        AuthConfig
+	Policy      PolicyConfig
+	OAuth       OAuthConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Feedback    FeedbackConfig
+	Attachments AttachmentsConfig
+	Jobs        JobsConfig
+	BulkIssues  BulkIssuesConfig
+	LogLevel    string
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
 	Port    string
 	GinMode string
+	// ShutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+	ShutdownTimeout time.Duration
+	// TrustedProxies lists the proxy addresses or CIDRs whose X-Forwarded-For
+	// header is believed; with none, clients are identified by the connecting address
+	TrustedProxies []string
 }
 
 // GitHubConfig holds GitHub API configuration
 type GitHubConfig struct {
+	// Token is the only token, or the token writes are sent with when Tokens is set
 	Token    string
 	Username string
+	// Tokens are pooled for reads, which go to the token with the most rate limit remaining
+	Tokens []string
+	// TokenQuarantine is how long a pooled token is skipped after GitHub rejects it
+	TokenQuarantine time.Duration
+	// TokenCheck verifies the token's access at startup: off, warn or strict
+	TokenCheck string
+	// AppID selects GitHub App authentication instead of Token when set
+	AppID             int64
+	AppPrivateKeyPath string
+	// AppInstallationID is used for every owner instead of looking up each owner's installation
+	AppInstallationID int64
 }
 
 // CORSConfig holds CORS configuration
@@ -33,6 +65,167 @@ type CORSConfig struct {
 	AllowOrigins []string
 }
 
+// BadgeConfig holds configuration for the SVG badge and card endpoints
+type BadgeConfig struct {
+	CacheMaxAge    int
+	FallbackMaxAge int
+	// PublicRepos are the repositories whose badges and feeds anyone can
+	// read; they are served for no other repository
+	PublicRepos []string
+}
+
+// WebhookConfig holds configuration for the inbound GitHub webhook receiver
+type WebhookConfig struct {
+	Secret      string
+	PublicURL   string
+	DeliveryTTL time.Duration
+}
+
+// FanoutConfig holds configuration for forwarding webhook events to subscribers
+type FanoutConfig struct {
+	StatePath      string
+	Workers        int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+	// MaxDeadLetters and DeadLetterTTL bound the dead-letter queue, dropping
+	// the oldest entries first; zero leaves it unbounded
+	MaxDeadLetters int
+	DeadLetterTTL  time.Duration
+}
+
+// StreamConfig holds configuration for the live repository event streams
+type StreamConfig struct {
+	// Source is "webhook", "poll" or "auto"; auto polls when no webhook secret is set
+	Source            string
+	PollInterval      time.Duration
+	HeartbeatInterval time.Duration
+	HistorySize       int
+	BufferSize        int
+	// ClientBufferSize bounds the messages queued for each WebSocket client
+	ClientBufferSize int
+	// DropPolicy is "drop_oldest", "drop_newest" or "disconnect" and applies when a client's buffer is full
+	DropPolicy string
+}
+
+// AuthConfig holds configuration for authenticating callers of the service
+type AuthConfig struct {
+	// APIKeysPath is the file of hashed API keys; authentication is disabled when it is empty
+	APIKeysPath            string
+	DefaultRotationOverlap time.Duration
+	// JWKS is the URL or file of the SSO signing keys; JWT authentication is disabled when it is empty
+	JWKS        string
+	JWTIssuer   string
+	JWTAudience string
+	// JWTGroupsClaim names the claim listing the caller's groups
+	JWTGroupsClaim string
+	// JWTGroupScopes maps groups to scopes, e.g. "platform=admin;support=read,issues:write"
+	JWTGroupScopes string
+	JWKSCacheTTL   time.Duration
+	JWTLeeway      time.Duration
+}
+
+// PolicyConfig holds configuration for the per-repository access policy
+type PolicyConfig struct {
+	// Path is the YAML policy file; policy checks are disabled when it is empty
+	Path string
+}
+
+// OAuthConfig holds configuration for signing users in with a GitHub OAuth App
+type OAuthConfig struct {
+	// ClientID identifies the OAuth App; the login flow is disabled when it is empty
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are the GitHub scopes requested from users
+	Scopes []string
+	// SessionSecret encrypts session cookies and must be at least 32 characters
+	SessionSecret string
+	SessionTTL    time.Duration
+	// SessionScopes are the service scopes granted to signed-in users when authentication is enabled
+	SessionScopes []string
+}
+
+// RateLimitConfig holds configuration for limiting each client's use of the service
+type RateLimitConfig struct {
+	// PerMinute is the average cost a client may spend per minute; rate limiting is disabled when it is zero
+	PerMinute int
+	Burst     int
+	// DailyQuota is the cost a client may spend per UTC day; quotas are disabled when it is zero
+	DailyQuota int
+	QuotaPath  string
+}
+
+// IdempotencyConfig holds configuration for replaying retried requests
+type IdempotencyConfig struct {
+	// TTL is how long the response to an Idempotency-Key is kept
+	TTL time.Duration
+}
+
+// FeedbackConfig holds configuration for the public feedback form
+type FeedbackConfig struct {
+	// Repos are the repositories accepting feedback; the form is disabled when it is empty
+	Repos []string
+	// Labels are added to every issue created from feedback
+	Labels []string
+	// FormSecret signs form tokens; a random secret is used when it is empty
+	FormSecret string
+	// MinFillTime is how long a form must be shown before it may be submitted
+	MinFillTime time.Duration
+	FormMaxAge  time.Duration
+	// PerMinute and Burst throttle submissions from each IP address
+	PerMinute      int
+	Burst          int
+	MaxTitleLength int
+	MaxBodyLength  int
+	// Captcha names the CAPTCHA provider; CAPTCHAs are not required when it is empty
+	Captcha       string
+	CaptchaSecret string
+}
+
+// AttachmentsConfig holds configuration for files uploaded with issues
+type AttachmentsConfig struct {
+	// Store is the blob store backend, local or s3; uploads are refused when it is empty
+	Store string
+	// PublicURL is the absolute URL stored files are linked under
+	PublicURL string
+	LocalDir  string
+	// S3Endpoint is the S3 API URL, e.g. https://s3.eu-west-1.amazonaws.com or a MinIO server
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// MaxFileSize is in bytes
+	MaxFileSize int64
+	MaxFiles    int
+	// AllowedTypes are the media types accepted, as detected from each file's content
+	AllowedTypes []string
+}
+
+// JobsConfig holds configuration for the background job queue
+type JobsConfig struct {
+	// DBPath is the BoltDB file jobs are kept in; jobs are only kept in memory when it is empty
+	DBPath  string
+	Workers int
+	// Retention is how long finished jobs can still be looked up
+	Retention time.Duration
+	// MaxAttempts bounds how often a failing job is run before it is marked failed
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// BulkIssuesConfig holds configuration for creating issues in bulk
+type BulkIssuesConfig struct {
+	MaxRows int
+	// Interval spaces out issue creation to stay within GitHub's limits on creating content
+	Interval time.Duration
+	// RateLimitBackoff is the first wait after GitHub limits the rate; it doubles on each retry
+	RateLimitBackoff time.Duration
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	// Load .env file if it exists
@@ -43,28 +236,146 @@ func LoadConfig() *Config {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port:    getEnv("PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "release"),
+			Port:            getEnv("PORT", "8080"),
+			GinMode:         getEnv("GIN_MODE", "release"),
+			TrustedProxies:  splitList(getEnv("TRUSTED_PROXIES", "")),
+			ShutdownTimeout: getEnvPositiveDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 		},
 		GitHub: GitHubConfig{
-			Token:    getEnv("GITHUB_TOKEN", ""),
-			Username: getEnv("GITHUB_USERNAME", ""),
+			Token:             getEnv("GITHUB_TOKEN", ""),
+			Username:          getEnv("GITHUB_USERNAME", ""),
+			Tokens:            splitList(getEnv("GITHUB_TOKENS", "")),
+			TokenQuarantine:   getEnvDuration("GITHUB_TOKEN_QUARANTINE", time.Hour),
+			TokenCheck:        getEnv("GITHUB_TOKEN_CHECK", "warn"),
+			AppID:             int64(getEnvInt("GITHUB_APP_ID", 0)),
+			AppPrivateKeyPath: getEnv("GITHUB_APP_PRIVATE_KEY_PATH", ""),
+			AppInstallationID: int64(getEnvInt("GITHUB_APP_INSTALLATION_ID", 0)),
 		},
 		CORS: CORSConfig{
 			AllowOrigins: strings.Split(getEnv("ALLOW_ORIGINS", "*"), ","),
 		},
+		Badges: BadgeConfig{
+			CacheMaxAge:    getEnvInt("BADGE_CACHE_MAX_AGE", 3600),
+			FallbackMaxAge: getEnvInt("BADGE_FALLBACK_MAX_AGE", 60),
+			PublicRepos:    splitList(getEnv("PUBLIC_REPOS", "")),
+		},
+		Webhooks: WebhookConfig{
+			Secret:      getEnv("GITHUB_WEBHOOK_SECRET", ""),
+			PublicURL:   getEnv("WEBHOOK_PUBLIC_URL", ""),
+			DeliveryTTL: getEnvDuration("WEBHOOK_DELIVERY_TTL", 24*time.Hour),
+		},
+		Fanout: FanoutConfig{
+			StatePath:      getEnv("FANOUT_STATE_PATH", "data/fanout.json"),
+			Workers:        getEnvInt("FANOUT_WORKERS", 4),
+			MaxAttempts:    getEnvInt("FANOUT_MAX_ATTEMPTS", 8),
+			InitialBackoff: getEnvDuration("FANOUT_INITIAL_BACKOFF", 2*time.Second),
+			MaxBackoff:     getEnvDuration("FANOUT_MAX_BACKOFF", 10*time.Minute),
+			Timeout:        getEnvDuration("FANOUT_TIMEOUT", 10*time.Second),
+			MaxDeadLetters: getEnvInt("FANOUT_MAX_DEAD_LETTERS", 1000),
+			DeadLetterTTL:  getEnvDuration("FANOUT_DEAD_LETTER_TTL", 7*24*time.Hour),
+		},
+		Streams: StreamConfig{
+			Source:            getEnv("STREAM_SOURCE", "auto"),
+			PollInterval:      getEnvPositiveDuration("STREAM_POLL_INTERVAL", 60*time.Second),
+			HeartbeatInterval: getEnvPositiveDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+			HistorySize:       getEnvPositiveInt("STREAM_HISTORY_SIZE", 500),
+			BufferSize:        getEnvPositiveInt("STREAM_BUFFER_SIZE", 64),
+			ClientBufferSize:  getEnvPositiveInt("STREAM_CLIENT_BUFFER_SIZE", 256),
+			DropPolicy:        getEnv("STREAM_DROP_POLICY", "drop_oldest"),
+		},
+		Auth: AuthConfig{
+			APIKeysPath:            getEnv("AUTH_API_KEYS_PATH", ""),
+			DefaultRotationOverlap: getEnvDuration("AUTH_ROTATION_OVERLAP", 24*time.Hour),
+			JWKS:                   getEnv("AUTH_JWKS", ""),
+			JWTIssuer:              getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience:            getEnv("AUTH_JWT_AUDIENCE", ""),
+			JWTGroupsClaim:         getEnv("AUTH_JWT_GROUPS_CLAIM", "groups"),
+			JWTGroupScopes:         getEnv("AUTH_JWT_GROUP_SCOPES", ""),
+			JWKSCacheTTL:           getEnvDuration("AUTH_JWKS_CACHE_TTL", time.Hour),
+			JWTLeeway:              getEnvDuration("AUTH_JWT_LEEWAY", 30*time.Second),
+		},
+		Policy: PolicyConfig{
+			Path: getEnv("POLICY_PATH", ""),
+		},
+		OAuth: OAuthConfig{
+			ClientID:      getEnv("OAUTH_CLIENT_ID", ""),
+			ClientSecret:  getEnv("OAUTH_CLIENT_SECRET", ""),
+			RedirectURL:   getEnv("OAUTH_REDIRECT_URL", "http://localhost:8080/auth/github/callback"),
+			Scopes:        strings.Split(getEnv("OAUTH_SCOPES", "public_repo"), ","),
+			SessionSecret: getEnv("OAUTH_SESSION_SECRET", ""),
+			SessionTTL:    getEnvDuration("OAUTH_SESSION_TTL", 8*time.Hour),
+			SessionScopes: strings.Split(getEnv("OAUTH_SESSION_SCOPES", "read,issues:write"), ","),
+		},
+		RateLimit: RateLimitConfig{
+			PerMinute:  getEnvInt("RATE_LIMIT_PER_MINUTE", 120),
+			Burst:      getEnvInt("RATE_LIMIT_BURST", 30),
+			DailyQuota: getEnvInt("RATE_LIMIT_DAILY_QUOTA", 0),
+			QuotaPath:  getEnv("RATE_LIMIT_QUOTA_PATH", "data/quotas.json"),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		Feedback: FeedbackConfig{
+			Repos:          splitList(getEnv("FEEDBACK_REPOS", "")),
+			Labels:         splitList(getEnv("FEEDBACK_LABELS", "feedback")),
+			FormSecret:     getEnv("FEEDBACK_FORM_SECRET", ""),
+			MinFillTime:    getEnvDuration("FEEDBACK_MIN_FILL_TIME", 3*time.Second),
+			FormMaxAge:     getEnvDuration("FEEDBACK_FORM_MAX_AGE", time.Hour),
+			PerMinute:      getEnvPositiveInt("FEEDBACK_PER_MINUTE", 1),
+			Burst:          getEnvPositiveInt("FEEDBACK_BURST", 5),
+			MaxTitleLength: getEnvInt("FEEDBACK_MAX_TITLE_LENGTH", 200),
+			MaxBodyLength:  getEnvInt("FEEDBACK_MAX_BODY_LENGTH", 5000),
+			Captcha:        getEnv("FEEDBACK_CAPTCHA", ""),
+			CaptchaSecret:  getEnv("FEEDBACK_CAPTCHA_SECRET", ""),
+		},
+		Attachments: AttachmentsConfig{
+			Store:             getEnv("ATTACHMENTS_STORE", ""),
+			PublicURL:         getEnv("ATTACHMENTS_PUBLIC_URL", ""),
+			LocalDir:          getEnv("ATTACHMENTS_LOCAL_DIR", "data/attachments"),
+			S3Endpoint:        getEnv("ATTACHMENTS_S3_ENDPOINT", ""),
+			S3Bucket:          getEnv("ATTACHMENTS_S3_BUCKET", ""),
+			S3Region:          getEnv("ATTACHMENTS_S3_REGION", "us-east-1"),
+			S3AccessKeyID:     getEnv("ATTACHMENTS_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("ATTACHMENTS_S3_SECRET_ACCESS_KEY", ""),
+			MaxFileSize:       int64(getEnvInt("ATTACHMENTS_MAX_FILE_SIZE", 10<<20)),
+			MaxFiles:          getEnvInt("ATTACHMENTS_MAX_FILES", 5),
+			AllowedTypes:      splitList(getEnv("ATTACHMENTS_ALLOWED_TYPES", "image/png,image/jpeg,image/gif,image/webp,text/plain,application/zip,application/x-gzip")),
+		},
+		Jobs: JobsConfig{
+			DBPath:         getEnv("JOBS_DB_PATH", "data/jobs.db"),
+			Workers:        getEnvInt("JOBS_WORKERS", 2),
+			Retention:      getEnvDuration("JOBS_RETENTION", 7*24*time.Hour),
+			MaxAttempts:    getEnvInt("JOBS_MAX_ATTEMPTS", 3),
+			InitialBackoff: getEnvDuration("JOBS_INITIAL_BACKOFF", 30*time.Second),
+			MaxBackoff:     getEnvDuration("JOBS_MAX_BACKOFF", 30*time.Minute),
+		},
+		BulkIssues: BulkIssuesConfig{
+			MaxRows:          getEnvInt("BULK_ISSUES_MAX_ROWS", 1000),
+			Interval:         getEnvDuration("BULK_ISSUES_INTERVAL", time.Second),
+			RateLimitBackoff: getEnvDuration("BULK_ISSUES_RATE_LIMIT_BACKOFF", time.Minute),
+		},
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 
 	// Validate required configuration
-	if config.GitHub.Token == "" {
-		logrus.Fatal("GITHUB_TOKEN is required")
+	if config.GitHub.AppID != 0 {
+		if config.GitHub.AppPrivateKeyPath == "" {
+			logrus.Fatal("GITHUB_APP_PRIVATE_KEY_PATH is required with GITHUB_APP_ID")
+		}
+	} else if config.GitHub.Token == "" && len(config.GitHub.Tokens) == 0 {
+		logrus.Fatal("GITHUB_TOKEN, GITHUB_TOKENS or GITHUB_APP_ID is required")
 	}
 
 	if config.GitHub.Username == "" {
 		logrus.Fatal("GITHUB_USERNAME is required")
 	}
 
+	switch config.GitHub.TokenCheck {
+	case "off", "warn", "strict":
+	default:
+		logrus.WithField("value", config.GitHub.TokenCheck).Fatal("GITHUB_TOKEN_CHECK must be off, warn or strict")
+	}
+
 	return config
 }
 
@@ -76,3 +387,65 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// splitList splits a comma separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.WithField("key", key).Warn("Invalid integer value, using default")
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvPositiveInt gets an integer environment variable like getEnvInt,
+// returning the default for values below 1, such as buffer and history sizes
+func getEnvPositiveInt(key string, defaultValue int) int {
+	value := getEnvInt(key, defaultValue)
+	if value < 1 {
+		logrus.WithField("key", key).Warn("Integer must be at least 1, using default")
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.WithField("key", key).Warn("Invalid duration value, using default")
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvPositiveDuration gets a duration environment variable like
+// getEnvDuration, returning the default for values that are not positive, such
+// as intervals used to start tickers
+func getEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnvDuration(key, defaultValue)
+	if value <= 0 {
+		logrus.WithField("key", key).Warn("Duration must be positive, using default")
+		return defaultValue
+	}
+	return value
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
@@ -32,11 +34,16 @@ func TestAPIIntegration(t *testing.T) {
 	// Set up the test environment
 	setupIntegrationTestEnv()
 
-	// Load the configuration
+	// Load the configuration, keeping state out of the working tree so that a
+	// running dev server's files are not locked or overwritten
 	cfg := config.LoadConfig()
+	cfg.Jobs.DBPath = filepath.Join(t.TempDir(), "jobs.db")
+	cfg.Fanout.StatePath = filepath.Join(t.TempDir(), "fanout.json")
+	cfg.RateLimit.QuotaPath = filepath.Join(t.TempDir(), "quotas.json")
 
 	// Set up the router
-	router := routes.SetupRouter(cfg)
+	router, shutdown := routes.SetupRouter(cfg)
+	defer shutdown()
 
 	// Test health check endpoint
 	t.Run("Health Check", func(t *testing.T) {
@@ -102,3 +109,44 @@ func TestAPIIntegration(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.Code)
 	})
 }
+
+// TestSpoofedForwardedFor tests that unauthenticated clients cannot escape
+// the rate limit by sending a new X-Forwarded-For with every request
+func TestSpoofedForwardedFor(t *testing.T) {
+	setupIntegrationTestEnv()
+
+	cfg := config.LoadConfig()
+	cfg.RateLimit.Burst = 10
+	cfg.RateLimit.DailyQuota = 0
+	cfg.Fanout.StatePath = filepath.Join(t.TempDir(), "fanout.json")
+
+	// Test cases
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		expectedStatus int
+	}{
+		{name: "No Trusted Proxies", expectedStatus: http.StatusTooManyRequests},
+		{name: "Trusted Proxy", trustedProxies: []string{"192.0.2.1"}, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg.Server.TrustedProxies = tc.trustedProxies
+			cfg.Jobs.DBPath = filepath.Join(t.TempDir(), "jobs.db")
+			router, shutdown := routes.SetupRouter(cfg)
+			defer shutdown()
+
+			var resp *httptest.ResponseRecorder
+			for i := 0; i <= cfg.RateLimit.Burst; i++ {
+				req := httptest.NewRequest("GET", "/feedback/unknown-repo/form", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				req.Header.Set("X-Forwarded-For", "198.51.100."+strconv.Itoa(i))
+				resp = httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+			}
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+		})
+	}
+}
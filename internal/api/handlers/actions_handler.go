@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// workflowRunStatuses lists the status values accepted by the workflow runs filter
+var workflowRunStatuses = map[string]bool{
+	"completed": true, "action_required": true, "cancelled": true, "failure": true,
+	"neutral": true, "skipped": true, "stale": true, "success": true, "timed_out": true,
+	"in_progress": true, "queued": true, "requested": true, "waiting": true, "pending": true,
+}
+
+// ActionsHandler handles GitHub Actions API requests
+type ActionsHandler struct {
+	service services.GitHubServiceInterface
+}
+
+// NewActionsHandler creates a new ActionsHandler
+func NewActionsHandler(service services.GitHubServiceInterface) *ActionsHandler {
+	return &ActionsHandler{
+		service: service,
+	}
+}
+
+// ListWorkflows handles GET /github/:repo/actions/workflows
+func (h *ActionsHandler) ListWorkflows(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	workflows, err := callerService(c, h.service).ListWorkflows(repoName)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list workflows")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve workflows",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, workflows)
+}
+
+// ListWorkflowRuns handles GET /github/:repo/actions/runs
+func (h *ActionsHandler) ListWorkflowRuns(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var filter models.WorkflowRunFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: malformed filter",
+		})
+		return
+	}
+
+	if filter.Status != "" && !workflowRunStatuses[filter.Status] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: unknown status %q", filter.Status),
+		})
+		return
+	}
+
+	runs, err := callerService(c, h.service).ListWorkflowRuns(repoName, &filter)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list workflow runs")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve workflow runs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// ListWorkflowRunJobs handles GET /github/:repo/actions/runs/:run_id/jobs
+func (h *ActionsHandler) ListWorkflowRunJobs(c *gin.Context) {
+	repoName := c.Param("repo")
+	runID, ok := parseIDParam(c, "run_id")
+	if !ok {
+		return
+	}
+
+	jobs, err := callerService(c, h.service).ListWorkflowRunJobs(repoName, runID)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list workflow run jobs")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve workflow run jobs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// DownloadWorkflowRunLogs handles GET /github/:repo/actions/runs/:run_id/logs
+func (h *ActionsHandler) DownloadWorkflowRunLogs(c *gin.Context) {
+	repoName := c.Param("repo")
+	runID, ok := parseIDParam(c, "run_id")
+	if !ok {
+		return
+	}
+
+	logs, err := callerService(c, h.service).DownloadWorkflowRunLogs(repoName, runID)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to download workflow run logs")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to download workflow run logs",
+		})
+		return
+	}
+	defer logs.Close()
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-run-%d-logs.zip"`, repoName, runID))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, logs); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Warn("Workflow run log stream interrupted")
+	}
+}
+
+// RerunFailedJobs handles POST /github/:repo/actions/runs/:run_id/rerun-failed-jobs
+func (h *ActionsHandler) RerunFailedJobs(c *gin.Context) {
+	repoName := c.Param("repo")
+	runID, ok := parseIDParam(c, "run_id")
+	if !ok {
+		return
+	}
+
+	if err := callerService(c, h.service).RerunFailedJobs(repoName, runID); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to re-run failed jobs")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to re-run failed jobs",
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// CancelWorkflowRun handles POST /github/:repo/actions/runs/:run_id/cancel
+func (h *ActionsHandler) CancelWorkflowRun(c *gin.Context) {
+	repoName := c.Param("repo")
+	runID, ok := parseIDParam(c, "run_id")
+	if !ok {
+		return
+	}
+
+	if err := callerService(c, h.service).CancelWorkflowRun(repoName, runID); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to cancel workflow run")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to cancel workflow run",
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// DispatchWorkflow handles POST /github/:repo/actions/workflows/:workflow/dispatches
+func (h *ActionsHandler) DispatchWorkflow(c *gin.Context) {
+	repoName := c.Param("repo")
+	workflow := c.Param("workflow")
+
+	var dispatch models.WorkflowDispatchRequest
+	if err := c.ShouldBindJSON(&dispatch); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: ref is required",
+		})
+		return
+	}
+
+	if err := callerService(c, h.service).DispatchWorkflow(repoName, workflow, &dispatch); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to dispatch workflow")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to dispatch workflow",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestListWorkflowRuns tests the ListWorkflowRuns handler
+func TestListWorkflowRuns(t *testing.T) {
+	mockRuns := &models.WorkflowRunList{
+		TotalCount:   1,
+		WorkflowRuns: []models.WorkflowRun{{ID: 42, HeadBranch: "main", Status: "completed"}},
+	}
+
+	// Test cases
+	tests := []struct {
+		name               string
+		query              string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:  "Success With Filters",
+			query: "?branch=main&status=completed&event=push",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("ListWorkflowRuns", "test-repo", mock.MatchedBy(func(filter *models.WorkflowRunFilter) bool {
+					return filter.Branch == "main" && filter.Status == "completed" && filter.Event == "push"
+				})).Return(mockRuns, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "Invalid Status",
+			query: "?status=exploded",
+			setupMock: func(mockService *MockGitHubService) {
+				// No mock setup needed as the request will be rejected by the handler
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Repository Not Found",
+			query: "",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("ListWorkflowRuns", "test-repo", mock.Anything).Return(nil, &services.APIError{StatusCode: http.StatusNotFound})
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewActionsHandler(mockService)
+			router.GET("/github/:repo/actions/runs", handler.ListWorkflowRuns)
+
+			req, _ := http.NewRequest("GET", "/github/test-repo/actions/runs"+tc.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				var response models.WorkflowRunList
+				err := json.Unmarshal(resp.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(42), response.WorkflowRuns[0].ID)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDownloadWorkflowRunLogs tests that logs are streamed through as a zip attachment
+func TestDownloadWorkflowRunLogs(t *testing.T) {
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+	mockService.On("DownloadWorkflowRunLogs", "test-repo", int64(42)).
+		Return(io.NopCloser(strings.NewReader("PK-archive")), nil)
+
+	handler := NewActionsHandler(mockService)
+	router.GET("/github/:repo/actions/runs/:run_id/logs", handler.DownloadWorkflowRunLogs)
+
+	// Valid run ID
+	req, _ := http.NewRequest("GET", "/github/test-repo/actions/runs/42/logs", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/zip", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "PK-archive", resp.Body.String())
+
+	// Invalid run ID
+	req, _ = http.NewRequest("GET", "/github/test-repo/actions/runs/latest/logs", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestDispatchWorkflow tests the DispatchWorkflow handler
+func TestDispatchWorkflow(t *testing.T) {
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+	mockService.On("DispatchWorkflow", "test-repo", "deploy.yml", mock.MatchedBy(func(dispatch *models.WorkflowDispatchRequest) bool {
+		return dispatch.Ref == "main" && dispatch.Inputs["environment"] == "staging"
+	})).Return(nil)
+
+	handler := NewActionsHandler(mockService)
+	router.POST("/github/:repo/actions/workflows/:workflow/dispatches", handler.DispatchWorkflow)
+
+	// Valid dispatch
+	body, _ := json.Marshal(map[string]interface{}{
+		"ref":    "main",
+		"inputs": map[string]string{"environment": "staging"},
+	})
+	req, _ := http.NewRequest("POST", "/github/test-repo/actions/workflows/deploy.yml/dispatches", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// Missing ref
+	req, _ = http.NewRequest("POST", "/github/test-repo/actions/workflows/deploy.yml/dispatches", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// apiKeyRequest represents a request to create an API key
+type apiKeyRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,oneof=read issues:write admin"`
+	ExpiresIn string   `json:"expires_in"`
+}
+
+// rotateAPIKeyRequest represents a request to rotate an API key
+type rotateAPIKeyRequest struct {
+	Overlap string `json:"overlap"`
+}
+
+// apiKeyResponse represents an API key without its hash. Key is only set
+// when the key is created or rotated.
+type apiKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RotatedTo string     `json:"rotated_to,omitempty"`
+	Key       string     `json:"key,omitempty"`
+}
+
+func newAPIKeyResponse(key *auth.APIKey, token string) apiKeyResponse {
+	return apiKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		RotatedTo: key.RotatedTo,
+		Key:       token,
+	}
+}
+
+// APIKeyHandler manages the API keys that authenticate callers
+type APIKeyHandler struct {
+	keys           *auth.KeyStore
+	defaultOverlap time.Duration
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(keys *auth.KeyStore, defaultOverlap time.Duration) *APIKeyHandler {
+	return &APIKeyHandler{
+		keys:           keys,
+		defaultOverlap: defaultOverlap,
+	}
+}
+
+// CreateKey handles POST /auth/keys
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var request apiKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: name and scopes (read, issues:write, admin) are required",
+		})
+		return
+	}
+
+	ttl, ok := parseDurationField(c, "expires_in", request.ExpiresIn, 0)
+	if !ok {
+		return
+	}
+
+	key, token, err := h.keys.CreateKey(request.Name, request.Scopes, ttl)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create API key")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create API key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAPIKeyResponse(key, token))
+}
+
+// ListKeys handles GET /auth/keys
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys := h.keys.ListKeys()
+	response := make([]apiKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, newAPIKeyResponse(&keys[i], ""))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RotateKey handles POST /auth/keys/:id/rotate
+func (h *APIKeyHandler) RotateKey(c *gin.Context) {
+	var request rotateAPIKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request format",
+			})
+			return
+		}
+	}
+
+	overlap, ok := parseDurationField(c, "overlap", request.Overlap, h.defaultOverlap)
+	if !ok {
+		return
+	}
+
+	key, token, err := h.keys.RotateKey(c.Param("id"), overlap)
+	if errors.Is(err, auth.ErrKeyNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "API key not found",
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("key_id", c.Param("id")).Error("Failed to rotate API key")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to rotate API key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAPIKeyResponse(key, token))
+}
+
+// RevokeKey handles DELETE /auth/keys/:id
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	err := h.keys.RevokeKey(c.Param("id"))
+	if errors.Is(err, auth.ErrKeyNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "API key not found",
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("key_id", c.Param("id")).Error("Failed to revoke API key")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to revoke API key",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseDurationField parses an optional duration such as "24h" from a request
+// body, writing a 400 response when it is invalid
+func parseDurationField(c *gin.Context, field, value string, defaultValue time.Duration) (time.Duration, bool) {
+	if value == "" {
+		return defaultValue, true
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid " + field + ": must be a duration such as 24h",
+		})
+		return 0, false
+	}
+	return d, true
+}
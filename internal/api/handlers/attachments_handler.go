@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/attachments"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentsHandler serves the files kept by a local attachment store
+type AttachmentsHandler struct {
+	store *attachments.LocalStore
+}
+
+// NewAttachmentsHandler creates a new AttachmentsHandler
+func NewAttachmentsHandler(store *attachments.LocalStore) *AttachmentsHandler {
+	return &AttachmentsHandler{
+		store: store,
+	}
+}
+
+// GetAttachment handles GET /attachments/*key
+func (h *AttachmentsHandler) GetAttachment(c *gin.Context) {
+	path, err := h.store.Path(c.Param("key"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Attachment not found",
+		})
+		return
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Attachment not found",
+		})
+		return
+	}
+
+	// Stored files never change, and browsers must not reinterpret them as pages
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; sandbox")
+	c.File(path)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/attachments"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateIssueWithAttachments tests multipart issue creation
+func TestCreateIssueWithAttachments(t *testing.T) {
+	store, err := attachments.NewLocalStore(t.TempDir(), "http://localhost:8080/attachments")
+	require.NoError(t, err)
+	uploader := attachments.NewUploader(store, config.AttachmentsConfig{
+		MaxFileSize:  1024,
+		MaxFiles:     2,
+		AllowedTypes: []string{"image/png", "text/plain"},
+	})
+
+	// Test cases
+	tests := []struct {
+		name               string
+		uploader           *attachments.Uploader
+		files              map[string]string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:     "Success",
+			uploader: uploader,
+			files:    map[string]string{"app.log": "panic: boom"},
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateIssue", "test-repo", mock.MatchedBy(func(req *models.IssueRequest) bool {
+					return req.Title == "Crash" && strings.HasPrefix(req.Body, "It crashed\n\n### Attachments") &&
+						strings.Contains(req.Body, "- [app.log](http://localhost:8080/attachments/test-repo/")
+				})).Return(&models.IssueResponse{Number: 1}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "Type Not Allowed",
+			uploader:           uploader,
+			files:              map[string]string{"page.html": "<html><body>hi</body></html>"},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Request Too Large",
+			uploader:           uploader,
+			files:              map[string]string{"app.log": strings.Repeat("a", 4<<20)},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:               "Attachments Disabled",
+			files:              map[string]string{"app.log": "panic: boom"},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewGitHubHandler(mockService, tc.uploader)
+			router.POST("/github/:repo/issues", handler.CreateIssue)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			require.NoError(t, writer.WriteField("title", "Crash"))
+			require.NoError(t, writer.WriteField("body", "It crashed"))
+			for name, content := range tc.files {
+				part, err := writer.CreateFormFile("attachments", name)
+				require.NoError(t, err)
+				_, err = part.Write([]byte(content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/issues", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetAttachment tests serving files from the local store
+func TestGetAttachment(t *testing.T) {
+	store, err := attachments.NewLocalStore(t.TempDir(), "http://localhost:8080/attachments")
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), "test-repo/abc/app.log", "text/plain", []byte("panic: boom"))
+	require.NoError(t, err)
+
+	router := SetupTestRouter()
+	router.GET("/attachments/*key", NewAttachmentsHandler(store).GetAttachment)
+
+	// Test cases
+	tests := []struct {
+		name               string
+		path               string
+		expectedStatusCode int
+	}{
+		{name: "Success", path: "/attachments/test-repo/abc/app.log", expectedStatusCode: http.StatusOK},
+		{name: "Missing File", path: "/attachments/test-repo/abc/other.log", expectedStatusCode: http.StatusNotFound},
+		{name: "Directory", path: "/attachments/test-repo/abc", expectedStatusCode: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, "panic: boom", resp.Body.String())
+				assert.Equal(t, "nosniff", resp.Header().Get("X-Content-Type-Options"))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/badges"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// BadgeHandler renders SVG badges and cards from GitHub data
+type BadgeHandler struct {
+	service services.GitHubServiceInterface
+	config  config.BadgeConfig
+}
+
+// NewBadgeHandler creates a new BadgeHandler
+func NewBadgeHandler(service services.GitHubServiceInterface, config config.BadgeConfig) *BadgeHandler {
+	return &BadgeHandler{
+		service: service,
+		config:  config,
+	}
+}
+
+// GetRepositoryBadge handles GET /badges/:repo/:metric.svg
+func (h *BadgeHandler) GetRepositoryBadge(c *gin.Context) {
+	repoName := c.Param("repo")
+	metricName, ok := strings.CutSuffix(c.Param("metric"), ".svg")
+	metric, known := badges.Metrics[metricName]
+	if !ok || !known {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Unknown badge metric",
+		})
+		return
+	}
+
+	theme := badges.ThemeByName(c.Query("theme"))
+	label := c.DefaultQuery("label", metric.Label)
+
+	repo, err := h.service.GetRepository(repoName)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Warn("Serving fallback badge")
+		h.writeSVG(c, badges.RenderFallbackBadge(label, theme), h.config.FallbackMaxAge)
+		return
+	}
+
+	h.writeSVG(c, badges.RenderBadge(label, metric.Value(repo), theme), h.config.CacheMaxAge)
+}
+
+// GetProfileCard handles GET /cards/profile.svg
+func (h *BadgeHandler) GetProfileCard(c *gin.Context) {
+	theme := badges.ThemeByName(c.Query("theme"))
+
+	profile, err := h.service.GetUserProfile()
+	if err != nil {
+		logrus.WithError(err).Warn("Serving fallback profile card")
+		h.writeSVG(c, badges.RenderFallbackCard(theme), h.config.FallbackMaxAge)
+		return
+	}
+
+	h.writeSVG(c, badges.RenderProfileCard(profile.User, profile.Repositories, theme), h.config.CacheMaxAge)
+}
+
+// writeSVG writes an SVG image with cache headers. Fallback images are still
+// served with 200 so that embedding pages don't show a broken image.
+func (h *BadgeHandler) writeSVG(c *gin.Context, svg []byte, maxAge int) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", maxAge, maxAge))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetRepositoryBadge tests the GetRepositoryBadge handler
+func TestGetRepositoryBadge(t *testing.T) {
+	badgeConfig := config.BadgeConfig{CacheMaxAge: 3600, FallbackMaxAge: 60}
+
+	// Test cases
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+		expectedBody       string
+		expectedCache      string
+	}{
+		{
+			name: "Stars Badge",
+			path: "/badges/test-repo/stars.svg",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetRepository", "test-repo").Return(&models.Repository{StargazersCount: 1234}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "1.2k",
+			expectedCache:      "public, max-age=3600, s-maxage=3600",
+		},
+		{
+			name: "Fallback On Upstream Error",
+			path: "/badges/test-repo/forks.svg?theme=dark",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetRepository", "test-repo").Return(nil, errors.New("service error"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "unavailable",
+			expectedCache:      "public, max-age=60, s-maxage=60",
+		},
+		{
+			name: "Unknown Metric",
+			path: "/badges/test-repo/downloads.svg",
+			setupMock: func(mockService *MockGitHubService) {
+				// No mock setup needed as the request will be rejected by the handler
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "Missing SVG Extension",
+			path: "/badges/test-repo/stars",
+			setupMock: func(mockService *MockGitHubService) {
+				// No mock setup needed as the request will be rejected by the handler
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewBadgeHandler(mockService, badgeConfig)
+			router.GET("/badges/:repo/:metric", handler.GetRepositoryBadge)
+
+			// Perform the request
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			// Check the response
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, "image/svg+xml; charset=utf-8", resp.Header().Get("Content-Type"))
+				assert.Equal(t, tc.expectedCache, resp.Header().Get("Cache-Control"))
+				assert.Contains(t, resp.Body.String(), tc.expectedBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetProfileCard tests the GetProfileCard handler
+func TestGetProfileCard(t *testing.T) {
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+	mockService.On("GetUserProfile").Return(&models.GithubProfile{
+		User:         models.UserResponse{Login: "test-user"},
+		Repositories: []models.Repository{{Language: "Go"}},
+	}, nil)
+
+	handler := NewBadgeHandler(mockService, config.BadgeConfig{CacheMaxAge: 3600})
+	router.GET("/cards/profile.svg", handler.GetProfileCard)
+
+	req, _ := http.NewRequest("GET", "/cards/profile.svg", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Top Languages")
+	mockService.AssertExpectations(t)
+}
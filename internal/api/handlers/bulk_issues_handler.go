@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/bulkissues"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBulkRequestSize bounds the CSV or JSON of a bulk import
+const maxBulkRequestSize = 5 << 20
+
+// BulkIssuesHandler queues jobs that create many issues at once
+type BulkIssuesHandler struct {
+	jobs    *jobs.Manager
+	maxRows int
+	rowCost int
+}
+
+// NewBulkIssuesHandler creates a new BulkIssuesHandler. Each row is charged
+// rowCost against the caller's daily quota, as creating the issue one at a
+// time would be; the token bucket is not, as the job paces creation itself.
+func NewBulkIssuesHandler(manager *jobs.Manager, maxRows, rowCost int) *BulkIssuesHandler {
+	return &BulkIssuesHandler{
+		jobs:    manager,
+		maxRows: maxRows,
+		rowCost: rowCost,
+	}
+}
+
+// MaxRequestSize returns the largest bulk import accepted
+func (h *BulkIssuesHandler) MaxRequestSize() int64 {
+	return maxBulkRequestSize
+}
+
+// CreateIssues handles POST /github/:repo/issues/bulk. Every row is validated
+// before the job is queued; the job creates the issues with the service's
+// own token and its progress is reported at /jobs/:id. The optional run_at
+// (RFC 3339) or delay (e.g. 30m) query parameter postpones the import.
+//
+// Jobs outlive requests and are persisted, so they cannot act as a user
+// signed in through OAuth without storing the user's token. Session callers
+// are refused rather than having issues authored by the service account.
+func (h *BulkIssuesHandler) CreateIssues(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	if middleware.GetGitHubToken(c) != "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: "Bulk imports cannot be made as a signed-in GitHub user, as they would be authored by the service account; use an API key or SSO token instead",
+		})
+		return
+	}
+
+	runAt, err := scheduledTime(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	if contentType := c.ContentType(); contentType != gin.MIMEJSON && contentType != "text/csv" {
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Error: "Bulk imports must be application/json or text/csv",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkRequestSize)
+	issues, err := bulkissues.Parse(c.ContentType(), c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Error: "Bulk import is too large",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	if problems := bulkissues.Validate(issues, h.maxRows); len(problems) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, models.BulkValidationResponse{
+			Error: "Invalid rows; no issues were created",
+			Rows:  problems,
+		})
+		return
+	}
+
+	if !middleware.ChargeQuota(c, h.rowCost*len(issues)) {
+		return
+	}
+
+	opts := jobs.Options{RunAt: runAt, Repo: repoName}
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		opts.Owner = principal.ID
+	}
+	job, err := h.jobs.Schedule(bulkissues.JobType, bulkissues.Payload{Repo: repoName, Issues: issues}, len(issues), opts)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to queue bulk issue job")
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: "Failed to queue bulk import",
+		})
+		return
+	}
+
+	c.Header("Location", "/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// scheduledTime returns when a job should run from the run_at or delay query
+// parameter, or the zero time to run it straight away
+func scheduledTime(c *gin.Context) (time.Time, error) {
+	runAt, delay := c.Query("run_at"), c.Query("delay")
+	switch {
+	case runAt != "" && delay != "":
+		return time.Time{}, errors.New("use either run_at or delay")
+	case runAt != "":
+		t, err := time.Parse(time.RFC3339, runAt)
+		if err != nil {
+			return time.Time{}, errors.New("run_at must be an RFC 3339 time")
+		}
+		return t, nil
+	case delay != "":
+		d, err := time.ParseDuration(delay)
+		if err != nil || d < 0 {
+			return time.Time{}, errors.New("delay must be a positive duration such as 30m")
+		}
+		return time.Now().Add(d), nil
+	}
+	return time.Time{}, nil
+}
@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/bulkissues"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateBulkIssues tests validating and queueing bulk imports
+func TestCreateBulkIssues(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		query              string
+		contentType        string
+		body               string
+		expectedStatusCode int
+		expectedRows       int
+	}{
+		{
+			name:               "CSV",
+			contentType:        "text/csv",
+			body:               "title,labels\nFix login,\"bug,auth\"\nUpdate docs,\n",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "JSON",
+			contentType:        "application/json",
+			body:               `[{"title":"Fix login","body":"Steps"}]`,
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Delayed",
+			query:              "?delay=30m",
+			contentType:        "text/csv",
+			body:               "title\nFix login\n",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Scheduled",
+			query:              "?run_at=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			contentType:        "text/csv",
+			body:               "title\nFix login\n",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Invalid Schedule",
+			query:              "?run_at=tomorrow",
+			contentType:        "text/csv",
+			body:               "title\nFix login\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid Rows",
+			contentType:        "text/csv",
+			body:               "title,body\n,No title\nFix login,\n,Also no title\n",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedRows:       2,
+		},
+		{
+			name:               "Too Many Rows",
+			contentType:        "application/json",
+			body:               `[{"title":"a"},{"title":"b"},{"title":"c"},{"title":"d"}]`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedRows:       1,
+		},
+		{
+			name:               "Malformed CSV",
+			contentType:        "text/csv",
+			body:               "title,priority\nFix login,high\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Unsupported Content Type",
+			contentType:        "application/xml",
+			body:               "<issues/>",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour})
+			require.NoError(t, err)
+			manager.Register(bulkissues.JobType, func(ctx context.Context, job *jobs.Job, run *jobs.Run) error { return nil })
+
+			handler := NewBulkIssuesHandler(manager, 3, 10)
+			router.POST("/github/:repo/issues/bulk", handler.CreateIssues)
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/issues/bulk"+tc.query, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+
+			switch tc.expectedStatusCode {
+			case http.StatusAccepted:
+				var job jobs.Job
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+				assert.Equal(t, jobs.StatusQueued, job.Status)
+				assert.Equal(t, "/jobs/"+job.ID, resp.Header().Get("Location"))
+				assert.Equal(t, tc.query != "", job.RunAt != nil)
+			case http.StatusUnprocessableEntity:
+				var response models.BulkValidationResponse
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+				assert.Len(t, response.Rows, tc.expectedRows)
+			}
+		})
+	}
+}
+
+// TestCreateBulkIssuesQuota tests that bulk imports are charged per row against the daily quota
+func TestCreateBulkIssuesQuota(t *testing.T) {
+	quotas, err := ratelimit.NewQuotaStore(filepath.Join(t.TempDir(), "quotas.json"), 25)
+	require.NoError(t, err)
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour})
+	require.NoError(t, err)
+	manager.Register(bulkissues.JobType, func(ctx context.Context, job *jobs.Job, run *jobs.Run) error { return nil })
+
+	router := SetupTestRouter()
+	router.Use(middleware.RateLimit(ratelimit.NewLimiter(600, 100), quotas, ratelimit.RouteCosts{
+		"POST /github/:repo/issues/bulk": 1,
+	}))
+	handler := NewBulkIssuesHandler(manager, 10, 10)
+	router.POST("/github/:repo/issues/bulk", handler.CreateIssues)
+
+	send := func(rows int) *httptest.ResponseRecorder {
+		body := "title\n" + strings.Repeat("Fix login\n", rows)
+		req, _ := http.NewRequest("POST", "/github/test-repo/issues/bulk", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Three rows cost 30, more than the whole quota
+	resp := send(3)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Daily quota exceeded")
+
+	resp = send(2)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "3", resp.Header().Get("RateLimit-Remaining"))
+
+	resp = send(1)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+}
+
+// TestCreateBulkIssuesSession tests that callers signed in through OAuth cannot start bulk imports
+func TestCreateBulkIssuesSession(t *testing.T) {
+	sessions, err := auth.NewSessionManager(strings.Repeat("s", 32), time.Hour, false, nil)
+	require.NoError(t, err)
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour})
+	require.NoError(t, err)
+	manager.Register(bulkissues.JobType, func(ctx context.Context, job *jobs.Job, run *jobs.Run) error { return nil })
+
+	router := SetupTestRouter()
+	router.Use(middleware.Session(sessions))
+	handler := NewBulkIssuesHandler(manager, 10, 10)
+	router.POST("/github/:repo/issues/bulk", handler.CreateIssues)
+
+	cookie := httptest.NewRecorder()
+	require.NoError(t, sessions.SetCookie(cookie, sessions.NewSession("octocat", "gho_user")))
+
+	req, _ := http.NewRequest("POST", "/github/test-repo/issues/bulk", bytes.NewBufferString("title\nFix login\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Cookie", cookie.Header().Get("Set-Cookie"))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "service account")
+}
+
+// TestGetJob tests reporting the progress and failures of a job
+func TestGetJob(t *testing.T) {
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour})
+	require.NoError(t, err)
+	manager.Register("import", func(ctx context.Context, job *jobs.Job, run *jobs.Run) error {
+		run.Record(jobs.Result{Item: 1, Label: "Fix login", Status: jobs.StatusSucceeded})
+		run.Record(jobs.Result{Item: 2, Label: "Update docs", Status: jobs.StatusFailed, Error: "Validation Failed"})
+		return nil
+	})
+	manager.Start()
+	defer manager.Stop()
+
+	job, err := manager.Enqueue("import", nil, 2)
+	require.NoError(t, err)
+	delayed, err := manager.Schedule("import", nil, 2, jobs.Options{RunAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		current, err := manager.Get(job.ID)
+		return err == nil && current.Status == jobs.StatusSucceeded
+	}, time.Second, 5*time.Millisecond)
+
+	router := SetupTestRouter()
+	handler := NewJobsHandler(manager, nil)
+	router.GET("/jobs/:id", handler.GetJob)
+	router.GET("/jobs/:id/errors.csv", handler.GetErrorReport)
+	router.DELETE("/jobs/:id", handler.CancelJob)
+
+	// Test cases
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Job",
+			method:             "GET",
+			path:               "/jobs/" + job.ID,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Error Report",
+			method:             "GET",
+			path:               "/jobs/" + job.ID + "/errors.csv",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "item,label,error\n2,Update docs,Validation Failed\n",
+		},
+		{
+			name:               "Job Not Found",
+			method:             "GET",
+			path:               "/jobs/missing",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Error Report Not Found",
+			method:             "GET",
+			path:               "/jobs/missing/errors.csv",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Cancel",
+			method:             "DELETE",
+			path:               "/jobs/" + delayed.ID,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Cancel Finished",
+			method:             "DELETE",
+			path:               "/jobs/" + job.ID,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "Cancel Not Found",
+			method:             "DELETE",
+			path:               "/jobs/missing",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, resp.Body.String())
+				assert.Contains(t, resp.Header().Get("Content-Disposition"), "errors.csv")
+			}
+		})
+	}
+
+	var reported jobs.Job
+	req, _ := http.NewRequest("GET", "/jobs/"+job.ID, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reported))
+	assert.Equal(t, 1, reported.Completed)
+	assert.Equal(t, 1, reported.Failed)
+}
+
+// headerAuthenticator authenticates callers from test headers
+type headerAuthenticator struct{}
+
+func (headerAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	id := r.Header.Get("X-Principal")
+	if id == "" {
+		return nil, auth.ErrNoCredentials
+	}
+	return &auth.Principal{ID: id, Scopes: strings.Split(r.Header.Get("X-Scopes"), ",")}, nil
+}
+
+// TestJobAccess tests that jobs are visible only to their owner and admins, subject to the policy
+func TestJobAccess(t *testing.T) {
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour})
+	require.NoError(t, err)
+	manager.Register("import", func(ctx context.Context, job *jobs.Job, run *jobs.Run) error { return nil })
+	later := time.Now().Add(time.Hour)
+	owned, err := manager.Schedule("import", nil, 1, jobs.Options{RunAt: later, Owner: "key:alice", Repo: "test-repo"})
+	require.NoError(t, err)
+	secret, err := manager.Schedule("import", nil, 1, jobs.Options{RunAt: later, Owner: "key:alice", Repo: "Secret-Repo"})
+	require.NoError(t, err)
+
+	engine, err := policy.Parse([]byte(`
+default: allow
+rules:
+  - effect: deny
+    principals: ["key:alice"]
+    repos: [secret-repo]
+`))
+	require.NoError(t, err)
+
+	router := SetupTestRouter()
+	router.Use(middleware.Authenticate([]auth.Authenticator{headerAuthenticator{}}, auth.RouteScopes{
+		"GET /jobs/:id":    auth.ScopeRead,
+		"DELETE /jobs/:id": auth.ScopeIssuesWrite,
+	}))
+	handler := NewJobsHandler(manager, engine)
+	router.GET("/jobs/:id", handler.GetJob)
+	router.DELETE("/jobs/:id", handler.CancelJob)
+
+	// Test cases
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		principal          string
+		scopes             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Owner",
+			method:             "GET",
+			path:               "/jobs/" + owned.ID,
+			principal:          "key:alice",
+			scopes:             "read",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Other Principal",
+			method:             "GET",
+			path:               "/jobs/" + owned.ID,
+			principal:          "key:bob",
+			scopes:             "read",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Other Principal Cancel",
+			method:             "DELETE",
+			path:               "/jobs/" + owned.ID,
+			principal:          "key:bob",
+			scopes:             "issues:write",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Admin",
+			method:             "GET",
+			path:               "/jobs/" + owned.ID,
+			principal:          "key:root",
+			scopes:             "admin",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Denied By Policy",
+			method:             "GET",
+			path:               "/jobs/" + secret.ID,
+			principal:          "key:alice",
+			scopes:             "read",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Owner Cancel",
+			method:             "DELETE",
+			path:               "/jobs/" + owned.ID,
+			principal:          "key:alice",
+			scopes:             "issues:write",
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("X-Principal", tc.principal)
+			req.Header.Set("X-Scopes", tc.scopes)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+		})
+	}
+
+	job, err := manager.Get(secret.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusQueued, job.Status)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ChecksHandler handles commit status and check run requests from external CI systems
+type ChecksHandler struct {
+	service services.GitHubServiceInterface
+}
+
+// NewChecksHandler creates a new ChecksHandler
+func NewChecksHandler(service services.GitHubServiceInterface) *ChecksHandler {
+	return &ChecksHandler{
+		service: service,
+	}
+}
+
+// CreateCommitStatus handles POST /github/:repo/statuses/:sha
+func (h *ChecksHandler) CreateCommitStatus(c *gin.Context) {
+	repoName := c.Param("repo")
+	sha := c.Param("sha")
+
+	var statusRequest models.CommitStatusRequest
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: state must be one of error, failure, pending or success",
+		})
+		return
+	}
+
+	status, err := callerService(c, h.service).CreateCommitStatus(repoName, sha, &statusRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create commit status")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create commit status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+// CreateCheckRun handles POST /github/:repo/check-runs
+func (h *ChecksHandler) CreateCheckRun(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var checkRunRequest models.CheckRunRequest
+	if err := c.ShouldBindJSON(&checkRunRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	if checkRunRequest.Name == "" || checkRunRequest.HeadSHA == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: name and head_sha are required",
+		})
+		return
+	}
+
+	checkRun, err := callerService(c, h.service).CreateCheckRun(repoName, &checkRunRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create check run")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create check run",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, checkRun)
+}
+
+// UpdateCheckRun handles PATCH /github/:repo/check-runs/:check_run_id
+func (h *ChecksHandler) UpdateCheckRun(c *gin.Context) {
+	repoName := c.Param("repo")
+	checkRunID, ok := parseIDParam(c, "check_run_id")
+	if !ok {
+		return
+	}
+
+	var checkRunRequest models.CheckRunRequest
+	if err := c.ShouldBindJSON(&checkRunRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	checkRun, err := callerService(c, h.service).UpdateCheckRun(repoName, checkRunID, &checkRunRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to update check run")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to update check run",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, checkRun)
+}
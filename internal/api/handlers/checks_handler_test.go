@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateCheckRun tests validation in the CreateCheckRun handler
+func TestCreateCheckRun(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		requestBody        string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"name": "build", "head_sha": "abc123", "status": "completed", "conclusion": "success"}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateCheckRun", "test-repo", mock.Anything).Return(&models.CheckRun{ID: 1}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "Unknown Status",
+			requestBody:        `{"name": "build", "head_sha": "abc123", "status": "done"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Unknown Conclusion",
+			requestBody:        `{"name": "build", "head_sha": "abc123", "status": "completed", "conclusion": "passed"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Completed Without Conclusion",
+			requestBody:        `{"name": "build", "head_sha": "abc123", "status": "completed"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid Annotation Level",
+			requestBody:        `{"name": "build", "head_sha": "abc123", "output": {"title": "t", "summary": "s", "annotations": [{"path": "a.go", "start_line": 1, "end_line": 1, "annotation_level": "error", "message": "m"}]}}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Missing Head SHA",
+			requestBody:        `{"name": "build"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewChecksHandler(mockService)
+			router.POST("/github/:repo/check-runs", handler.CreateCheckRun)
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/check-runs", bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateCommitStatus tests state validation in the CreateCommitStatus handler
+func TestCreateCommitStatus(t *testing.T) {
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+	mockService.On("CreateCommitStatus", "test-repo", "abc123", mock.MatchedBy(func(status *models.CommitStatusRequest) bool {
+		return status.State == "success" && status.Context == "ci/build"
+	})).Return(&models.CommitStatus{ID: 1, State: "success"}, nil)
+
+	handler := NewChecksHandler(mockService)
+	router.POST("/github/:repo/statuses/:sha", handler.CreateCommitStatus)
+
+	// Valid state
+	req, _ := http.NewRequest("POST", "/github/test-repo/statuses/abc123", bytes.NewBufferString(`{"state": "success", "context": "ci/build"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	// Invalid state
+	req, _ = http.NewRequest("POST", "/github/test-repo/statuses/abc123", bytes.NewBufferString(`{"state": "passed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DeploymentsHandler handles deployment and deployment status requests
+type DeploymentsHandler struct {
+	service services.GitHubServiceInterface
+}
+
+// NewDeploymentsHandler creates a new DeploymentsHandler
+func NewDeploymentsHandler(service services.GitHubServiceInterface) *DeploymentsHandler {
+	return &DeploymentsHandler{
+		service: service,
+	}
+}
+
+// CreateDeployment handles POST /github/:repo/deployments
+func (h *DeploymentsHandler) CreateDeployment(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var deploymentRequest models.DeploymentRequest
+	if err := c.ShouldBindJSON(&deploymentRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: ref is required",
+		})
+		return
+	}
+
+	deployment, err := callerService(c, h.service).CreateDeployment(repoName, &deploymentRequest)
+	var merged *services.DeploymentMergedError
+	if errors.As(err, &merged) {
+		// Nothing was deployed; the caller can deploy the ref again once the merge is done
+		c.JSON(http.StatusAccepted, models.DeploymentMergedResponse{
+			Message: merged.Message,
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create deployment")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create deployment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, deployment)
+}
+
+// ListDeployments handles GET /github/:repo/deployments
+func (h *DeploymentsHandler) ListDeployments(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var filter models.DeploymentFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: malformed filter",
+		})
+		return
+	}
+
+	deployments, err := callerService(c, h.service).ListDeployments(repoName, &filter)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list deployments")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve deployments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deployments)
+}
+
+// CreateDeploymentStatus handles POST /github/:repo/deployments/:deployment_id/statuses
+func (h *DeploymentsHandler) CreateDeploymentStatus(c *gin.Context) {
+	repoName := c.Param("repo")
+	deploymentID, ok := parseIDParam(c, "deployment_id")
+	if !ok {
+		return
+	}
+
+	var statusRequest models.DeploymentStatusRequest
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	status, err := callerService(c, h.service).CreateDeploymentStatus(repoName, deploymentID, &statusRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create deployment status")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create deployment status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+// ListDeploymentStatuses handles GET /github/:repo/deployments/:deployment_id/statuses
+func (h *DeploymentsHandler) ListDeploymentStatuses(c *gin.Context) {
+	repoName := c.Param("repo")
+	deploymentID, ok := parseIDParam(c, "deployment_id")
+	if !ok {
+		return
+	}
+
+	statuses, err := callerService(c, h.service).ListDeploymentStatuses(repoName, deploymentID)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list deployment statuses")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve deployment statuses",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// CompleteDeployment handles POST /github/:repo/deployments/:deployment_id/complete
+func (h *DeploymentsHandler) CompleteDeployment(c *gin.Context) {
+	repoName := c.Param("repo")
+	deploymentID, ok := parseIDParam(c, "deployment_id")
+	if !ok {
+		return
+	}
+
+	var completion models.DeploymentCompletionRequest
+	if err := c.ShouldBindJSON(&completion); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: state must be one of success, failure or error",
+		})
+		return
+	}
+
+	statuses, err := callerService(c, h.service).CompleteDeployment(repoName, deploymentID, &completion)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to complete deployment")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to complete deployment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, statuses)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateDeployment tests the CreateDeployment handler
+func TestCreateDeployment(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		requestBody        string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"ref": "main"}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateDeployment", "test-repo", mock.Anything).Return(&models.Deployment{ID: 9, Ref: "main"}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:        "Merged Instead",
+			requestBody: `{"ref": "topic"}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateDeployment", "test-repo", mock.Anything).Return(nil, &services.DeploymentMergedError{Message: "Auto-merged main into topic on deployment."})
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedBody:       `{"message": "Auto-merged main into topic on deployment."}`,
+		},
+		{
+			name:               "Missing Ref",
+			requestBody:        `{}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewDeploymentsHandler(mockService)
+			router.POST("/github/:repo/deployments", handler.CreateDeployment)
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/deployments", bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCompleteDeployment tests the CompleteDeployment handler
+func TestCompleteDeployment(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		path               string
+		requestBody        string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:        "Success",
+			path:        "/github/test-repo/deployments/9/complete",
+			requestBody: `{"state": "failure", "log_url": "https://ci.example.com/runs/1"}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CompleteDeployment", "test-repo", int64(9), mock.MatchedBy(func(completion *models.DeploymentCompletionRequest) bool {
+					return completion.State == "failure"
+				})).Return([]models.DeploymentStatus{{State: "in_progress"}, {State: "failure"}}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "Non Final State",
+			path:               "/github/test-repo/deployments/9/complete",
+			requestBody:        `{"state": "queued"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid Deployment ID",
+			path:               "/github/test-repo/deployments/latest/complete",
+			requestBody:        `{"state": "success"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewDeploymentsHandler(mockService)
+			router.POST("/github/:repo/deployments/:deployment_id/complete", handler.CompleteDeployment)
+
+			req, _ := http.NewRequest("POST", tc.path, bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// upstreamStatus maps a service error to the status code returned to the client.
+// Client errors reported by GitHub are passed through; anything else is a bad gateway.
+func upstreamStatus(err error) int {
+	var apiErr *services.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity:
+			return apiErr.StatusCode
+		}
+	}
+	return http.StatusBadGateway
+}
+
+// parseIDParam reads a numeric ID path parameter, writing a 400 response if it is invalid
+func parseIDParam(c *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid " + param,
+		})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/fanout"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// subscriptionRequest represents a request to create a fan-out subscription
+type subscriptionRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events"`
+	Repos  []string `json:"repos"`
+	Secret string   `json:"secret"`
+}
+
+// FanoutHandler manages webhook fan-out subscriptions and dead letters
+type FanoutHandler struct {
+	service *fanout.Service
+}
+
+// NewFanoutHandler creates a new FanoutHandler
+func NewFanoutHandler(service *fanout.Service) *FanoutHandler {
+	return &FanoutHandler{
+		service: service,
+	}
+}
+
+// CreateSubscription handles POST /fanout/subscriptions
+func (h *FanoutHandler) CreateSubscription(c *gin.Context) {
+	var request subscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: a valid url is required",
+		})
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(fanout.Subscription{
+		URL:    request.URL,
+		Events: request.Events,
+		Repos:  request.Repos,
+		Secret: request.Secret,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create subscription")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create subscription",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListSubscriptions handles GET /fanout/subscriptions
+func (h *FanoutHandler) ListSubscriptions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ListSubscriptions())
+}
+
+// GetSubscription handles GET /fanout/subscriptions/:id
+func (h *FanoutHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.service.GetSubscription(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Subscription not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteSubscription handles DELETE /fanout/subscriptions/:id
+func (h *FanoutHandler) DeleteSubscription(c *gin.Context) {
+	err := h.service.DeleteSubscription(c.Param("id"))
+	if errors.Is(err, fanout.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Subscription not found",
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete subscription")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to delete subscription",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeadLetters handles GET /fanout/dead-letters
+func (h *FanoutHandler) ListDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ListDeadLetters())
+}
+
+// ReplayDeadLetter handles POST /fanout/dead-letters/:id/replay
+func (h *FanoutHandler) ReplayDeadLetter(c *gin.Context) {
+	err := h.service.Replay(c.Param("id"))
+	if errors.Is(err, fanout.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Dead letter not found",
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to replay dead letter")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to replay dead letter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"replayed": 1})
+}
+
+// ReplayAllDeadLetters handles POST /fanout/dead-letters/replay
+func (h *FanoutHandler) ReplayAllDeadLetters(c *gin.Context) {
+	replayed, err := h.service.ReplayAll()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to replay dead letters")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to replay dead letters",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"replayed": replayed})
+}
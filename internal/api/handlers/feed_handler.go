@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/feeds"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// FeedHandler serves Atom feeds built from GitHub data
+type FeedHandler struct {
+	service  services.GitHubServiceInterface
+	username string
+}
+
+// NewFeedHandler creates a new FeedHandler
+func NewFeedHandler(service services.GitHubServiceInterface, username string) *FeedHandler {
+	return &FeedHandler{
+		service:  service,
+		username: username,
+	}
+}
+
+// GetReleasesFeed handles GET /feeds/:repo/releases.atom
+func (h *FeedHandler) GetReleasesFeed(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	releases, err := h.service.ListReleases(repoName)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list releases")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to retrieve releases",
+		})
+		return
+	}
+
+	h.writeFeed(c, feeds.ReleasesFeed(h.username, repoName, releases, selfURL(c)))
+}
+
+// GetIssuesFeed handles GET /feeds/:repo/issues.atom
+func (h *FeedHandler) GetIssuesFeed(c *gin.Context) {
+	repoName := c.Param("repo")
+	state := c.DefaultQuery("state", "all")
+	if state != "open" && state != "closed" && state != "all" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "State must be one of open, closed or all",
+		})
+		return
+	}
+
+	issues, err := h.service.ListIssues(repoName, state)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list issues")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to retrieve issues",
+		})
+		return
+	}
+
+	h.writeFeed(c, feeds.IssuesFeed(h.username, repoName, issues, selfURL(c)))
+}
+
+// GetActivityFeed handles GET /feeds/activity.atom
+func (h *FeedHandler) GetActivityFeed(c *gin.Context) {
+	events, err := h.service.ListUserEvents()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list user events")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to retrieve activity",
+		})
+		return
+	}
+
+	h.writeFeed(c, feeds.ActivityFeed(h.username, events, selfURL(c)))
+}
+
+// writeFeed writes a feed with validators, answering conditional requests with 304
+func (h *FeedHandler) writeFeed(c *gin.Context, feed *feeds.Feed) {
+	body, err := feed.Marshal()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to render feed")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to render feed",
+		})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := feed.UpdatedAt()
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", body)
+}
+
+// notModified reports whether a conditional request can be answered with 304.
+// If-None-Match takes precedence over If-Modified-Since as per RFC 9110.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := req.Header.Get("If-Modified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selfURL reconstructs the absolute URL of the current request
+func selfURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := c.GetHeader("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetReleasesFeed tests the GetReleasesFeed handler including conditional requests
+func TestGetReleasesFeed(t *testing.T) {
+	releases := []models.Release{
+		{ID: 1, TagName: "v1.0.0", PublishedAt: "2025-03-01T10:00:00Z"},
+	}
+
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+	mockService.On("ListReleases", "test-repo").Return(releases, nil)
+	mockService.On("ListReleases", "broken-repo").Return(nil, errors.New("service error"))
+
+	handler := NewFeedHandler(mockService, "test-user")
+	router.GET("/feeds/:repo/releases.atom", handler.GetReleasesFeed)
+
+	// First request returns the feed with validators
+	req, _ := http.NewRequest("GET", "/feeds/test-repo/releases.atom", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "Sat, 01 Mar 2025 10:00:00 GMT", resp.Header().Get("Last-Modified"))
+	assert.Contains(t, resp.Body.String(), "tag:github.com,2008:Repository/test-user/test-repo/Release/1")
+	etag := resp.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Matching ETag returns 304
+	req, _ = http.NewRequest("GET", "/feeds/test-repo/releases.atom", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// Matching modification date returns 304
+	req, _ = http.NewRequest("GET", "/feeds/test-repo/releases.atom", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:00:00 GMT")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+
+	// Upstream failure
+	req, _ = http.NewRequest("GET", "/feeds/broken-repo/releases.atom", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadGateway, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestGetIssuesFeedInvalidState tests that unknown issue states are rejected
+func TestGetIssuesFeedInvalidState(t *testing.T) {
+	router := SetupTestRouter()
+	mockService := new(MockGitHubService)
+
+	handler := NewFeedHandler(mockService, "test-user")
+	router.GET("/feeds/:repo/issues.atom", handler.GetIssuesFeed)
+
+	req, _ := http.NewRequest("GET", "/feeds/test-repo/issues.atom?state=merged", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/feedback"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/ratelimit"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// feedbackFooter marks issues created from untrusted public input
+	feedbackFooter = "\n\n---\n_Submitted through the public feedback form._"
+	// feedbackFormOverhead allows for the fields other than the title and
+	// body when bounding the size of a submission
+	feedbackFormOverhead = 4096
+)
+
+// FeedbackHandler turns submissions of a public feedback form into issues,
+// without requiring credentials
+type FeedbackHandler struct {
+	service  services.GitHubServiceInterface
+	config   config.FeedbackConfig
+	forms    *feedback.Forms
+	captcha  feedback.Verifier
+	throttle *ratelimit.Limiter
+	repos    map[string]bool
+}
+
+// NewFeedbackHandler creates a new FeedbackHandler. A nil captcha verifier
+// accepts submissions without a CAPTCHA response.
+func NewFeedbackHandler(service services.GitHubServiceInterface, cfg config.FeedbackConfig, forms *feedback.Forms, captcha feedback.Verifier, throttle *ratelimit.Limiter) *FeedbackHandler {
+	repos := make(map[string]bool)
+	for _, repo := range cfg.Repos {
+		repos[repo] = true
+	}
+
+	return &FeedbackHandler{
+		service:  service,
+		config:   cfg,
+		forms:    forms,
+		captcha:  captcha,
+		throttle: throttle,
+		repos:    repos,
+	}
+}
+
+// GetForm handles GET /feedback/:repo/form
+func (h *FeedbackHandler) GetForm(c *gin.Context) {
+	repoName := c.Param("repo")
+	if !h.repos[repoName] {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Feedback is not accepted for this repository",
+		})
+		return
+	}
+
+	token, expiresAt := h.forms.Issue(repoName)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.FeedbackForm{
+		Token:           token,
+		ExpiresAt:       expiresAt,
+		CaptchaRequired: h.captcha != nil,
+	})
+}
+
+// SubmitFeedback handles POST /feedback/:repo
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	repoName := c.Param("repo")
+	if !h.repos[repoName] {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Feedback is not accepted for this repository",
+		})
+		return
+	}
+
+	if result := h.throttle.Allow(c.ClientIP(), 1); !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			Error: "Too much feedback from this address; try again later",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.config.MaxTitleLength+h.config.MaxBodyLength+feedbackFormOverhead))
+	var feedbackRequest models.FeedbackRequest
+	if err := c.ShouldBind(&feedbackRequest); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Error: "Feedback is too long",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: title, body and form_token are required",
+		})
+		return
+	}
+
+	// Bots fill in every field; they are told the feedback was received so
+	// that they do not adapt
+	if feedbackRequest.Website != "" {
+		logrus.WithFields(logrus.Fields{
+			"repo":      repoName,
+			"client_ip": c.ClientIP(),
+		}).Info("Discarded feedback that filled in the honeypot")
+		c.JSON(http.StatusAccepted, models.FeedbackResponse{Status: "received"})
+		return
+	}
+
+	// The token is only used up once the submission is otherwise acceptable,
+	// so that a rejected submission can be corrected and sent again
+	if err := h.forms.Validate(repoName, feedbackRequest.FormToken); err != nil {
+		h.rejectFormToken(c, repoName, err)
+		return
+	}
+
+	if h.captcha != nil {
+		if err := h.captcha.Verify(c.Request.Context(), feedbackRequest.CaptchaResponse, c.ClientIP()); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "CAPTCHA verification failed",
+			})
+			return
+		}
+	}
+
+	title := strings.TrimSpace(feedbackRequest.Title)
+	body := strings.TrimSpace(feedbackRequest.Body)
+	if title == "" || body == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: title, body and form_token are required",
+		})
+		return
+	}
+	if utf8.RuneCountInString(title) > h.config.MaxTitleLength || utf8.RuneCountInString(body) > h.config.MaxBodyLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: title is limited to %d characters and body to %d", h.config.MaxTitleLength, h.config.MaxBodyLength),
+		})
+		return
+	}
+	if err := h.forms.Check(repoName, feedbackRequest.FormToken); err != nil {
+		h.rejectFormToken(c, repoName, err)
+		return
+	}
+
+	// Feedback is always filed with the service's own token
+	issue, err := h.service.CreateIssue(repoName, &models.IssueRequest{
+		Title:  title,
+		Body:   body + feedbackFooter,
+		Labels: h.config.Labels,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create issue from feedback")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to submit feedback",
+		})
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"repo":  repoName,
+		"issue": issue.Number,
+	}).Info("Created issue from feedback")
+	c.JSON(http.StatusAccepted, models.FeedbackResponse{Status: "received"})
+}
+
+// rejectFormToken answers a submission whose form token cannot be used
+func (h *FeedbackHandler) rejectFormToken(c *gin.Context, repoName string, err error) {
+	logrus.WithError(err).WithFields(logrus.Fields{
+		"repo":      repoName,
+		"client_ip": c.ClientIP(),
+	}).Info("Rejected feedback with an unusable form token")
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: "The form has expired or is invalid; reload the page and try again",
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/feedback"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubmitFeedback tests the spam protection of the feedback form
+func TestSubmitFeedback(t *testing.T) {
+	cfg := config.FeedbackConfig{
+		Repos:          []string{"website"},
+		Labels:         []string{"feedback"},
+		MaxTitleLength: 20,
+		MaxBodyLength:  100,
+	}
+	forms, err := feedback.NewForms("test-secret", 0, time.Hour)
+	require.NoError(t, err)
+	slowForms, err := feedback.NewForms("test-secret", time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	// Tokens are single use, so each case gets its own
+	newToken := func() string {
+		token, _ := forms.Issue("website")
+		return token
+	}
+	replayed := newToken()
+	require.NoError(t, forms.Check("website", replayed))
+	// A rejected submission does not use up its token
+	corrected := newToken()
+
+	// Test cases
+	tests := []struct {
+		name               string
+		repoName           string
+		forms              *feedback.Forms
+		captcha            feedback.Verifier
+		fields             url.Values
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:     "Success",
+			repoName: "website",
+			forms:    forms,
+			fields:   url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {newToken()}},
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateIssue", "website", mock.MatchedBy(func(req *models.IssueRequest) bool {
+					return req.Title == "Broken link" && strings.HasPrefix(req.Body, "The docs link 404s") &&
+						strings.Contains(req.Body, "feedback form") && req.Labels[0] == "feedback"
+				})).Return(&models.IssueResponse{Number: 1}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Repository Not Allowed",
+			repoName:           "private-repo",
+			forms:              forms,
+			fields:             url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {newToken()}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Honeypot Filled",
+			repoName:           "website",
+			forms:              forms,
+			fields:             url.Values{"title": {"Cheap pills"}, "body": {"Buy now"}, "website": {"http://spam.example"}, "form_token": {newToken()}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Submitted Too Quickly",
+			repoName:           "website",
+			forms:              slowForms,
+			fields:             url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {newToken()}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Replayed Form Token",
+			repoName:           "website",
+			forms:              forms,
+			fields:             url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {replayed}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Missing Form Token",
+			repoName:           "website",
+			forms:              forms,
+			fields:             url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "CAPTCHA Failed",
+			repoName:           "website",
+			forms:              forms,
+			captcha:            feedback.StubVerifier{Response: "human"},
+			fields:             url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {newToken()}, "captcha_response": {"robot"}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Title Too Long",
+			repoName:           "website",
+			forms:              forms,
+			fields:             url.Values{"title": {strings.Repeat("a", 21)}, "body": {"The docs link 404s"}, "form_token": {corrected}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Corrected Resubmission",
+			repoName: "website",
+			forms:    forms,
+			fields:   url.Values{"title": {"Broken link"}, "body": {"The docs link 404s"}, "form_token": {corrected}},
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("CreateIssue", "website", mock.Anything).Return(&models.IssueResponse{Number: 2}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Request Too Large",
+			repoName:           "website",
+			forms:              forms,
+			fields:             url.Values{"title": {"Broken link"}, "body": {strings.Repeat("a", 5000)}, "form_token": {newToken()}},
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewFeedbackHandler(mockService, cfg, tc.forms, tc.captcha, ratelimit.NewLimiter(60, 5))
+			router.POST("/feedback/:repo", handler.SubmitFeedback)
+
+			req, _ := http.NewRequest("POST", "/feedback/"+tc.repoName, bytes.NewBufferString(tc.fields.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSubmitFeedbackThrottle tests that each address is throttled
+func TestSubmitFeedbackThrottle(t *testing.T) {
+	forms, err := feedback.NewForms("test-secret", 0, time.Hour)
+	require.NoError(t, err)
+
+	router := SetupTestRouter()
+	handler := NewFeedbackHandler(new(MockGitHubService), config.FeedbackConfig{Repos: []string{"website"}, MaxTitleLength: 20, MaxBodyLength: 100}, forms, nil, ratelimit.NewLimiter(1, 2))
+	router.GET("/feedback/:repo/form", handler.GetForm)
+	router.POST("/feedback/:repo", handler.SubmitFeedback)
+
+	// Honeypot submissions are accepted without creating issues but still count
+	var codes []int
+	for i := 0; i < 3; i++ {
+		body := url.Values{"title": {"Spam"}, "body": {"Spam"}, "website": {"spam"}, "form_token": {"token"}}
+		req, _ := http.NewRequest("POST", "/feedback/website", bytes.NewBufferString(body.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		codes = append(codes, resp.Code)
+		if resp.Code == http.StatusTooManyRequests {
+			assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests}, codes)
+
+	req, _ := http.NewRequest("GET", "/feedback/website/form", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"token"`)
+}
@@ -1,29 +1,52 @@
 package handlers
 
 import (
+	"errors"
+	"mime/multipart"
 	"net/http"
 
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/attachments"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/duplicates"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// Modes of the duplicate_check query parameter of CreateIssue
+const (
+	duplicateCheckReject  = "reject"
+	duplicateCheckComment = "comment"
+)
+
 // GitHubHandler handles GitHub-related API requests
 type GitHubHandler struct {
-	service services.GitHubServiceInterface
+	service     services.GitHubServiceInterface
+	attachments *attachments.Uploader
 }
 
-// NewGitHubHandler creates a new GitHubHandler
-func NewGitHubHandler(service services.GitHubServiceInterface) *GitHubHandler {
+// NewGitHubHandler creates a new GitHubHandler. Issues are created without
+// attachments when uploader is nil.
+func NewGitHubHandler(service services.GitHubServiceInterface, uploader *attachments.Uploader) *GitHubHandler {
 	return &GitHubHandler{
-		service: service,
+		service:     service,
+		attachments: uploader,
+	}
+}
+
+// callerService returns a service acting as the user signed in through the
+// OAuth flow, or the service itself when the request has no session
+func callerService(c *gin.Context, service services.GitHubServiceInterface) services.GitHubServiceInterface {
+	if token := middleware.GetGitHubToken(c); token != "" {
+		return service.WithToken(token)
 	}
+	return service
 }
 
 // GetUserProfile handles GET /github
 func (h *GitHubHandler) GetUserProfile(c *gin.Context) {
-	profile, err := h.service.GetUserProfile()
+	profile, err := callerService(c, h.service).GetUserProfile()
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get user profile")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -45,7 +68,7 @@ func (h *GitHubHandler) GetRepository(c *gin.Context) {
 		return
 	}
 
-	repo, err := h.service.GetRepository(repoName)
+	repo, err := callerService(c, h.service).GetRepository(repoName)
 	if err != nil {
 		logrus.WithError(err).WithField("repo", repoName).Error("Failed to get repository")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -57,7 +80,10 @@ func (h *GitHubHandler) GetRepository(c *gin.Context) {
 	c.JSON(http.StatusOK, repo)
 }
 
-// CreateIssue handles POST /github/:repo/issues
+// CreateIssue handles POST /github/:repo/issues. The optional duplicate_check
+// query parameter looks for similar open issues first: "reject" refuses to
+// create the issue when any are found, "comment" creates it and lists them in a comment.
+// A multipart request may attach files, which are stored and linked from the body.
 func (h *GitHubHandler) CreateIssue(c *gin.Context) {
 	repoName := c.Param("repo")
 	if repoName == "" {
@@ -67,16 +93,80 @@ func (h *GitHubHandler) CreateIssue(c *gin.Context) {
 		return
 	}
 
+	duplicateCheck := c.Query("duplicate_check")
+	if duplicateCheck != "" && duplicateCheck != duplicateCheckReject && duplicateCheck != duplicateCheckComment {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid duplicate_check: must be reject or comment",
+		})
+		return
+	}
+
 	var issueRequest models.IssueRequest
-	if err := c.ShouldBindJSON(&issueRequest); err != nil {
+	var files []*multipart.FileHeader
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		if h.attachments == nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Attachments are not enabled",
+			})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachments.MaxRequestSize())
+		if err := c.ShouldBind(&issueRequest); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+					Error: "Attachments are too large",
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request: title and body are required",
+			})
+			return
+		}
+		files = c.Request.MultipartForm.File["attachments"]
+	} else if err := c.ShouldBindJSON(&issueRequest); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: "Invalid request: title and body are required",
 		})
 		return
 	}
 
+	service := callerService(c, h.service)
+
+	var candidates []models.DuplicateCandidate
+	if duplicateCheck != "" {
+		candidates = findDuplicates(service, repoName, &issueRequest)
+		if duplicateCheck == duplicateCheckReject && len(candidates) > 0 {
+			c.JSON(http.StatusConflict, models.DuplicateIssueResponse{
+				Error:      "Possible duplicate issues found",
+				Candidates: candidates,
+			})
+			return
+		}
+	}
+
+	if len(files) > 0 {
+		stored, err := h.attachments.Upload(c.Request.Context(), repoName, files)
+		if err != nil {
+			var fileErr *attachments.FileError
+			if errors.As(err, &fileErr) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error: "Invalid request: " + fileErr.Error(),
+				})
+				return
+			}
+			logrus.WithError(err).WithField("repo", repoName).Error("Failed to store attachments")
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{
+				Error: "Failed to store attachments",
+			})
+			return
+		}
+		issueRequest.Body += "\n\n" + attachments.Markdown(stored)
+	}
+
 	// Create the issue
-	issue, err := h.service.CreateIssue(repoName, &issueRequest)
+	issue, err := service.CreateIssue(repoName, &issueRequest)
 	if err != nil {
 		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create issue")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -85,5 +175,31 @@ func (h *GitHubHandler) CreateIssue(c *gin.Context) {
 		return
 	}
 
+	// The issue exists, so a failed comment is only logged
+	if len(candidates) > 0 {
+		if err := service.CreateIssueComment(repoName, issue.Number, duplicates.Comment(candidates)); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"repo":  repoName,
+				"issue": issue.Number,
+			}).Warn("Failed to comment on possible duplicate issue")
+		}
+	}
+
 	c.JSON(http.StatusCreated, issue)
 }
+
+// findDuplicates returns the open issues resembling issue. A failed search
+// does not stop the issue being created.
+func findDuplicates(service services.GitHubServiceInterface, repoName string, issue *models.IssueRequest) []models.DuplicateCandidate {
+	terms := duplicates.SearchTerms(issue)
+	if terms == "" {
+		return nil
+	}
+
+	existing, err := service.SearchIssues(repoName, terms)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Warn("Failed to search for duplicate issues")
+		return nil
+	}
+	return duplicates.Rank(issue, existing)
+}
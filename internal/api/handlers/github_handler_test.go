@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
@@ -23,6 +25,21 @@ type MockGitHubService struct {
 // Ensure MockGitHubService implements GitHubServiceInterface
 var _ services.GitHubServiceInterface = (*MockGitHubService)(nil)
 
+// WithToken mocks the WithToken method
+func (m *MockGitHubService) WithToken(token string) services.GitHubServiceInterface {
+	args := m.Called(token)
+	return args.Get(0).(services.GitHubServiceInterface)
+}
+
+// CheckTokens mocks the CheckTokens method
+func (m *MockGitHubService) CheckTokens() ([]*models.TokenCheck, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TokenCheck), args.Error(1)
+}
+
 // GetUserProfile mocks the GetUserProfile method
 func (m *MockGitHubService) GetUserProfile() (*models.GithubProfile, error) {
 	args := m.Called()
@@ -50,6 +67,264 @@ func (m *MockGitHubService) CreateIssue(repoName string, issue *models.IssueRequ
 	return args.Get(0).(*models.IssueResponse), args.Error(1)
 }
 
+// ListIssues mocks the ListIssues method
+func (m *MockGitHubService) ListIssues(repoName string, state string) ([]models.IssueResponse, error) {
+	args := m.Called(repoName, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IssueResponse), args.Error(1)
+}
+
+// ListReleases mocks the ListReleases method
+func (m *MockGitHubService) ListReleases(repoName string) ([]models.Release, error) {
+	args := m.Called(repoName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Release), args.Error(1)
+}
+
+// ListUserEvents mocks the ListUserEvents method
+func (m *MockGitHubService) ListUserEvents() ([]models.Event, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Event), args.Error(1)
+}
+
+// ListRepositoryEvents mocks the ListRepositoryEvents method
+func (m *MockGitHubService) ListRepositoryEvents(repoName string, etag string) (*models.EventPage, error) {
+	args := m.Called(repoName, etag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EventPage), args.Error(1)
+}
+
+// ListWorkflows mocks the ListWorkflows method
+func (m *MockGitHubService) ListWorkflows(repoName string) ([]models.Workflow, error) {
+	args := m.Called(repoName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Workflow), args.Error(1)
+}
+
+// ListWorkflowRuns mocks the ListWorkflowRuns method
+func (m *MockGitHubService) ListWorkflowRuns(repoName string, filter *models.WorkflowRunFilter) (*models.WorkflowRunList, error) {
+	args := m.Called(repoName, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WorkflowRunList), args.Error(1)
+}
+
+// ListWorkflowRunJobs mocks the ListWorkflowRunJobs method
+func (m *MockGitHubService) ListWorkflowRunJobs(repoName string, runID int64) ([]models.WorkflowJob, error) {
+	args := m.Called(repoName, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WorkflowJob), args.Error(1)
+}
+
+// DownloadWorkflowRunLogs mocks the DownloadWorkflowRunLogs method
+func (m *MockGitHubService) DownloadWorkflowRunLogs(repoName string, runID int64) (io.ReadCloser, error) {
+	args := m.Called(repoName, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+// RerunFailedJobs mocks the RerunFailedJobs method
+func (m *MockGitHubService) RerunFailedJobs(repoName string, runID int64) error {
+	args := m.Called(repoName, runID)
+	return args.Error(0)
+}
+
+// CancelWorkflowRun mocks the CancelWorkflowRun method
+func (m *MockGitHubService) CancelWorkflowRun(repoName string, runID int64) error {
+	args := m.Called(repoName, runID)
+	return args.Error(0)
+}
+
+// DispatchWorkflow mocks the DispatchWorkflow method
+func (m *MockGitHubService) DispatchWorkflow(repoName string, workflow string, dispatch *models.WorkflowDispatchRequest) error {
+	args := m.Called(repoName, workflow, dispatch)
+	return args.Error(0)
+}
+
+// CreateCommitStatus mocks the CreateCommitStatus method
+func (m *MockGitHubService) CreateCommitStatus(repoName string, sha string, status *models.CommitStatusRequest) (*models.CommitStatus, error) {
+	args := m.Called(repoName, sha, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommitStatus), args.Error(1)
+}
+
+// CreateCheckRun mocks the CreateCheckRun method
+func (m *MockGitHubService) CreateCheckRun(repoName string, checkRun *models.CheckRunRequest) (*models.CheckRun, error) {
+	args := m.Called(repoName, checkRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CheckRun), args.Error(1)
+}
+
+// UpdateCheckRun mocks the UpdateCheckRun method
+func (m *MockGitHubService) UpdateCheckRun(repoName string, checkRunID int64, checkRun *models.CheckRunRequest) (*models.CheckRun, error) {
+	args := m.Called(repoName, checkRunID, checkRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CheckRun), args.Error(1)
+}
+
+// CreateDeployment mocks the CreateDeployment method
+func (m *MockGitHubService) CreateDeployment(repoName string, deployment *models.DeploymentRequest) (*models.Deployment, error) {
+	args := m.Called(repoName, deployment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Deployment), args.Error(1)
+}
+
+// ListDeployments mocks the ListDeployments method
+func (m *MockGitHubService) ListDeployments(repoName string, filter *models.DeploymentFilter) ([]models.Deployment, error) {
+	args := m.Called(repoName, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Deployment), args.Error(1)
+}
+
+// CreateDeploymentStatus mocks the CreateDeploymentStatus method
+func (m *MockGitHubService) CreateDeploymentStatus(repoName string, deploymentID int64, status *models.DeploymentStatusRequest) (*models.DeploymentStatus, error) {
+	args := m.Called(repoName, deploymentID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeploymentStatus), args.Error(1)
+}
+
+// ListDeploymentStatuses mocks the ListDeploymentStatuses method
+func (m *MockGitHubService) ListDeploymentStatuses(repoName string, deploymentID int64) ([]models.DeploymentStatus, error) {
+	args := m.Called(repoName, deploymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeploymentStatus), args.Error(1)
+}
+
+// CompleteDeployment mocks the CompleteDeployment method
+func (m *MockGitHubService) CompleteDeployment(repoName string, deploymentID int64, completion *models.DeploymentCompletionRequest) ([]models.DeploymentStatus, error) {
+	args := m.Called(repoName, deploymentID, completion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeploymentStatus), args.Error(1)
+}
+
+// ListHooks mocks the ListHooks method
+func (m *MockGitHubService) ListHooks(repoName string) ([]models.Hook, error) {
+	args := m.Called(repoName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Hook), args.Error(1)
+}
+
+// CreateHook mocks the CreateHook method
+func (m *MockGitHubService) CreateHook(repoName string, hook *models.HookRequest) (*models.Hook, error) {
+	args := m.Called(repoName, hook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hook), args.Error(1)
+}
+
+// UpdateHook mocks the UpdateHook method
+func (m *MockGitHubService) UpdateHook(repoName string, hookID int64, hook *models.HookRequest) (*models.Hook, error) {
+	args := m.Called(repoName, hookID, hook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hook), args.Error(1)
+}
+
+// DeleteHook mocks the DeleteHook method
+func (m *MockGitHubService) DeleteHook(repoName string, hookID int64) error {
+	args := m.Called(repoName, hookID)
+	return args.Error(0)
+}
+
+// PingHook mocks the PingHook method
+func (m *MockGitHubService) PingHook(repoName string, hookID int64) error {
+	args := m.Called(repoName, hookID)
+	return args.Error(0)
+}
+
+// ListHookDeliveries mocks the ListHookDeliveries method
+func (m *MockGitHubService) ListHookDeliveries(repoName string, hookID int64) ([]models.HookDelivery, error) {
+	args := m.Called(repoName, hookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HookDelivery), args.Error(1)
+}
+
+// RedeliverHookDelivery mocks the RedeliverHookDelivery method
+func (m *MockGitHubService) RedeliverHookDelivery(repoName string, hookID int64, deliveryID int64) error {
+	args := m.Called(repoName, hookID, deliveryID)
+	return args.Error(0)
+}
+
+// EnsureHook mocks the EnsureHook method
+func (m *MockGitHubService) EnsureHook(repoName string, events []string) (*models.Hook, bool, error) {
+	args := m.Called(repoName, events)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.Hook), args.Bool(1), args.Error(2)
+}
+
+// SearchIssues mocks the SearchIssues method
+func (m *MockGitHubService) SearchIssues(repoName string, terms string) ([]models.IssueResponse, error) {
+	args := m.Called(repoName, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IssueResponse), args.Error(1)
+}
+
+// CreateIssueComment mocks the CreateIssueComment method
+func (m *MockGitHubService) CreateIssueComment(repoName string, number int, body string) error {
+	args := m.Called(repoName, number, body)
+	return args.Error(0)
+}
+
+// ListDirectory mocks the ListDirectory method
+func (m *MockGitHubService) ListDirectory(repoName string, path string) ([]models.ContentEntry, error) {
+	args := m.Called(repoName, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ContentEntry), args.Error(1)
+}
+
+// GetFileContents mocks the GetFileContents method
+func (m *MockGitHubService) GetFileContents(repoName string, path string) ([]byte, error) {
+	args := m.Called(repoName, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 // SetupTestRouter creates a router for testing
 func SetupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -109,7 +384,7 @@ func TestGetUserProfile(t *testing.T) {
 			tc.setupMock(mockService)
 
 			// Create a handler with our mock service - using the constructor now
-			handler := NewGitHubHandler(mockService)
+			handler := NewGitHubHandler(mockService, nil)
 
 			router.GET("/github", handler.GetUserProfile)
 
@@ -186,7 +461,7 @@ func TestGetRepository(t *testing.T) {
 			tc.setupMock(mockService)
 
 			// Create a handler with our mock service
-			handler := NewGitHubHandler(mockService)
+			handler := NewGitHubHandler(mockService, nil)
 
 			router.GET("/github/:repo", handler.GetRepository)
 
@@ -288,7 +563,7 @@ func TestCreateIssue(t *testing.T) {
 			tc.setupMock(mockService)
 
 			// Create a handler with our mock service
-			handler := NewGitHubHandler(mockService)
+			handler := NewGitHubHandler(mockService, nil)
 
 			router.POST("/github/:repo/issues", handler.CreateIssue)
 
@@ -320,3 +595,114 @@ func TestCreateIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateIssueDuplicateCheck(t *testing.T) {
+	// Create test data
+	issueRequest := &models.IssueRequest{
+		Title: "App crashes on login",
+		Body:  "The app crashes after entering a password",
+	}
+
+	existing := []models.IssueResponse{
+		{Number: 7, Title: "App crashes on login page", HTMLURL: "https://github.com/test-user/test-repo/issues/7"},
+		{Number: 9, Title: "Dark mode colours", HTMLURL: "https://github.com/test-user/test-repo/issues/9"},
+	}
+
+	createdIssue := &models.IssueResponse{
+		Number:  12,
+		Title:   "App crashes on login",
+		HTMLURL: "https://github.com/test-user/test-repo/issues/12",
+	}
+
+	// Test cases
+	tests := []struct {
+		name               string
+		mode               string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+		expectedCandidates []int
+	}{
+		{
+			name: "Reject Duplicate",
+			mode: "reject",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("SearchIssues", "test-repo", "app OR crashes OR login").Return(existing, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedCandidates: []int{7},
+		},
+		{
+			name: "Reject Without Duplicates",
+			mode: "reject",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("SearchIssues", "test-repo", "app OR crashes OR login").Return(existing[1:], nil)
+				mockService.On("CreateIssue", "test-repo", mock.Anything).Return(createdIssue, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name: "Comment On Duplicate",
+			mode: "comment",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("SearchIssues", "test-repo", "app OR crashes OR login").Return(existing, nil)
+				mockService.On("CreateIssue", "test-repo", mock.Anything).Return(createdIssue, nil)
+				mockService.On("CreateIssueComment", "test-repo", 12, mock.MatchedBy(func(body string) bool {
+					return strings.Contains(body, "#7 App crashes on login page")
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name: "Search Failure",
+			mode: "reject",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("SearchIssues", "test-repo", "app OR crashes OR login").Return(nil, errors.New("search failed"))
+				mockService.On("CreateIssue", "test-repo", mock.Anything).Return(createdIssue, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name: "Invalid Mode",
+			mode: "ignore",
+			setupMock: func(mockService *MockGitHubService) {
+				// No mock setup needed as the request will be rejected by the handler
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewGitHubHandler(mockService, nil)
+			router.POST("/github/:repo/issues", handler.CreateIssue)
+
+			requestBody, _ := json.Marshal(issueRequest)
+			req, _ := http.NewRequest("POST", "/github/test-repo/issues?duplicate_check="+tc.mode, bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			// Check the response
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+
+			if tc.expectedStatusCode == http.StatusConflict {
+				var response models.DuplicateIssueResponse
+				err := json.Unmarshal(resp.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				var numbers []int
+				for _, candidate := range response.Candidates {
+					numbers = append(numbers, candidate.Number)
+				}
+				assert.Equal(t, tc.expectedCandidates, numbers)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ensureHookRequest represents the optional body of an ensure hook request
+type ensureHookRequest struct {
+	Events []string `json:"events"`
+}
+
+// HooksHandler handles repository webhook management requests
+type HooksHandler struct {
+	service services.GitHubServiceInterface
+}
+
+// NewHooksHandler creates a new HooksHandler
+func NewHooksHandler(service services.GitHubServiceInterface) *HooksHandler {
+	return &HooksHandler{
+		service: service,
+	}
+}
+
+// ListHooks handles GET /github/:repo/hooks
+func (h *HooksHandler) ListHooks(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	hooks, err := callerService(c, h.service).ListHooks(repoName)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list hooks")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve hooks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// CreateHook handles POST /github/:repo/hooks
+func (h *HooksHandler) CreateHook(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var hookRequest models.HookRequest
+	if err := c.ShouldBindJSON(&hookRequest); err != nil || hookRequest.Config == nil || hookRequest.Config.URL == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: config.url is required",
+		})
+		return
+	}
+
+	hook, err := callerService(c, h.service).CreateHook(repoName, &hookRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create hook")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create hook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, hook)
+}
+
+// UpdateHook handles PATCH /github/:repo/hooks/:hook_id
+func (h *HooksHandler) UpdateHook(c *gin.Context) {
+	repoName := c.Param("repo")
+	hookID, ok := parseIDParam(c, "hook_id")
+	if !ok {
+		return
+	}
+
+	var hookRequest models.HookRequest
+	if err := c.ShouldBindJSON(&hookRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	hook, err := callerService(c, h.service).UpdateHook(repoName, hookID, &hookRequest)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to update hook")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to update hook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// DeleteHook handles DELETE /github/:repo/hooks/:hook_id
+func (h *HooksHandler) DeleteHook(c *gin.Context) {
+	repoName := c.Param("repo")
+	hookID, ok := parseIDParam(c, "hook_id")
+	if !ok {
+		return
+	}
+
+	if err := callerService(c, h.service).DeleteHook(repoName, hookID); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to delete hook")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to delete hook",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PingHook handles POST /github/:repo/hooks/:hook_id/pings
+func (h *HooksHandler) PingHook(c *gin.Context) {
+	repoName := c.Param("repo")
+	hookID, ok := parseIDParam(c, "hook_id")
+	if !ok {
+		return
+	}
+
+	if err := callerService(c, h.service).PingHook(repoName, hookID); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to ping hook")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to ping hook",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListHookDeliveries handles GET /github/:repo/hooks/:hook_id/deliveries
+func (h *HooksHandler) ListHookDeliveries(c *gin.Context) {
+	repoName := c.Param("repo")
+	hookID, ok := parseIDParam(c, "hook_id")
+	if !ok {
+		return
+	}
+
+	deliveries, err := callerService(c, h.service).ListHookDeliveries(repoName, hookID)
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list hook deliveries")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to retrieve hook deliveries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverHookDelivery handles POST /github/:repo/hooks/:hook_id/deliveries/:delivery_id/attempts
+func (h *HooksHandler) RedeliverHookDelivery(c *gin.Context) {
+	repoName := c.Param("repo")
+	hookID, ok := parseIDParam(c, "hook_id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	if err := callerService(c, h.service).RedeliverHookDelivery(repoName, hookID, deliveryID); err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to redeliver hook delivery")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to redeliver hook delivery",
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// EnsureHook handles POST /github/:repo/hooks/ensure
+func (h *HooksHandler) EnsureHook(c *gin.Context) {
+	repoName := c.Param("repo")
+
+	var request ensureHookRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request: events must be a list of event names",
+			})
+			return
+		}
+	}
+
+	hook, created, err := callerService(c, h.service).EnsureHook(repoName, request.Events)
+	if errors.Is(err, services.ErrReceiverNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: "Webhook receiver URL and secret are not configured",
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to ensure hook")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to ensure hook",
+		})
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, hook)
+		return
+	}
+	c.JSON(http.StatusOK, hook)
+}
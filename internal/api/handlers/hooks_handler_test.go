@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnsureHook tests the EnsureHook handler
+func TestEnsureHook(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		requestBody        string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:        "Created",
+			requestBody: "",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("EnsureHook", "test-repo", []string(nil)).Return(&models.Hook{ID: 1}, true, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:        "Updated With Events",
+			requestBody: `{"events": ["push"]}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("EnsureHook", "test-repo", []string{"push"}).Return(&models.Hook{ID: 1}, false, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "Receiver Not Configured",
+			requestBody: "",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("EnsureHook", "test-repo", []string(nil)).Return(nil, false, services.ErrReceiverNotConfigured)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewHooksHandler(mockService)
+			router.POST("/github/:repo/hooks/ensure", handler.EnsureHook)
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/hooks/ensure", bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/issueforms"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// IssueTemplatesHandler handles requests for a repository's issue templates
+// and issues created from them
+type IssueTemplatesHandler struct {
+	service services.GitHubServiceInterface
+}
+
+// NewIssueTemplatesHandler creates a new IssueTemplatesHandler
+func NewIssueTemplatesHandler(service services.GitHubServiceInterface) *IssueTemplatesHandler {
+	return &IssueTemplatesHandler{
+		service: service,
+	}
+}
+
+// ListIssueTemplates handles GET /github/:repo/issue-templates
+func (h *IssueTemplatesHandler) ListIssueTemplates(c *gin.Context) {
+	repoName := c.Param("repo")
+	service := callerService(c, h.service)
+
+	entries, err := service.ListDirectory(repoName, issueforms.Dir)
+	if err != nil {
+		// A repository without templates has no template directory
+		var apiErr *services.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			c.JSON(http.StatusOK, []models.IssueTemplate{})
+			return
+		}
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to list issue templates")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to list issue templates",
+		})
+		return
+	}
+
+	templates := []models.IssueTemplate{}
+	for _, entry := range entries {
+		if entry.Type != "file" {
+			continue
+		}
+
+		template, err := h.loadTemplate(service, repoName, entry.Name)
+		if errors.Is(err, issueforms.ErrNotTemplate) {
+			continue
+		}
+		if err != nil {
+			// GitHub leaves invalid templates out of the chooser too
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"repo": repoName,
+				"file": entry.Name,
+			}).Warn("Skipping invalid issue template")
+			continue
+		}
+		templates = append(templates, template.Summary(entry.Name))
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// CreateTemplateIssue handles POST /github/:repo/issue-templates/:template/issues
+func (h *IssueTemplatesHandler) CreateTemplateIssue(c *gin.Context) {
+	repoName := c.Param("repo")
+	file := c.Param("template")
+	if strings.ContainsAny(file, "/\\") || strings.HasPrefix(file, ".") {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid template",
+		})
+		return
+	}
+
+	var issueRequest models.TemplateIssueRequest
+	if err := c.ShouldBindJSON(&issueRequest); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	service := callerService(c, h.service)
+	template, err := h.loadTemplate(service, repoName, file)
+	if err != nil {
+		var apiErr *services.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			logrus.WithError(err).WithField("repo", repoName).Error("Failed to fetch issue template")
+			c.JSON(upstreamStatus(err), models.ErrorResponse{
+				Error: "Failed to fetch issue template",
+			})
+		case errors.Is(err, issueforms.ErrNotTemplate):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Issue template not found",
+			})
+		default:
+			c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+				Error: "Invalid issue template: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	body, err := template.Render(issueRequest.Fields)
+	if err != nil {
+		var validationErr *issueforms.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusUnprocessableEntity, models.FieldErrorResponse{
+				Error:  "Invalid form values",
+				Fields: validationErr.Fields,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to render issue",
+		})
+		return
+	}
+
+	title := strings.TrimSpace(issueRequest.Title)
+	if title == "" {
+		title = strings.TrimSpace(template.Title)
+	}
+	if title == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request: title is required",
+		})
+		return
+	}
+
+	issue, err := service.CreateIssue(repoName, &models.IssueRequest{
+		Title:     title,
+		Body:      body,
+		Labels:    template.Labels,
+		Assignees: template.Assignees,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("repo", repoName).Error("Failed to create issue")
+		c.JSON(upstreamStatus(err), models.ErrorResponse{
+			Error: "Failed to create issue",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, issue)
+}
+
+// loadTemplate fetches and parses a template from the repository's template directory
+func (h *IssueTemplatesHandler) loadTemplate(service services.GitHubServiceInterface, repoName string, file string) (*issueforms.Template, error) {
+	// Other files are not templates, so they are not fetched
+	if !issueforms.IsTemplateFile(file) {
+		return nil, issueforms.ErrNotTemplate
+	}
+
+	content, err := service.GetFileContents(repoName, issueforms.Dir+"/"+file)
+	if err != nil {
+		return nil, err
+	}
+	return issueforms.Parse(file, content)
+}
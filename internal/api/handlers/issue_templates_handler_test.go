@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssueForm = `name: Bug report
+title: "[Bug]: "
+labels: bug
+body:
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      required: true
+`
+
+// TestListIssueTemplates tests that templates are listed and other files skipped
+func TestListIssueTemplates(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+		expectedFiles      []string
+	}{
+		{
+			name: "Success",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("ListDirectory", "test-repo", ".github/ISSUE_TEMPLATE").Return([]models.ContentEntry{
+					{Name: "bug.yml", Type: "file"},
+					{Name: "config.yml", Type: "file"},
+					{Name: "broken.yml", Type: "file"},
+				}, nil)
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/bug.yml").Return([]byte(testIssueForm), nil)
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/broken.yml").Return([]byte("name: Broken"), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedFiles:      []string{"bug.yml"},
+		},
+		{
+			name: "No Templates",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("ListDirectory", "test-repo", ".github/ISSUE_TEMPLATE").Return(nil, &services.APIError{StatusCode: http.StatusNotFound})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedFiles:      []string{},
+		},
+		{
+			name: "Service Error",
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("ListDirectory", "test-repo", ".github/ISSUE_TEMPLATE").Return(nil, &services.APIError{StatusCode: http.StatusInternalServerError})
+			},
+			expectedStatusCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewIssueTemplatesHandler(mockService)
+			router.GET("/github/:repo/issue-templates", handler.ListIssueTemplates)
+
+			req, _ := http.NewRequest("GET", "/github/test-repo/issue-templates", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			if tc.expectedFiles != nil {
+				var templates []models.IssueTemplate
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &templates))
+				files := []string{}
+				for _, template := range templates {
+					files = append(files, template.File)
+				}
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateTemplateIssue tests validating form values and creating the rendered issue
+func TestCreateTemplateIssue(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name               string
+		template           string
+		requestBody        string
+		setupMock          func(mockService *MockGitHubService)
+		expectedStatusCode int
+	}{
+		{
+			name:        "Success",
+			template:    "bug.yml",
+			requestBody: `{"title": "Crash on start", "fields": {"version": "1.2.0"}}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/bug.yml").Return([]byte(testIssueForm), nil)
+				mockService.On("CreateIssue", "test-repo", mock.MatchedBy(func(req *models.IssueRequest) bool {
+					return req.Title == "Crash on start" && req.Body == "### Version\n\n1.2.0" && len(req.Labels) == 1 && req.Labels[0] == "bug"
+				})).Return(&models.IssueResponse{Number: 1}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:        "Template Title",
+			template:    "bug.yml",
+			requestBody: `{"fields": {"version": "1.2.0"}}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/bug.yml").Return([]byte(testIssueForm), nil)
+				mockService.On("CreateIssue", "test-repo", mock.MatchedBy(func(req *models.IssueRequest) bool {
+					return req.Title == "[Bug]:"
+				})).Return(&models.IssueResponse{Number: 1}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:        "Invalid Values",
+			template:    "bug.yml",
+			requestBody: `{"title": "Crash on start", "fields": {"os": "linux"}}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/bug.yml").Return([]byte(testIssueForm), nil)
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "Template Not Found",
+			template:    "missing.yml",
+			requestBody: `{"title": "Crash on start"}`,
+			setupMock: func(mockService *MockGitHubService) {
+				mockService.On("GetFileContents", "test-repo", ".github/ISSUE_TEMPLATE/missing.yml").Return(nil, &services.APIError{StatusCode: http.StatusNotFound})
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Not A Template",
+			template:           "config.yml",
+			requestBody:        `{"title": "Crash on start"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Hidden File",
+			template:           "..yml",
+			requestBody:        `{"title": "Crash on start"}`,
+			setupMock:          func(mockService *MockGitHubService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := SetupTestRouter()
+			mockService := new(MockGitHubService)
+			tc.setupMock(mockService)
+
+			handler := NewIssueTemplatesHandler(mockService)
+			router.POST("/github/:repo/issue-templates/:template/issues", handler.CreateTemplateIssue)
+
+			req, _ := http.NewRequest("POST", "/github/test-repo/issue-templates/"+tc.template+"/issues", bytes.NewBufferString(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
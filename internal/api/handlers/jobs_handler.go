@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// JobsHandler reports the progress of background jobs
+type JobsHandler struct {
+	jobs   *jobs.Manager
+	policy *policy.Engine
+}
+
+// NewJobsHandler creates a new JobsHandler. A job is visible only to the
+// principal that created it and to admins, and the engine, which may be nil,
+// is evaluated against the job's repository.
+func NewJobsHandler(manager *jobs.Manager, engine *policy.Engine) *JobsHandler {
+	return &JobsHandler{
+		jobs:   manager,
+		policy: engine,
+	}
+}
+
+// GetJob handles GET /jobs/:id
+func (h *JobsHandler) GetJob(c *gin.Context) {
+	job, ok := h.getJob(c, auth.ScopeRead)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelJob handles DELETE /jobs/:id
+func (h *JobsHandler) CancelJob(c *gin.Context) {
+	if _, ok := h.getJob(c, auth.ScopeIssuesWrite); !ok {
+		return
+	}
+
+	job, err := h.jobs.Cancel(c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Job not found",
+			})
+		case errors.Is(err, jobs.ErrFinished):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error: "Job has already finished",
+			})
+		default:
+			logrus.WithError(err).Error("Failed to cancel job")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to cancel job",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetErrorReport handles GET /jobs/:id/errors.csv
+func (h *JobsHandler) GetErrorReport(c *gin.Context) {
+	job, ok := h.getJob(c, auth.ScopeRead)
+	if !ok {
+		return
+	}
+
+	var report bytes.Buffer
+	if err := jobs.ErrorReport(job, &report); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("Failed to write job error report")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to write error report",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="job-`+job.ID+`-errors.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report.Bytes())
+}
+
+// getJob looks up the job named by the id parameter for an operation,
+// writing a 404 response if it does not exist or belongs to another principal
+// and a 403 response if the policy denies the operation on its repository
+func (h *JobsHandler) getJob(c *gin.Context, operation string) (*jobs.Job, bool) {
+	job, err := h.jobs.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, jobs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Job not found",
+			})
+			return nil, false
+		}
+		logrus.WithError(err).Error("Failed to get job")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to get job",
+		})
+		return nil, false
+	}
+
+	principal := middleware.GetPrincipal(c)
+	// Other principals' jobs are reported as missing so that their IDs cannot be probed
+	if !ownsJob(principal, job) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return nil, false
+	}
+
+	// Jobs name their repository, so the route-level policy check cannot cover them
+	if h.policy != nil && job.Repo != "" {
+		decision := h.policy.Evaluate(policy.Request{Principal: principal, Repo: job.Repo, Operation: operation})
+		if !decision.Allowed {
+			logrus.WithFields(logrus.Fields{
+				"principal": decision.Principal,
+				"repo":      decision.Repo,
+				"operation": decision.Operation,
+				"rule":      decision.Rule,
+				"job_id":    job.ID,
+			}).Warn("Denied job access by policy")
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error: "Access denied by policy",
+			})
+			return nil, false
+		}
+	}
+	return job, true
+}
+
+// ownsJob reports whether a principal may see a job. Admins see every job;
+// without authentication there is no principal and only unowned jobs match.
+func ownsJob(principal *auth.Principal, job *jobs.Job) bool {
+	if principal == nil {
+		return job.Owner == ""
+	}
+	return principal.ID == job.Owner || principal.HasScope(auth.ScopeAdmin)
+}
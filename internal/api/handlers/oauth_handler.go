@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// oauthStateCookie binds the callback to the browser that started the login
+	oauthStateCookie = "gh_oauth_state"
+	// oauthStateTTL bounds how long a user may take to authorize the app
+	oauthStateTTL = 10 * time.Minute
+)
+
+// sessionResponse describes the signed-in user without their token
+type sessionResponse struct {
+	Login     string    `json:"login"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OAuthHandler signs users in with GitHub so that requests act under their own identity
+type OAuthHandler struct {
+	oauth    *auth.OAuthClient
+	sessions *auth.SessionManager
+	secure   bool
+}
+
+// NewOAuthHandler creates a new OAuthHandler. Secure marks the login state
+// cookie as HTTPS only.
+func NewOAuthHandler(oauth *auth.OAuthClient, sessions *auth.SessionManager, secure bool) *OAuthHandler {
+	return &OAuthHandler{
+		oauth:    oauth,
+		sessions: sessions,
+		secure:   secure,
+	}
+}
+
+// Login handles GET /auth/github/login
+func (h *OAuthHandler) Login(c *gin.Context) {
+	redirect := c.Query("redirect")
+	if redirect != "" && !localRedirect(redirect) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid redirect: must be a path on this service",
+		})
+		return
+	}
+
+	state, err := auth.NewState()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate OAuth state")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to start login",
+		})
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state + "|" + redirect,
+		Path:     "/auth/github",
+		MaxAge:   int(oauthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.Redirect(http.StatusFound, h.oauth.AuthCodeURL(state))
+}
+
+// Callback handles GET /auth/github/callback
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	cookie, err := c.Request.Cookie(oauthStateCookie)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Login expired, please sign in again",
+		})
+		return
+	}
+	h.clearStateCookie(c)
+
+	state, redirect, _ := strings.Cut(cookie.Value, "|")
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		logrus.Warn("Rejected OAuth callback with mismatched state")
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid OAuth state",
+		})
+		return
+	}
+
+	if reason := c.Query("error"); reason != "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "GitHub authorization failed: " + reason,
+		})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Authorization code is required",
+		})
+		return
+	}
+
+	token, err := h.oauth.Exchange(code)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to exchange OAuth code")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to complete GitHub login",
+		})
+		return
+	}
+
+	login, err := h.oauth.Login(token)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to identify OAuth user")
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Error: "Failed to complete GitHub login",
+		})
+		return
+	}
+
+	session := h.sessions.NewSession(login, token)
+	if err := h.sessions.SetCookie(c.Writer, session); err != nil {
+		logrus.WithError(err).Error("Failed to create session")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to complete GitHub login",
+		})
+		return
+	}
+
+	logrus.WithField("login", login).Info("User signed in with GitHub")
+	if localRedirect(redirect) {
+		c.Redirect(http.StatusFound, redirect)
+		return
+	}
+	c.JSON(http.StatusOK, sessionResponse{Login: session.Login, ExpiresAt: session.ExpiresAt})
+}
+
+// Logout handles POST /auth/github/logout
+func (h *OAuthHandler) Logout(c *gin.Context) {
+	h.sessions.ClearCookie(c.Writer)
+	c.Status(http.StatusNoContent)
+}
+
+// GetSession handles GET /auth/github/session
+func (h *OAuthHandler) GetSession(c *gin.Context) {
+	session, err := h.sessions.FromRequest(c.Request)
+	if err != nil {
+		if !errors.Is(err, auth.ErrNoCredentials) {
+			h.sessions.ClearCookie(c.Writer)
+		}
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Not signed in",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse{Login: session.Login, ExpiresAt: session.ExpiresAt})
+}
+
+func (h *OAuthHandler) clearStateCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    "",
+		Path:     "/auth/github",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// localRedirect reports whether target is a path on this service, so that
+// the login flow cannot be used as an open redirect
+func localRedirect(target string) bool {
+	return strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") && !strings.Contains(target, "\\")
+}
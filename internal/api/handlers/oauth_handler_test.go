@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOAuthRouter creates a router signing users in against a fake GitHub
+func setupOAuthRouter(t *testing.T, service *MockGitHubService) *gin.Engine {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/oauth/access_token":
+			if r.FormValue("code") != "good-code" {
+				w.Write([]byte(`{"error": "bad_verification_code"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "gho_user"}`))
+		case "/user":
+			w.Write([]byte(`{"login": "octocat"}`))
+		}
+	}))
+	t.Cleanup(github.Close)
+
+	sessions, err := auth.NewSessionManager(strings.Repeat("s", 32), time.Hour, false, nil)
+	require.NoError(t, err)
+	handler := NewOAuthHandler(auth.NewOAuthClient(auth.OAuthOptions{
+		ClientID:    "client-id",
+		RedirectURL: "http://localhost/auth/github/callback",
+		TokenURL:    github.URL + "/login/oauth/access_token",
+		UserURL:     github.URL + "/user",
+	}), sessions, false)
+
+	router := SetupTestRouter()
+	router.Use(middleware.Session(sessions))
+	router.GET("/auth/github/login", handler.Login)
+	router.GET("/auth/github/callback", handler.Callback)
+	router.GET("/auth/github/session", handler.GetSession)
+	router.POST("/auth/github/logout", handler.Logout)
+	router.GET("/github/:repo", NewGitHubHandler(service, nil).GetRepository)
+	return router
+}
+
+// findCookie returns the named cookie set by a response
+func findCookie(resp *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// TestOAuthLoginFlow tests that a signed-in user's requests carry their own token
+func TestOAuthLoginFlow(t *testing.T) {
+	userService := new(MockGitHubService)
+	userService.On("GetRepository", "test-repo").Return(&models.Repository{Name: "test-repo"}, nil)
+	mockService := new(MockGitHubService)
+	mockService.On("WithToken", "gho_user").Return(userService)
+	mockService.On("GetRepository", "test-repo").Return(&models.Repository{Name: "test-repo"}, nil)
+
+	router := setupOAuthRouter(t, mockService)
+
+	// Login redirects to GitHub and remembers the state
+	req, _ := http.NewRequest("GET", "/auth/github/login?redirect=/dashboard", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusFound, resp.Code)
+	location, err := url.Parse(resp.Header().Get("Location"))
+	require.NoError(t, err)
+	state := location.Query().Get("state")
+	assert.NotEmpty(t, state)
+	stateCookie := findCookie(resp, oauthStateCookie)
+	require.NotNil(t, stateCookie)
+
+	// The callback exchanges the code and sets the session cookie
+	req, _ = http.NewRequest("GET", "/auth/github/callback?code=good-code&state="+state, nil)
+	req.AddCookie(stateCookie)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusFound, resp.Code)
+	assert.Equal(t, "/dashboard", resp.Header().Get("Location"))
+	sessionCookie := findCookie(resp, auth.SessionCookieName)
+	require.NotNil(t, sessionCookie)
+	assert.True(t, sessionCookie.HttpOnly)
+	assert.NotContains(t, sessionCookie.Value, "gho_user")
+
+	req, _ = http.NewRequest("GET", "/auth/github/session", nil)
+	req.AddCookie(sessionCookie)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"login":"octocat"`)
+
+	// Requests with the session use the user's token, others the service token
+	req, _ = http.NewRequest("GET", "/github/test-repo", nil)
+	req.AddCookie(sessionCookie)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	req, _ = http.NewRequest("GET", "/github/test-repo", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	userService.AssertNumberOfCalls(t, "GetRepository", 1)
+	mockService.AssertNumberOfCalls(t, "WithToken", 1)
+	mockService.AssertNumberOfCalls(t, "GetRepository", 1)
+
+	req, _ = http.NewRequest("POST", "/auth/github/logout", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, -1, findCookie(resp, auth.SessionCookieName).MaxAge)
+}
+
+// TestOAuthCallbackErrors tests that callbacks without a matching state or a valid code are rejected
+func TestOAuthCallbackErrors(t *testing.T) {
+	router := setupOAuthRouter(t, new(MockGitHubService))
+	stateCookie := &http.Cookie{Name: oauthStateCookie, Value: "abc|"}
+
+	// Test cases
+	tests := []struct {
+		name           string
+		query          string
+		cookie         *http.Cookie
+		expectedStatus int
+	}{
+		{name: "Missing State Cookie", query: "?code=good-code&state=abc", expectedStatus: http.StatusBadRequest},
+		{name: "Mismatched State", query: "?code=good-code&state=xyz", cookie: stateCookie, expectedStatus: http.StatusBadRequest},
+		{name: "Access Denied", query: "?error=access_denied&state=abc", cookie: stateCookie, expectedStatus: http.StatusUnauthorized},
+		{name: "Bad Code", query: "?code=bad-code&state=abc", cookie: stateCookie, expectedStatus: http.StatusBadGateway},
+		{name: "No Redirect", query: "?code=good-code&state=abc", cookie: stateCookie, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/auth/github/callback"+tc.query, nil)
+			if tc.cookie != nil {
+				req.AddCookie(tc.cookie)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+			assert.Equal(t, tc.expectedStatus == http.StatusOK, findCookie(resp, auth.SessionCookieName) != nil)
+		})
+	}
+
+	req, _ := http.NewRequest("GET", "/auth/github/login?redirect=//evil.example.com", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
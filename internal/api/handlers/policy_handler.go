@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyHandler exposes access policy decisions for debugging
+type PolicyHandler struct {
+	engine *policy.Engine
+}
+
+// NewPolicyHandler creates a new PolicyHandler
+func NewPolicyHandler(engine *policy.Engine) *PolicyHandler {
+	return &PolicyHandler{
+		engine: engine,
+	}
+}
+
+// Explain handles GET /policy/explain. It evaluates the repo and operation
+// query parameters for the principal and groups parameters, or for the
+// caller when no principal is given, and returns the rule-by-rule trace.
+func (h *PolicyHandler) Explain(c *gin.Context) {
+	operation := c.DefaultQuery("operation", auth.ScopeRead)
+	if !auth.ValidScope(operation) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Operation must be one of read, issues:write or admin",
+		})
+		return
+	}
+
+	principal := middleware.GetPrincipal(c)
+	if id := c.Query("principal"); id != "" {
+		principal = &auth.Principal{
+			ID:     id,
+			Name:   id,
+			Groups: splitList(c.Query("groups")),
+		}
+	}
+
+	c.JSON(http.StatusOK, h.engine.Evaluate(policy.Request{
+		Principal: principal,
+		Repo:      c.Query("repo"),
+		Operation: operation,
+	}))
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExplain tests the decision trace returned for a principal
+func TestExplain(t *testing.T) {
+	engine, err := policy.Parse([]byte(`
+rules:
+  - name: support-issues
+    effect: allow
+    groups: [support]
+    operations: ["issues:write"]
+`))
+	require.NoError(t, err)
+
+	router := SetupTestRouter()
+	handler := NewPolicyHandler(engine)
+	router.GET("/policy/explain", handler.Explain)
+
+	// Test cases
+	tests := []struct {
+		name            string
+		query           string
+		expectedStatus  int
+		expectedAllowed bool
+		expectedRule    string
+	}{
+		{
+			name:            "Allowed Group",
+			query:           "?principal=bob&groups=support&repo=web&operation=issues:write",
+			expectedStatus:  http.StatusOK,
+			expectedAllowed: true,
+			expectedRule:    "support-issues",
+		},
+		{
+			name:           "Caller Defaults To Read",
+			query:          "?repo=web",
+			expectedStatus: http.StatusOK,
+			expectedRule:   "default",
+		},
+		{
+			name:           "Unknown Operation",
+			query:          "?repo=web&operation=delete",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/policy/explain"+tc.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var decision policy.Decision
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decision))
+			assert.Equal(t, tc.expectedAllowed, decision.Allowed)
+			assert.Equal(t, tc.expectedRule, decision.Rule)
+			assert.Len(t, decision.Trace, 1)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/events"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// streamRetry is the reconnection delay suggested to EventSource clients
+const streamRetry = 3 * time.Second
+
+// StreamHandler pushes live repository events to clients
+type StreamHandler struct {
+	broker    *events.Broker
+	poller    *events.Poller
+	owner     string
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new StreamHandler. Poller may be nil when the
+// broker is fed by the webhook receiver.
+func NewStreamHandler(broker *events.Broker, poller *events.Poller, owner string, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		broker:    broker,
+		poller:    poller,
+		owner:     owner,
+		heartbeat: heartbeat,
+	}
+}
+
+// StreamRepositoryEvents handles GET /github/:repo/events/stream
+func (h *StreamHandler) StreamRepositoryEvents(c *gin.Context) {
+	filter, lastEventID, ok := h.streamFilter(c)
+	if !ok {
+		return
+	}
+
+	if h.poller != nil {
+		release := h.poller.Watch(c.Param("repo"))
+		defer release()
+	}
+
+	sub, backlog := h.broker.Subscribe(filter, lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, msg := range backlog {
+		if err := writeServerSentEvent(w, msg); err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := writeServerSentEvent(w, msg); err != nil {
+				return
+			}
+			w.Flush()
+		case <-ticker.C:
+			// Comments keep proxies from closing idle connections
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// streamFilter builds the subscription filter from the types and actions
+// query parameters and reads the resume position from the Last-Event-ID
+// header, falling back to the last_event_id query parameter
+func (h *StreamHandler) streamFilter(c *gin.Context) (events.Filter, uint64, bool) {
+	filter, err := newStreamFilter(h.owner, c.Param("repo"), splitList(c.Query("types")), splitList(c.Query("actions")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return events.Filter{}, 0, false
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID == "" {
+		return filter, 0, true
+	}
+
+	id, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Last event ID must be a positive integer",
+		})
+		return events.Filter{}, 0, false
+	}
+
+	return filter, id, true
+}
+
+// newStreamFilter builds a filter for one of the owner's repositories,
+// rejecting event types that are not streamed
+func newStreamFilter(owner, repoName string, types, actions []string) (events.Filter, error) {
+	for _, eventType := range types {
+		if !containsString(events.StreamTypes, eventType) {
+			return events.Filter{}, fmt.Errorf("Types must be a comma separated list of %s", strings.Join(events.StreamTypes, ", "))
+		}
+	}
+
+	return events.Filter{
+		Repository: owner + "/" + repoName,
+		Types:      types,
+		Actions:    actions,
+	}, nil
+}
+
+// writeServerSentEvent writes a message as an SSE event
+func writeServerSentEvent(w io.Writer, msg events.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logrus.WithError(err).WithField("event_id", msg.ID).Error("Failed to encode stream event")
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+	return err
+}
+
+// splitList splits a comma separated query parameter, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamRepositoryEvents tests resuming, filtering, live delivery and heartbeats
+func TestStreamRepositoryEvents(t *testing.T) {
+	broker := events.NewBroker(10, 8)
+	broker.Publish(events.Message{Type: "push", Repository: "test-user/test-repo"})
+	broker.Publish(events.Message{Type: "issues", Action: "opened", Repository: "test-user/test-repo"})
+	broker.Publish(events.Message{Type: "issues", Action: "opened", Repository: "test-user/other-repo"})
+
+	router := SetupTestRouter()
+	handler := NewStreamHandler(broker, nil, "test-user", 50*time.Millisecond)
+	router.GET("/github/:repo/events/stream", handler.StreamRepositoryEvents)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/github/test-repo/events/stream?types=issues,release", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// expect reads lines until one starts with prefix
+	expect := func(prefix string) string {
+		t.Helper()
+		for {
+			select {
+			case line, ok := <-lines:
+				require.True(t, ok, "stream closed before %q", prefix)
+				if strings.HasPrefix(line, prefix) {
+					return line
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out waiting for %q", prefix)
+			}
+		}
+	}
+
+	// The backlog resumes after the last event ID and skips other repositories
+	assert.Equal(t, "retry: 3000", expect("retry:"))
+	assert.Equal(t, "id: 2", expect("id:"))
+	assert.Equal(t, "event: issues", expect("event:"))
+	assert.Contains(t, expect("data:"), `"action":"opened"`)
+
+	// Live events are filtered by type
+	broker.Publish(events.Message{Type: "push", Repository: "test-user/test-repo"})
+	broker.Publish(events.Message{Type: "release", Action: "published", Repository: "test-user/test-repo"})
+	assert.Equal(t, "id: 5", expect("id:"))
+	assert.Equal(t, "event: release", expect("event:"))
+
+	// Idle connections receive heartbeat comments
+	assert.Equal(t, ": heartbeat", expect(":"))
+}
+
+// TestStreamRepositoryEventsValidation tests rejection of invalid stream parameters
+func TestStreamRepositoryEventsValidation(t *testing.T) {
+	router := SetupTestRouter()
+	handler := NewStreamHandler(events.NewBroker(10, 8), nil, "test-user", time.Second)
+	router.GET("/github/:repo/events/stream", handler.StreamRepositoryEvents)
+
+	// Test cases
+	tests := []struct {
+		name        string
+		url         string
+		lastEventID string
+	}{
+		{
+			name: "Unknown Type",
+			url:  "/github/test-repo/events/stream?types=issues,workflow_run",
+		},
+		{
+			name:        "Invalid Last Event ID",
+			url:         "/github/test-repo/events/stream",
+			lastEventID: "abc",
+		},
+		{
+			name: "Invalid Last Event ID Query",
+			url:  "/github/test-repo/events/stream?last_event_id=-1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.url, nil)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxWebhookPayloadSize is the largest payload GitHub will deliver
+const maxWebhookPayloadSize = 25 << 20
+
+// WebhookHandler receives webhook deliveries from GitHub
+type WebhookHandler struct {
+	secret     []byte
+	deliveries *webhooks.DeliveryCache
+	dispatcher *webhooks.Dispatcher
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(secret string, deliveries *webhooks.DeliveryCache, dispatcher *webhooks.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		secret:     []byte(secret),
+		deliveries: deliveries,
+		dispatcher: dispatcher,
+	}
+}
+
+// ReceiveGitHubWebhook handles POST /webhooks/github
+func (h *WebhookHandler) ReceiveGitHubWebhook(c *gin.Context) {
+	if len(h.secret) == 0 {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: "Webhook receiver is not configured",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayloadSize+1))
+	if err != nil || len(body) > maxWebhookPayloadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Error: "Payload too large",
+		})
+		return
+	}
+
+	if !webhooks.VerifySignature(h.secret, body, c.GetHeader("X-Hub-Signature-256")) {
+		logrus.WithField("client_ip", c.ClientIP()).Warn("Rejected webhook with invalid signature")
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Invalid signature",
+		})
+		return
+	}
+
+	eventType := c.GetHeader("X-GitHub-Event")
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	if eventType == "" || deliveryID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "X-GitHub-Event and X-GitHub-Delivery headers are required",
+		})
+		return
+	}
+
+	entry := logrus.WithFields(logrus.Fields{
+		"event":       eventType,
+		"delivery_id": deliveryID,
+	})
+
+	event, err := webhooks.ParseEvent(eventType, deliveryID, body)
+	if err != nil {
+		entry.WithError(err).Warn("Failed to parse webhook payload")
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid payload",
+		})
+		return
+	}
+
+	// The delivery is claimed before dispatch so that a concurrent duplicate
+	// is not dispatched twice, and only stays claimed if dispatch succeeds
+	if !h.deliveries.MarkSeen(deliveryID) {
+		entry.Info("Ignoring duplicate webhook delivery")
+		c.JSON(http.StatusOK, gin.H{"status": "duplicate"})
+		return
+	}
+
+	if err := h.dispatcher.Dispatch(event); err != nil {
+		// Forget the delivery so that a redelivery from GitHub is processed again
+		h.deliveries.Forget(deliveryID)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to process webhook",
+		})
+		return
+	}
+
+	entry.WithField("repo", event.Repository).Info("Webhook processed")
+	c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReceiveGitHubWebhook tests signature checks, deduplication and dispatch
+func TestReceiveGitHubWebhook(t *testing.T) {
+	secret := "test-secret"
+	payload := []byte(`{"action": "published", "release": {"tag_name": "v1.0.0"}, "repository": {"full_name": "test-user/test-repo"}}`)
+
+	dispatcher := webhooks.NewDispatcher()
+	var received []*webhooks.Event
+	failNext := false
+	dispatcher.Register(webhooks.EventRelease, func(event *webhooks.Event) error {
+		received = append(received, event)
+		if failNext {
+			failNext = false
+			return errors.New("handler error")
+		}
+		return nil
+	})
+
+	router := SetupTestRouter()
+	handler := NewWebhookHandler(secret, webhooks.NewDeliveryCache(time.Hour), dispatcher)
+	router.POST("/webhooks/github", handler.ReceiveGitHubWebhook)
+
+	send := func(deliveryID, signature string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/webhooks/github", bytes.NewBuffer(payload))
+		req.Header.Set("X-GitHub-Event", "release")
+		req.Header.Set("X-GitHub-Delivery", deliveryID)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	validSignature := webhooks.Sign([]byte(secret), payload)
+
+	// Invalid signature is rejected before dispatch
+	resp := send("delivery-1", "sha256=00")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Empty(t, received)
+
+	// Valid delivery is dispatched with a typed payload
+	resp = send("delivery-1", validSignature)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Len(t, received, 1)
+	release, ok := received[0].Payload.(*models.ReleaseEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.0.0", release.Release.TagName)
+
+	// Duplicate delivery is acknowledged but not dispatched again
+	resp = send("delivery-1", validSignature)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, received, 1)
+
+	// Failed dispatch can be redelivered
+	failNext = true
+	resp = send("delivery-2", validSignature)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	resp = send("delivery-2", validSignature)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Len(t, received, 3)
+
+	// A payload that fails to parse does not mark the delivery as seen
+	invalid := []byte(`{"action": "published", "release": []}`)
+	req, _ := http.NewRequest("POST", "/webhooks/github", bytes.NewBuffer(invalid))
+	req.Header.Set("X-GitHub-Event", "release")
+	req.Header.Set("X-GitHub-Delivery", "delivery-3")
+	req.Header.Set("X-Hub-Signature-256", webhooks.Sign([]byte(secret), invalid))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = send("delivery-3", validSignature)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Len(t, received, 4)
+}
+
+// TestReceiveGitHubWebhookWithoutSecret tests that an unconfigured receiver rejects deliveries
+func TestReceiveGitHubWebhookWithoutSecret(t *testing.T) {
+	router := SetupTestRouter()
+	handler := NewWebhookHandler("", webhooks.NewDeliveryCache(time.Hour), webhooks.NewDispatcher())
+	router.POST("/webhooks/github", handler.ReceiveGitHubWebhook)
+
+	req, _ := http.NewRequest("POST", "/webhooks/github", bytes.NewBufferString(`{}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/events"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/metrics"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// Policies applied when a WebSocket client's buffer is full
+const (
+	dropOldest = "drop_oldest"
+	dropNewest = "drop_newest"
+	disconnect = "disconnect"
+)
+
+const (
+	// maxClientMessageSize bounds the control messages a client may send
+	maxClientMessageSize = 16 << 10
+	// maxStreamSubscriptions bounds the subscriptions of a single connection
+	maxStreamSubscriptions = 50
+	// writeWait is the time allowed to write a message to a client
+	writeWait = 10 * time.Second
+)
+
+var (
+	wsConnections     = metrics.Default.NewGauge("websocket_connections", "Open WebSocket stream connections")
+	wsDropped         = metrics.Default.NewCounter("websocket_messages_dropped_total", "Messages dropped because a WebSocket client fell behind", "policy")
+	wsSlowDisconnects = metrics.Default.NewCounter("websocket_slow_consumer_disconnects_total", "WebSocket clients disconnected for falling behind")
+)
+
+// WebSocketHandler multiplexes live repository event subscriptions over a WebSocket
+type WebSocketHandler struct {
+	broker       *events.Broker
+	poller       *events.Poller
+	owner        string
+	pingInterval time.Duration
+	bufferSize   int
+	dropPolicy   string
+	upgrader     websocket.Upgrader
+	policy       *policy.Engine
+}
+
+// NewWebSocketHandler creates a new WebSocketHandler. Poller may be nil when
+// the broker is fed by the webhook receiver, and engine may be nil when no
+// access policy is configured.
+func NewWebSocketHandler(broker *events.Broker, poller *events.Poller, owner string, streams config.StreamConfig, allowOrigins []string, engine *policy.Engine) *WebSocketHandler {
+	dropPolicy := streams.DropPolicy
+	if dropPolicy != dropOldest && dropPolicy != dropNewest && dropPolicy != disconnect {
+		logrus.WithField("policy", dropPolicy).Warn("Unknown stream drop policy, using drop_oldest")
+		dropPolicy = dropOldest
+	}
+
+	return &WebSocketHandler{
+		broker:       broker,
+		poller:       poller,
+		owner:        owner,
+		pingInterval: streams.HeartbeatInterval,
+		bufferSize:   streams.ClientBufferSize,
+		dropPolicy:   dropPolicy,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return originAllowed(r.Header.Get("Origin"), allowOrigins)
+			},
+		},
+		policy: engine,
+	}
+}
+
+// StreamEvents handles GET /github/events/ws
+func (h *WebSocketHandler) StreamEvents(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		logrus.WithError(err).Warn("WebSocket upgrade failed")
+		return
+	}
+
+	client := &wsClient{
+		handler:   h,
+		principal: middleware.GetPrincipal(c),
+		conn:      conn,
+		send:      make(chan []byte, h.bufferSize),
+		done:      make(chan struct{}),
+		subs:      make(map[string]*wsSubscription),
+	}
+
+	wsConnections.Add(1)
+	defer wsConnections.Add(-1)
+
+	go client.writePump()
+	client.readPump()
+	client.shutdown()
+}
+
+// wsClient is a single WebSocket connection and its subscriptions
+type wsClient struct {
+	handler   *WebSocketHandler
+	principal *auth.Principal
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	once      sync.Once
+
+	mu        sync.Mutex
+	closed    bool
+	dropped   uint64
+	lastAcked uint64
+	subs      map[string]*wsSubscription
+}
+
+type wsSubscription struct {
+	sub     *events.Subscription
+	release func()
+}
+
+// readPump processes control messages until the connection fails or closes
+func (c *wsClient) readPump() {
+	pongWait := 2 * c.handler.pingInterval
+	c.conn.SetReadLimit(maxClientMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var msg models.StreamClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.enqueue(models.StreamServerMessage{Type: models.StreamError, Error: "Invalid message format"})
+			continue
+		}
+
+		switch msg.Type {
+		case models.StreamSubscribe:
+			c.subscribe(msg)
+		case models.StreamUnsubscribe:
+			c.unsubscribe(msg.ID)
+		case models.StreamAck:
+			c.mu.Lock()
+			if msg.EventID > c.lastAcked {
+				c.lastAcked = msg.EventID
+			}
+			c.mu.Unlock()
+		case models.StreamPing:
+			c.enqueue(models.StreamServerMessage{Type: models.StreamPong, ID: msg.ID})
+		default:
+			c.enqueue(models.StreamServerMessage{Type: models.StreamError, ID: msg.ID, Error: "Unknown message type"})
+		}
+	}
+}
+
+// writePump writes queued messages and keepalive pings until the connection
+// shuts down. It is the connection's only writer.
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(c.handler.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case payload := <-c.send:
+			if err := c.write(payload); err != nil {
+				c.shutdown()
+				return
+			}
+
+			// Tell the client what it missed so it can resubscribe from its last ack
+			c.mu.Lock()
+			dropped, lastAcked := c.dropped, c.lastAcked
+			c.dropped = 0
+			c.mu.Unlock()
+			if dropped > 0 {
+				notice, _ := json.Marshal(models.StreamServerMessage{Type: models.StreamDropped, Count: dropped, LastAckedID: lastAcked})
+				if err := c.write(notice); err != nil {
+					c.shutdown()
+					return
+				}
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.shutdown()
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) write(payload []byte) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteMessage(websocket.TextMessage, payload)
+}
+
+// subscribe registers a subscription and queues its backlog
+func (c *wsClient) subscribe(msg models.StreamClientMessage) {
+	filter, err := c.subscriptionFilter(msg)
+	if err != nil {
+		c.enqueue(models.StreamServerMessage{Type: models.StreamError, ID: msg.ID, Error: err.Error()})
+		return
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	if _, exists := c.subs[msg.ID]; exists {
+		c.mu.Unlock()
+		c.enqueue(models.StreamServerMessage{Type: models.StreamError, ID: msg.ID, Error: "Subscription ID is already in use"})
+		return
+	}
+	if len(c.subs) >= maxStreamSubscriptions {
+		c.mu.Unlock()
+		c.enqueue(models.StreamServerMessage{Type: models.StreamError, ID: msg.ID, Error: "Too many subscriptions"})
+		return
+	}
+
+	release := func() {}
+	if c.handler.poller != nil {
+		release = c.handler.poller.Watch(msg.Repo)
+	}
+	sub, backlog := c.handler.broker.Subscribe(filter, msg.LastEventID)
+	c.subs[msg.ID] = &wsSubscription{sub: sub, release: release}
+	c.mu.Unlock()
+
+	c.enqueue(models.StreamServerMessage{Type: models.StreamSubscribed, ID: msg.ID})
+	for _, event := range backlog {
+		c.enqueueEvent(msg.ID, event)
+	}
+
+	// Live messages wait in the subscription until the backlog is queued
+	go func() {
+		for event := range sub.C {
+			c.enqueueEvent(msg.ID, event)
+		}
+	}()
+}
+
+// subscriptionFilter validates a subscribe message and builds its filter
+func (c *wsClient) subscriptionFilter(msg models.StreamClientMessage) (events.Filter, error) {
+	if msg.ID == "" {
+		return events.Filter{}, errors.New("Subscription ID is required")
+	}
+	if msg.Repo == "" {
+		return events.Filter{}, errors.New("Repository is required")
+	}
+	// Subscriptions name their repository, so the route-level policy check cannot cover them
+	if c.handler.policy != nil {
+		decision := c.handler.policy.Evaluate(policy.Request{Principal: c.principal, Repo: msg.Repo, Operation: auth.ScopeRead})
+		if !decision.Allowed {
+			return events.Filter{}, errors.New("Access denied by policy")
+		}
+	}
+	return newStreamFilter(c.handler.owner, msg.Repo, msg.Types, msg.Actions)
+}
+
+// unsubscribe removes a subscription
+func (c *wsClient) unsubscribe(id string) {
+	c.mu.Lock()
+	s, ok := c.subs[id]
+	delete(c.subs, id)
+	c.mu.Unlock()
+
+	if !ok {
+		c.enqueue(models.StreamServerMessage{Type: models.StreamError, ID: id, Error: "Subscription not found"})
+		return
+	}
+
+	s.sub.Close()
+	s.release()
+	c.enqueue(models.StreamServerMessage{Type: models.StreamUnsubscribed, ID: id})
+}
+
+// enqueueEvent queues an event for a subscription
+func (c *wsClient) enqueueEvent(id string, event events.Message) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logrus.WithError(err).WithField("event_id", event.ID).Error("Failed to encode stream event")
+		return
+	}
+	c.enqueue(models.StreamServerMessage{Type: models.StreamEvent, ID: id, Event: data})
+}
+
+// enqueue queues a message for the writer, applying the drop policy when
+// the client's buffer is full rather than blocking the publisher
+func (c *wsClient) enqueue(msg models.StreamServerMessage) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to encode stream message")
+		return
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+
+	select {
+	case c.send <- payload:
+		c.mu.Unlock()
+		return
+	default:
+	}
+
+	switch c.handler.dropPolicy {
+	case dropNewest:
+		c.dropped++
+		wsDropped.Inc(dropNewest)
+	case dropOldest:
+		select {
+		case <-c.send:
+			c.dropped++
+			wsDropped.Inc(dropOldest)
+		default:
+		}
+		// The writer may have taken the freed slot, or there was none to
+		// free, so drop the new message rather than block while holding mu
+		select {
+		case c.send <- payload:
+		default:
+			c.dropped++
+			wsDropped.Inc(dropOldest)
+		}
+	case disconnect:
+		c.mu.Unlock()
+		wsSlowDisconnects.Inc()
+		c.shutdown()
+		return
+	}
+	c.mu.Unlock()
+}
+
+// shutdown closes the connection and releases all subscriptions
+func (c *wsClient) shutdown() {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		subs := c.subs
+		c.subs = make(map[string]*wsSubscription)
+		c.mu.Unlock()
+
+		close(c.done)
+		for _, s := range subs {
+			s.sub.Close()
+			s.release()
+		}
+		if c.conn != nil {
+			c.conn.Close()
+		}
+	})
+}
+
+// originAllowed reports whether a cross-origin WebSocket request matches the
+// CORS configuration. Requests without an Origin header are not from browsers.
+func originAllowed(origin string, allowOrigins []string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range allowOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
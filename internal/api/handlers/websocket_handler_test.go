@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/events"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamEventsProtocol tests subscribing, resuming, live delivery, ping and unsubscribing
+func TestStreamEventsProtocol(t *testing.T) {
+	broker := events.NewBroker(10, 8)
+	broker.Publish(events.Message{Type: "push", Repository: "test-user/test-repo"})
+	broker.Publish(events.Message{Type: "issues", Action: "opened", Repository: "test-user/test-repo"})
+
+	router := SetupTestRouter()
+	handler := NewWebSocketHandler(broker, nil, "test-user", config.StreamConfig{
+		HeartbeatInterval: time.Second,
+		ClientBufferSize:  16,
+		DropPolicy:        "drop_oldest",
+	}, []string{"*"}, nil)
+	router.GET("/github/events/ws", handler.StreamEvents)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/github/events/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(msg models.StreamClientMessage) {
+		require.NoError(t, conn.WriteJSON(msg))
+	}
+	receive := func() models.StreamServerMessage {
+		t.Helper()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var msg models.StreamServerMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+
+	// Invalid subscriptions are rejected
+	send(models.StreamClientMessage{Type: models.StreamSubscribe, ID: "bad", Repo: "test-repo", Types: []string{"workflow_run"}})
+	msg := receive()
+	assert.Equal(t, models.StreamError, msg.Type)
+	assert.Equal(t, "bad", msg.ID)
+
+	// The backlog after the last event ID is sent after the confirmation
+	send(models.StreamClientMessage{Type: models.StreamSubscribe, ID: "issues", Repo: "test-repo", Types: []string{"issues"}, LastEventID: 1})
+	assert.Equal(t, models.StreamServerMessage{Type: models.StreamSubscribed, ID: "issues"}, receive())
+	msg = receive()
+	assert.Equal(t, models.StreamEvent, msg.Type)
+	assert.Equal(t, "issues", msg.ID)
+	var event events.Message
+	require.NoError(t, json.Unmarshal(msg.Event, &event))
+	assert.Equal(t, uint64(2), event.ID)
+
+	send(models.StreamClientMessage{Type: models.StreamSubscribe, ID: "other", Repo: "other-repo"})
+	assert.Equal(t, models.StreamSubscribed, receive().Type)
+
+	// Live events are routed to the matching subscription
+	broker.Publish(events.Message{Type: "push", Repository: "test-user/test-repo"})
+	broker.Publish(events.Message{Type: "release", Repository: "test-user/other-repo"})
+	msg = receive()
+	assert.Equal(t, "other", msg.ID)
+	require.NoError(t, json.Unmarshal(msg.Event, &event))
+	assert.Equal(t, uint64(4), event.ID)
+
+	send(models.StreamClientMessage{Type: models.StreamAck, EventID: 4})
+	send(models.StreamClientMessage{Type: models.StreamPing, ID: "p1"})
+	assert.Equal(t, models.StreamServerMessage{Type: models.StreamPong, ID: "p1"}, receive())
+
+	send(models.StreamClientMessage{Type: models.StreamUnsubscribe, ID: "other"})
+	assert.Equal(t, models.StreamServerMessage{Type: models.StreamUnsubscribed, ID: "other"}, receive())
+	send(models.StreamClientMessage{Type: models.StreamUnsubscribe, ID: "other"})
+	assert.Equal(t, models.StreamError, receive().Type)
+
+	send(models.StreamClientMessage{Type: "shout"})
+	assert.Equal(t, models.StreamError, receive().Type)
+}
+
+// TestStreamEventsOrigin tests that cross-origin upgrades follow the CORS configuration
+func TestStreamEventsOrigin(t *testing.T) {
+	router := SetupTestRouter()
+	handler := NewWebSocketHandler(events.NewBroker(10, 8), nil, "test-user", config.StreamConfig{
+		HeartbeatInterval: time.Second,
+		ClientBufferSize:  16,
+	}, []string{"https://app.example.com"}, nil)
+	router.GET("/github/events/ws", handler.StreamEvents)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/github/events/ws"
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, map[string][]string{"Origin": {"https://evil.example.com"}})
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, map[string][]string{"Origin": {"https://app.example.com"}})
+	require.NoError(t, err)
+	conn.Close()
+}
+
+// TestClientDropPolicy tests the drop policies applied when a client's buffer is full
+func TestClientDropPolicy(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name            string
+		policy          string
+		bufferSize      int
+		expectedQueue   []string
+		expectedDropped uint64
+		expectedClosed  bool
+	}{
+		{
+			name:            "Drop Oldest",
+			policy:          dropOldest,
+			bufferSize:      2,
+			expectedQueue:   []string{"2", "3"},
+			expectedDropped: 1,
+		},
+		{
+			name:            "Drop Oldest Without Room",
+			policy:          dropOldest,
+			expectedDropped: 3,
+		},
+		{
+			name:            "Drop Newest",
+			policy:          dropNewest,
+			bufferSize:      2,
+			expectedQueue:   []string{"1", "2"},
+			expectedDropped: 1,
+		},
+		{
+			name:           "Disconnect",
+			policy:         disconnect,
+			bufferSize:     2,
+			expectedQueue:  []string{"1", "2"},
+			expectedClosed: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &wsClient{
+				handler: &WebSocketHandler{dropPolicy: tc.policy},
+				send:    make(chan []byte, tc.bufferSize),
+				done:    make(chan struct{}),
+				subs:    make(map[string]*wsSubscription),
+			}
+			before := wsDropped.Value(tc.policy)
+
+			for _, id := range []string{"1", "2", "3"} {
+				client.enqueue(models.StreamServerMessage{Type: models.StreamPong, ID: id})
+			}
+
+			var queued []string
+			for len(client.send) > 0 {
+				var msg models.StreamServerMessage
+				require.NoError(t, json.Unmarshal(<-client.send, &msg))
+				queued = append(queued, msg.ID)
+			}
+
+			assert.Equal(t, tc.expectedQueue, queued)
+			assert.Equal(t, tc.expectedDropped, client.dropped)
+			assert.Equal(t, float64(tc.expectedDropped), wsDropped.Value(tc.policy)-before)
+			assert.Equal(t, tc.expectedClosed, client.closed)
+		})
+	}
+}
+
+// TestSubscriptionPolicy tests that subscriptions are checked against the policy regardless of repository case
+func TestSubscriptionPolicy(t *testing.T) {
+	engine, err := policy.Parse([]byte(`
+default: allow
+rules:
+  - effect: deny
+    repos: [secret-repo]
+`))
+	require.NoError(t, err)
+	client := &wsClient{handler: &WebSocketHandler{owner: "test-user", policy: engine}}
+
+	for _, repo := range []string{"secret-repo", "Secret-Repo"} {
+		_, err := client.subscriptionFilter(models.StreamClientMessage{ID: "1", Repo: repo})
+		assert.EqualError(t, err, "Access denied by policy", repo)
+	}
+	_, err = client.subscriptionFilter(models.StreamClientMessage{ID: "1", Repo: "website"})
+	assert.NoError(t, err)
+}
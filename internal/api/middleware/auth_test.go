@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticAuthenticator accepts a fixed set of tokens
+type staticAuthenticator map[string]*auth.Principal
+
+func (a staticAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	token := r.Header.Get("X-API-Key")
+	if token == "" {
+		return nil, auth.ErrNoCredentials
+	}
+	if principal, ok := a[token]; ok {
+		return principal, nil
+	}
+	return nil, auth.ErrInvalidCredentials
+}
+
+// TestAuthenticate tests route scopes, including the admin default for unlisted routes
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate([]auth.Authenticator{staticAuthenticator{
+		"reader": {ID: "k1", Scopes: []string{auth.ScopeRead}},
+		"admin":  {ID: "k2", Scopes: []string{auth.ScopeAdmin}},
+	}}, auth.RouteScopes{
+		"GET /health":       auth.ScopePublic,
+		"GET /github/:repo": auth.ScopeRead,
+	}))
+
+	ok := func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, principal.ID)
+	}
+	router.GET("/health", ok)
+	router.GET("/github/:repo", ok)
+	router.DELETE("/github/:repo", ok)
+
+	// Test cases
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		key            string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Public Route", method: "GET", path: "/health", expectedStatus: http.StatusOK, expectedBody: "anonymous"},
+		{name: "Missing Credentials", method: "GET", path: "/github/test-repo", expectedStatus: http.StatusUnauthorized},
+		{name: "Invalid Credentials", method: "GET", path: "/github/test-repo", key: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "Scope Granted", method: "GET", path: "/github/test-repo", key: "reader", expectedStatus: http.StatusOK, expectedBody: "k1"},
+		{name: "Unlisted Route Needs Admin", method: "DELETE", path: "/github/test-repo", key: "reader", expectedStatus: http.StatusForbidden},
+		{name: "Admin Allowed", method: "DELETE", path: "/github/test-repo", key: "admin", expectedStatus: http.StatusOK, expectedBody: "k2"},
+		{name: "Unknown Path", method: "GET", path: "/nowhere", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, resp.Body.String())
+			}
+			if tc.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, resp.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+// TestAuthorize tests that the policy is evaluated against the route's repository and scope
+func TestAuthorize(t *testing.T) {
+	engine, err := policy.Parse([]byte(`
+rules:
+  - effect: allow
+    principals: [k1]
+    repos: ["public-*"]
+  - effect: deny
+    repos: [Public-Secrets]
+`))
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(principalKey, &auth.Principal{ID: c.GetHeader("X-Principal")})
+	})
+	router.Use(Authorize(engine, auth.RouteScopes{
+		"GET /health":       auth.ScopePublic,
+		"GET /github/:repo": auth.ScopeRead,
+	}))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/github/:repo", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Test cases
+	tests := []struct {
+		name           string
+		path           string
+		principal      string
+		expectedStatus int
+	}{
+		{name: "Public Route", path: "/health", principal: "k2", expectedStatus: http.StatusOK},
+		{name: "Allowed Repository", path: "/github/public-site", principal: "k1", expectedStatus: http.StatusOK},
+		{name: "Other Repository", path: "/github/private-site", principal: "k1", expectedStatus: http.StatusForbidden},
+		{name: "Other Principal", path: "/github/public-site", principal: "k2", expectedStatus: http.StatusForbidden},
+		{name: "Denied Repository", path: "/github/public-secrets", principal: "k1", expectedStatus: http.StatusForbidden},
+		{name: "Denied Repository Mixed Case", path: "/github/PUBLIC-secrets", principal: "k1", expectedStatus: http.StatusForbidden},
+		{name: "Allowed Repository Mixed Case", path: "/github/Public-Site", principal: "k1", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set("X-Principal", tc.principal)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+		})
+	}
+}
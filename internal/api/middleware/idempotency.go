@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/idempotency"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxIdempotencyKeyLength bounds the keys clients may send
+	maxIdempotencyKeyLength = 255
+	// DefaultIdempotentBodySize bounds the request bodies read for
+	// fingerprinting on routes without a larger limit of their own
+	DefaultIdempotentBodySize = 1 << 20
+)
+
+// responseRecorder captures a response while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotency is a middleware that honours the Idempotency-Key header. The
+// first request with a key runs and its response is stored; retries with the
+// same key, query and body receive the stored response. Server errors are not
+// stored, so a retry after one runs the request again. The body is read to
+// fingerprint it, so maxBodySize should match the most the route's handler accepts.
+func Idempotency(store *idempotency.Store, maxBodySize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Idempotency-Key must be at most 255 characters",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodySize+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Invalid request body",
+			})
+			return
+		}
+		if int64(len(body)) > maxBodySize {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Error: "Request body is too large",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the caller and route so that clients cannot collide
+		client := "ip:" + c.ClientIP()
+		if principal := GetPrincipal(c); principal != nil {
+			client = "principal:" + principal.ID
+		}
+		scopedKey := client + " " + c.Request.Method + " " + c.Request.URL.Path + " " + key
+
+		stored, err := store.Begin(scopedKey, fingerprint(c.Request, body))
+		switch {
+		case errors.Is(err, idempotency.ErrInFlight):
+			c.AbortWithStatusJSON(http.StatusConflict, models.ErrorResponse{
+				Error: "A request with this Idempotency-Key is still in progress",
+			})
+			return
+		case errors.Is(err, idempotency.ErrMismatch):
+			c.AbortWithStatusJSON(http.StatusConflict, models.ErrorResponse{
+				Error: "Idempotency-Key was already used with a different request",
+			})
+			return
+		case stored != nil:
+			for name, values := range stored.Header {
+				for _, value := range values {
+					c.Writer.Header().Add(name, value)
+				}
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Status(stored.Status)
+			c.Writer.Write(stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			// Release the key if the handler panicked so that retries are not stuck in flight
+			if !completed {
+				store.Release(scopedKey)
+			}
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			store.Release(scopedKey)
+		} else {
+			store.Complete(scopedKey, &idempotency.Response{
+				Status: c.Writer.Status(),
+				Header: http.Header{"Content-Type": c.Writer.Header().Values("Content-Type")},
+				Body:   recorder.body.Bytes(),
+			})
+		}
+		completed = true
+	}
+}
+
+// fingerprint identifies a request by its query and body. The query is part
+// of it because parameters such as duplicate_check change what the request
+// does. Multipart bodies are fingerprinted by their parts, since clients pick
+// a new boundary for every attempt.
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.URL.Query().Encode())
+	hash.Write([]byte{0})
+	if parts, ok := multipartFingerprint(r.Header.Get("Content-Type"), body); ok {
+		io.WriteString(hash, parts)
+	} else {
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// multipartFingerprint hashes the name, file name and contents of each part
+// of a multipart form, reporting false if the body is not one
+func multipartFingerprint(contentType string, body []byte) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return "", false
+	}
+
+	hash := sha256.New()
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return hex.EncodeToString(hash.Sum(nil)), true
+		}
+		if err != nil {
+			return "", false
+		}
+		contents := sha256.New()
+		if _, err := io.Copy(contents, part); err != nil {
+			return "", false
+		}
+		fmt.Fprintf(hash, "%q %q %x\n", part.FormName(), part.FileName(), contents.Sum(nil))
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/idempotency"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIdempotency tests that retries replay the stored response and conflicting reuse is rejected
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	calls := 0
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router.POST("/issues", Idempotency(idempotency.NewStore(time.Hour), 2<<20), func(c *gin.Context) {
+		calls++
+		switch c.Query("mode") {
+		case "slow":
+			close(started)
+			<-release
+		case "fail":
+			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"number": calls})
+	})
+
+	sendMultipart := func(key, boundary, attachment string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.SetBoundary(boundary))
+		require.NoError(t, writer.WriteField("title", "Bug"))
+		file, err := writer.CreateFormFile("attachments", "screenshot.png")
+		require.NoError(t, err)
+		file.Write([]byte(attachment))
+		require.NoError(t, writer.Close())
+
+		req, _ := http.NewRequest("POST", "/issues", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Idempotency-Key", key)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	send := func(key, query, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/issues"+query, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	first := send("k1", "", `{"title":"Bug"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	// A retry replays the response without running the handler
+	retry := send("k1", "", `{"title":"Bug"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	resp := send("k1", "", `{"title":"Other"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), "different request")
+
+	// The query is part of the request, so changing it is a conflict too
+	resp = send("k1", "?duplicate_check=comment", `{"title":"Bug"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Equal(t, 1, calls)
+
+	// Requests without a key always run
+	send("", "", `{"title":"Bug"}`)
+	assert.Equal(t, 2, calls)
+
+	// Server errors are not stored
+	assert.Equal(t, http.StatusBadGateway, send("k2", "?mode=fail", `{}`).Code)
+	assert.Equal(t, http.StatusBadGateway, send("k2", "?mode=fail", `{}`).Code)
+	assert.Equal(t, 4, calls)
+
+	assert.Equal(t, http.StatusBadRequest, send(strings.Repeat("k", 256), "", `{}`).Code)
+
+	// Bodies up to the route's limit are accepted, such as issues with attachments
+	large := strings.Repeat("a", 3<<19)
+	assert.Equal(t, http.StatusCreated, send("k4", "", large).Code)
+	replayed := send("k4", "", large)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("k5", "", strings.Repeat("a", 2<<20+1)).Code)
+	assert.Equal(t, 5, calls)
+
+	// A retried upload matches even though its multipart boundary changed
+	assert.Equal(t, http.StatusCreated, sendMultipart("k6", "first-boundary", "png bytes").Code)
+	replayed = sendMultipart("k6", "second-boundary", "png bytes")
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusConflict, sendMultipart("k6", "third-boundary", "other bytes").Code)
+	assert.Equal(t, 6, calls)
+
+	// A retry while the first request runs is a conflict
+	done := make(chan struct{})
+	go func() {
+		send("k3", "?mode=slow", `{}`)
+		close(done)
+	}()
+	<-started
+	resp = send("k3", "?mode=slow", `{}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), "still in progress")
+	close(release)
+	<-done
+}
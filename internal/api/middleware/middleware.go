@@ -32,6 +32,11 @@ func Logger() gin.HandlerFunc {
 			"path":        path,
 		})
 
+		// Identify the caller by credential ID only
+		if principal := GetPrincipal(c); principal != nil {
+			entry = entry.WithField("principal", principal.ID)
+		}
+
 		// Log based on status code
 		if statusCode >= 500 {
 			entry.Error("Server error")
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Authorize is a middleware that evaluates the access policy for the
+// caller, the route's repository and the operation, which is the scope the
+// route requires. It runs after Authenticate.
+func Authorize(engine *policy.Engine, scopes auth.RouteScopes) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+
+		operation := scopes.Lookup(c.Request.Method, route)
+		if operation == auth.ScopePublic {
+			c.Next()
+			return
+		}
+
+		decision := engine.Evaluate(policy.Request{
+			Principal: GetPrincipal(c),
+			Repo:      c.Param("repo"),
+			Operation: operation,
+		})
+		if !decision.Allowed {
+			logrus.WithFields(logrus.Fields{
+				"principal": decision.Principal,
+				"repo":      decision.Repo,
+				"operation": decision.Operation,
+				"rule":      decision.Rule,
+			}).Warn("Denied request by policy")
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error: "Access denied by policy",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
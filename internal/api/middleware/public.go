@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PublicRepos is a middleware for public routes that name a repository. It
+// answers 404 unless the repository is one of repos, compared without regard
+// to case as GitHub does, so that unauthenticated callers cannot read private
+// repositories through the service's token.
+func PublicRepos(repos []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		allowed[strings.ToLower(repo)] = true
+	}
+
+	return func(c *gin.Context) {
+		if !allowed[strings.ToLower(c.Param("repo"))] {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Repository not found",
+			})
+			return
+		}
+		c.Next()
+	}
+}
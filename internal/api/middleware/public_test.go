@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPublicRepos tests that only listed repositories are served
+func TestPublicRepos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/feeds/:repo/issues.atom", PublicRepos([]string{"website"}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Test cases
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "Public Repository", path: "/feeds/website/issues.atom", expectedStatus: http.StatusOK},
+		{name: "Public Repository Mixed Case", path: "/feeds/WebSite/issues.atom", expectedStatus: http.StatusOK},
+		{name: "Private Repository", path: "/feeds/secret-repo/issues.atom", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimit tests route costs, per-client keys, headers and quotas
+func TestRateLimit(t *testing.T) {
+	quotas, err := ratelimit.NewQuotaStore(filepath.Join(t.TempDir(), "quotas.json"), 25)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Principal"); id != "" {
+			c.Set(principalKey, &auth.Principal{ID: id})
+		}
+	})
+	router.Use(RateLimit(ratelimit.NewLimiter(60, 10), quotas, ratelimit.RouteCosts{
+		"GET /health":  0,
+		"POST /issues": 10,
+	}))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/repo", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/issues", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	send := func(method, path, principal string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if principal != "" {
+			req.Header.Set("X-Principal", principal)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Exempt routes carry no headers
+	resp := send("GET", "/health", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+
+	resp = send("GET", "/repo", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "10;w=10, 25;w=86400", resp.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "10", resp.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", resp.Header().Get("RateLimit-Reset"))
+
+	// Issue creation costs more than the anonymous client has left
+	resp = send("POST", "/issues", "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), "Rate limit exceeded")
+
+	// Authenticated callers have their own bucket, and the headers follow the closer limit
+	resp = send("POST", "/issues", "k1")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+
+	quotas.Use("principal:k2", 20)
+	resp = send("POST", "/issues", "k2")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "25", resp.Header().Get("RateLimit-Limit"))
+	assert.Contains(t, resp.Body.String(), "Daily quota exceeded")
+}
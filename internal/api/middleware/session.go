@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// githubTokenKey is the gin context key holding the signed-in user's GitHub token
+const githubTokenKey = "auth.github_token"
+
+// Session is a middleware that makes the GitHub token of a user signed in
+// through the OAuth flow available to handlers. Requests without a valid
+// session continue unchanged and use the service token.
+func Session(sessions *auth.SessionManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if session, err := sessions.FromRequest(c.Request); err == nil {
+			c.Set(githubTokenKey, session.Token)
+		}
+		c.Next()
+	}
+}
+
+// GetGitHubToken returns the signed-in user's GitHub token, or an empty
+// string when the request has no session
+func GetGitHubToken(c *gin.Context) string {
+	return c.GetString(githubTokenKey)
+}
@@ -1,22 +1,44 @@
 package routes
 
 import (
+	"strings"
+
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/handlers"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/middleware"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/attachments"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/bulkissues"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/events"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/fanout"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/feedback"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/idempotency"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/metrics"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/policy"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/ratelimit"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
-// SetupRouter configures the API routes
-func SetupRouter(config *config.Config) *gin.Engine {
+// SetupRouter configures the API routes. The returned function stops the
+// background workers and saves their state; call it once the server has stopped.
+func SetupRouter(config *config.Config) (*gin.Engine, func()) {
 	// Set Gin mode
 	gin.SetMode(config.Server.GinMode)
 
 	// Create router with default middleware
 	router := gin.New()
 
+	// Only believe X-Forwarded-For from configured proxies, as client IPs
+	// key the rate limits of unauthenticated callers
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		logrus.WithError(err).Fatal("Invalid trusted proxies")
+	}
+
 	// Add custom middleware
 	router.Use(middleware.Logger())
 	router.Use(middleware.ErrorHandler())
@@ -25,14 +47,242 @@ func SetupRouter(config *config.Config) *gin.Engine {
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = config.CORS.AllowOrigins
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "Idempotency-Key"}
+	corsConfig.ExposeHeaders = []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Idempotent-Replayed"}
 	router.Use(cors.New(corsConfig))
 
+	// Declare the scope each route requires; routes that are not listed
+	// require admin
+	routeScopes := auth.RouteScopes{
+		"GET /health":              auth.ScopePublic,
+		"GET /cards/profile.svg":   auth.ScopePublic,
+		"GET /feeds/activity.atom": auth.ScopePublic,
+		// Repository badges and feeds are limited to PUBLIC_REPOS
+		"GET /badges/:repo/:metric":      auth.ScopePublic,
+		"GET /feeds/:repo/releases.atom": auth.ScopePublic,
+		"GET /feeds/:repo/issues.atom":   auth.ScopePublic,
+		// Attachment keys are unguessable and GitHub fetches them to show images
+		"GET /attachments/*key": auth.ScopePublic,
+		// Webhook deliveries are authenticated by their signature
+		"POST /webhooks/github": auth.ScopePublic,
+		// The login flow is how callers obtain a session
+		"GET /auth/github/login":    auth.ScopePublic,
+		"GET /auth/github/callback": auth.ScopePublic,
+		"GET /auth/github/session":  auth.ScopePublic,
+		"POST /auth/github/logout":  auth.ScopePublic,
+		// The feedback form has its own spam protection
+		"GET /feedback/:repo/form": auth.ScopePublic,
+		"POST /feedback/:repo":     auth.ScopePublic,
+
+		"GET /metrics":                                          auth.ScopeRead,
+		"GET /github":                                           auth.ScopeRead,
+		"GET /github/:repo":                                     auth.ScopeRead,
+		"GET /github/events/ws":                                 auth.ScopeRead,
+		"GET /github/:repo/events/stream":                       auth.ScopeRead,
+		"GET /github/:repo/issue-templates":                     auth.ScopeRead,
+		"GET /github/:repo/actions/workflows":                   auth.ScopeRead,
+		"GET /github/:repo/actions/runs":                        auth.ScopeRead,
+		"GET /github/:repo/actions/runs/:run_id/jobs":           auth.ScopeRead,
+		"GET /github/:repo/actions/runs/:run_id/logs":           auth.ScopeRead,
+		"GET /github/:repo/deployments":                         auth.ScopeRead,
+		"GET /github/:repo/deployments/:deployment_id/statuses": auth.ScopeRead,
+		"GET /jobs/:id":                                         auth.ScopeRead,
+		"GET /jobs/:id/errors.csv":                              auth.ScopeRead,
+
+		"POST /github/:repo/issues":                           auth.ScopeIssuesWrite,
+		"POST /github/:repo/issue-templates/:template/issues": auth.ScopeIssuesWrite,
+		"POST /github/:repo/issues/bulk":                      auth.ScopeIssuesWrite,
+		"DELETE /jobs/:id":                                    auth.ScopeIssuesWrite,
+	}
+
+	// Authenticate callers with API keys and SSO tokens when configured
+	var authenticators []auth.Authenticator
+	var apiKeys *auth.KeyStore
+	if config.Auth.APIKeysPath != "" {
+		var err error
+		apiKeys, err = auth.NewKeyStore(config.Auth.APIKeysPath)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load API keys")
+		}
+		authenticators = append(authenticators, apiKeys)
+	}
+	if config.Auth.JWKS != "" {
+		groupScopes, err := auth.ParseGroupScopes(config.Auth.JWTGroupScopes)
+		if err != nil {
+			logrus.WithError(err).Fatal("Invalid JWT group scopes")
+		}
+		jwtAuthenticator, err := auth.NewJWTAuthenticator(auth.JWTOptions{
+			JWKS:        config.Auth.JWKS,
+			Issuer:      config.Auth.JWTIssuer,
+			Audience:    config.Auth.JWTAudience,
+			GroupsClaim: config.Auth.JWTGroupsClaim,
+			GroupScopes: groupScopes,
+			CacheTTL:    config.Auth.JWKSCacheTTL,
+			Leeway:      config.Auth.JWTLeeway,
+		})
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to configure JWT authentication")
+		}
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+
+	// Sign users in with GitHub so that their requests carry their own token
+	var sessions *auth.SessionManager
+	if config.OAuth.ClientID != "" {
+		var err error
+		sessions, err = auth.NewSessionManager(config.OAuth.SessionSecret, config.OAuth.SessionTTL, strings.HasPrefix(config.OAuth.RedirectURL, "https://"), config.OAuth.SessionScopes)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to configure GitHub sessions")
+		}
+		// Sessions only count as credentials when authentication is enabled
+		if len(authenticators) > 0 {
+			authenticators = append(authenticators, sessions)
+		}
+	}
+
+	if len(authenticators) > 0 {
+		router.Use(middleware.Authenticate(authenticators, routeScopes))
+	} else {
+		logrus.Warn("API authentication is disabled; set AUTH_API_KEYS_PATH or AUTH_JWKS to enable it")
+	}
+	if sessions != nil {
+		router.Use(middleware.Session(sessions))
+	}
+
+	// Limit each client's use of the shared GitHub budget. Routes that are
+	// not listed cost one token; writes cost more and issue creation most.
+	// Bulk imports are also charged per row against the daily quota.
+	routeCosts := ratelimit.RouteCosts{
+		"GET /health":           0,
+		"GET /metrics":          0,
+		"POST /webhooks/github": 0,
+		"GET /attachments/*key": 0,
+
+		"POST /github/:repo/issues":                           10,
+		"POST /github/:repo/issue-templates/:template/issues": 10,
+		"POST /feedback/:repo":                                10,
+		"POST /github/:repo/issues/bulk":                      10,
+
+		"POST /github/:repo/actions/workflows/:workflow/dispatches":          5,
+		"POST /github/:repo/actions/runs/:run_id/rerun-failed-jobs":          5,
+		"POST /github/:repo/actions/runs/:run_id/cancel":                     5,
+		"POST /github/:repo/statuses/:sha":                                   5,
+		"POST /github/:repo/check-runs":                                      5,
+		"PATCH /github/:repo/check-runs/:check_run_id":                       5,
+		"POST /github/:repo/deployments":                                     5,
+		"POST /github/:repo/deployments/:deployment_id/statuses":             5,
+		"POST /github/:repo/deployments/:deployment_id/complete":             5,
+		"POST /github/:repo/hooks":                                           5,
+		"POST /github/:repo/hooks/ensure":                                    5,
+		"PATCH /github/:repo/hooks/:hook_id":                                 5,
+		"DELETE /github/:repo/hooks/:hook_id":                                5,
+		"POST /github/:repo/hooks/:hook_id/pings":                            5,
+		"POST /github/:repo/hooks/:hook_id/deliveries/:delivery_id/attempts": 5,
+	}
+	var quotas *ratelimit.QuotaStore
+	if config.RateLimit.PerMinute > 0 {
+		if maxCost := routeCosts.Max(); config.RateLimit.Burst < maxCost {
+			logrus.WithField("max_cost", maxCost).Fatal("RATE_LIMIT_BURST must cover the most expensive route")
+		}
+		if config.RateLimit.DailyQuota > 0 {
+			var err error
+			quotas, err = ratelimit.NewQuotaStore(config.RateLimit.QuotaPath, config.RateLimit.DailyQuota)
+			if err != nil {
+				logrus.WithError(err).Fatal("Failed to load rate limit quotas")
+			}
+			quotas.Start()
+		}
+		limiter := ratelimit.NewLimiter(config.RateLimit.PerMinute, config.RateLimit.Burst)
+		router.Use(middleware.RateLimit(limiter, quotas, routeCosts))
+	}
+
+	// Load the per-repository access policy when configured
+	var policyEngine *policy.Engine
+	if config.Policy.Path != "" {
+		var err error
+		policyEngine, err = policy.Load(config.Policy.Path)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load access policy")
+		}
+	}
+
 	// Create services
-	var githubService services.GitHubServiceInterface = services.NewGitHubService(config)
+	tokens, err := services.NewTokenSource(config.GitHub)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to configure GitHub authentication")
+	}
+	var githubService services.GitHubServiceInterface = services.NewGitHubServiceWithTokens(config, tokens)
+
+	// Create webhook dispatcher; in-process consumers register on it
+	webhookDispatcher := webhooks.NewDispatcher()
+	webhookDeliveries := webhooks.NewDeliveryCache(config.Webhooks.DeliveryTTL)
+
+	// Store files uploaded with issues
+	attachmentStore, err := attachments.NewStore(config.Attachments)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to configure attachment store")
+	}
+	var uploader *attachments.Uploader
+	if attachmentStore != nil {
+		uploader = attachments.NewUploader(attachmentStore, config.Attachments)
+	}
+
+	// Remember responses so that retried writes are not run twice. Issues
+	// may carry attachments, so their bodies can be larger.
+	idempotencyKeys := idempotency.NewStore(config.Idempotency.TTL)
+	issueBodySize := int64(middleware.DefaultIdempotentBodySize)
+	if uploader != nil {
+		issueBodySize = uploader.MaxRequestSize()
+	}
+
+	// Run slow work such as bulk imports in the background
+	jobManager, err := jobs.NewManager(config.Jobs)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to open job database")
+	}
+	jobManager.Register(bulkissues.JobType, bulkissues.NewHandler(githubService, config.BulkIssues.Interval, config.BulkIssues.RateLimitBackoff))
+	jobManager.Start()
+
+	// Forward webhook events to downstream subscribers
+	fanoutService, err := fanout.NewService(config.Fanout)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load fan-out state")
+	}
+	fanoutService.Start()
+	webhookDispatcher.Register(webhooks.AllEvents, fanoutService.HandleEvent)
+
+	// Publish repository activity to live streams, polling the events API
+	// when webhooks are not delivered to this service
+	eventBroker := events.NewBroker(config.Streams.HistorySize, config.Streams.BufferSize)
+	var eventPoller *events.Poller
+	switch config.Streams.Source {
+	case events.SourceWebhook:
+		webhookDispatcher.Register(webhooks.AllEvents, eventBroker.HandleWebhook)
+	case events.SourcePoll:
+		eventPoller = events.NewPoller(githubService, eventBroker, config.Streams.PollInterval)
+	default:
+		if config.Webhooks.Secret != "" {
+			webhookDispatcher.Register(webhooks.AllEvents, eventBroker.HandleWebhook)
+		} else {
+			eventPoller = events.NewPoller(githubService, eventBroker, config.Streams.PollInterval)
+		}
+	}
 
 	// Create handlers
-	githubHandler := handlers.NewGitHubHandler(githubService)
+	githubHandler := handlers.NewGitHubHandler(githubService, uploader)
+	badgeHandler := handlers.NewBadgeHandler(githubService, config.Badges)
+	feedHandler := handlers.NewFeedHandler(githubService, config.GitHub.Username)
+	actionsHandler := handlers.NewActionsHandler(githubService)
+	issueTemplatesHandler := handlers.NewIssueTemplatesHandler(githubService)
+	bulkIssuesHandler := handlers.NewBulkIssuesHandler(jobManager, config.BulkIssues.MaxRows, routeCosts.Lookup("POST", "/github/:repo/issues"))
+	jobsHandler := handlers.NewJobsHandler(jobManager, policyEngine)
+	checksHandler := handlers.NewChecksHandler(githubService)
+	deploymentsHandler := handlers.NewDeploymentsHandler(githubService)
+	hooksHandler := handlers.NewHooksHandler(githubService)
+	webhookHandler := handlers.NewWebhookHandler(config.Webhooks.Secret, webhookDeliveries, webhookDispatcher)
+	fanoutHandler := handlers.NewFanoutHandler(fanoutService)
+	streamHandler := handlers.NewStreamHandler(eventBroker, eventPoller, config.GitHub.Username, config.Streams.HeartbeatInterval)
+	webSocketHandler := handlers.NewWebSocketHandler(eventBroker, eventPoller, config.GitHub.Username, config.Streams, config.CORS.AllowOrigins, policyEngine)
 
 	// Add health check route
 	router.GET("/health", func(c *gin.Context) {
@@ -41,13 +291,167 @@ func SetupRouter(config *config.Config) *gin.Engine {
 		})
 	})
 
+	// Add metrics route
+	router.GET("/metrics", gin.WrapH(metrics.Default.Handler()))
+
 	// GitHub routes
 	githubGroup := router.Group("/github")
+	if policyEngine != nil {
+		githubGroup.Use(middleware.Authorize(policyEngine, routeScopes))
+	}
 	{
 		githubGroup.GET("", githubHandler.GetUserProfile)
+		githubGroup.GET("/events/ws", webSocketHandler.StreamEvents)
 		githubGroup.GET("/:repo", githubHandler.GetRepository)
-		githubGroup.POST("/:repo/issues", githubHandler.CreateIssue)
+		githubGroup.POST("/:repo/issues", middleware.Idempotency(idempotencyKeys, issueBodySize), githubHandler.CreateIssue)
+		githubGroup.POST("/:repo/issues/bulk", middleware.Idempotency(idempotencyKeys, bulkIssuesHandler.MaxRequestSize()), bulkIssuesHandler.CreateIssues)
+		githubGroup.GET("/:repo/events/stream", streamHandler.StreamRepositoryEvents)
+
+		// Issue template routes
+		githubGroup.GET("/:repo/issue-templates", issueTemplatesHandler.ListIssueTemplates)
+		githubGroup.POST("/:repo/issue-templates/:template/issues", middleware.Idempotency(idempotencyKeys, middleware.DefaultIdempotentBodySize), issueTemplatesHandler.CreateTemplateIssue)
+
+		// GitHub Actions routes
+		githubGroup.GET("/:repo/actions/workflows", actionsHandler.ListWorkflows)
+		githubGroup.POST("/:repo/actions/workflows/:workflow/dispatches", actionsHandler.DispatchWorkflow)
+		githubGroup.GET("/:repo/actions/runs", actionsHandler.ListWorkflowRuns)
+		githubGroup.GET("/:repo/actions/runs/:run_id/jobs", actionsHandler.ListWorkflowRunJobs)
+		githubGroup.GET("/:repo/actions/runs/:run_id/logs", actionsHandler.DownloadWorkflowRunLogs)
+		githubGroup.POST("/:repo/actions/runs/:run_id/rerun-failed-jobs", actionsHandler.RerunFailedJobs)
+		githubGroup.POST("/:repo/actions/runs/:run_id/cancel", actionsHandler.CancelWorkflowRun)
+
+		// Commit status and check run routes
+		githubGroup.POST("/:repo/statuses/:sha", checksHandler.CreateCommitStatus)
+		githubGroup.POST("/:repo/check-runs", checksHandler.CreateCheckRun)
+		githubGroup.PATCH("/:repo/check-runs/:check_run_id", checksHandler.UpdateCheckRun)
+
+		// Deployment routes
+		githubGroup.POST("/:repo/deployments", deploymentsHandler.CreateDeployment)
+		githubGroup.GET("/:repo/deployments", deploymentsHandler.ListDeployments)
+		githubGroup.POST("/:repo/deployments/:deployment_id/statuses", deploymentsHandler.CreateDeploymentStatus)
+		githubGroup.GET("/:repo/deployments/:deployment_id/statuses", deploymentsHandler.ListDeploymentStatuses)
+		githubGroup.POST("/:repo/deployments/:deployment_id/complete", deploymentsHandler.CompleteDeployment)
+
+		// Repository webhook routes
+		githubGroup.GET("/:repo/hooks", hooksHandler.ListHooks)
+		githubGroup.POST("/:repo/hooks", hooksHandler.CreateHook)
+		githubGroup.POST("/:repo/hooks/ensure", hooksHandler.EnsureHook)
+		githubGroup.PATCH("/:repo/hooks/:hook_id", hooksHandler.UpdateHook)
+		githubGroup.DELETE("/:repo/hooks/:hook_id", hooksHandler.DeleteHook)
+		githubGroup.POST("/:repo/hooks/:hook_id/pings", hooksHandler.PingHook)
+		githubGroup.GET("/:repo/hooks/:hook_id/deliveries", hooksHandler.ListHookDeliveries)
+		githubGroup.POST("/:repo/hooks/:hook_id/deliveries/:delivery_id/attempts", hooksHandler.RedeliverHookDelivery)
+	}
+
+	// Badge and card routes. Repository badges and feeds bypass
+	// authentication and the policy, so they are limited to repositories
+	// that are meant to be public.
+	publicRepos := middleware.PublicRepos(config.Badges.PublicRepos)
+	router.GET("/badges/:repo/:metric", publicRepos, badgeHandler.GetRepositoryBadge)
+	router.GET("/cards/profile.svg", badgeHandler.GetProfileCard)
+
+	// Feed routes
+	feedGroup := router.Group("/feeds")
+	{
+		feedGroup.GET("/activity.atom", feedHandler.GetActivityFeed)
+		feedGroup.GET("/:repo/releases.atom", publicRepos, feedHandler.GetReleasesFeed)
+		feedGroup.GET("/:repo/issues.atom", publicRepos, feedHandler.GetIssuesFeed)
+	}
+
+	// Webhook routes
+	router.POST("/webhooks/github", webhookHandler.ReceiveGitHubWebhook)
+
+	// Attachment routes, when this service keeps the files itself
+	if localStore, ok := attachmentStore.(*attachments.LocalStore); ok {
+		attachmentsHandler := handlers.NewAttachmentsHandler(localStore)
+		router.GET("/attachments/*key", attachmentsHandler.GetAttachment)
+	}
+
+	// Public feedback form routes
+	if len(config.Feedback.Repos) > 0 {
+		forms, err := feedback.NewForms(config.Feedback.FormSecret, config.Feedback.MinFillTime, config.Feedback.FormMaxAge)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to configure feedback forms")
+		}
+		captcha, err := feedback.NewVerifier(config.Feedback.Captcha, config.Feedback.CaptchaSecret)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to configure feedback CAPTCHA")
+		}
+		throttle := ratelimit.NewLimiter(config.Feedback.PerMinute, config.Feedback.Burst)
+
+		feedbackHandler := handlers.NewFeedbackHandler(githubService, config.Feedback, forms, captcha, throttle)
+		feedbackGroup := router.Group("/feedback")
+		{
+			feedbackGroup.GET("/:repo/form", feedbackHandler.GetForm)
+			feedbackGroup.POST("/:repo", feedbackHandler.SubmitFeedback)
+		}
+	}
+
+	// API key management routes
+	if apiKeys != nil {
+		apiKeyHandler := handlers.NewAPIKeyHandler(apiKeys, config.Auth.DefaultRotationOverlap)
+		keysGroup := router.Group("/auth/keys")
+		{
+			keysGroup.POST("", apiKeyHandler.CreateKey)
+			keysGroup.GET("", apiKeyHandler.ListKeys)
+			keysGroup.POST("/:id/rotate", apiKeyHandler.RotateKey)
+			keysGroup.DELETE("/:id", apiKeyHandler.RevokeKey)
+		}
+	}
+
+	// GitHub login routes
+	if sessions != nil {
+		oauthHandler := handlers.NewOAuthHandler(auth.NewOAuthClient(auth.OAuthOptions{
+			ClientID:     config.OAuth.ClientID,
+			ClientSecret: config.OAuth.ClientSecret,
+			RedirectURL:  config.OAuth.RedirectURL,
+			Scopes:       config.OAuth.Scopes,
+		}), sessions, strings.HasPrefix(config.OAuth.RedirectURL, "https://"))
+		oauthGroup := router.Group("/auth/github")
+		{
+			oauthGroup.GET("/login", oauthHandler.Login)
+			oauthGroup.GET("/callback", oauthHandler.Callback)
+			oauthGroup.GET("/session", oauthHandler.GetSession)
+			oauthGroup.POST("/logout", oauthHandler.Logout)
+		}
+	}
+
+	// Access policy routes
+	if policyEngine != nil {
+		policyHandler := handlers.NewPolicyHandler(policyEngine)
+		router.GET("/policy/explain", policyHandler.Explain)
+	}
+
+	// Background job routes
+	jobsGroup := router.Group("/jobs")
+	{
+		jobsGroup.GET("/:id", jobsHandler.GetJob)
+		jobsGroup.GET("/:id/errors.csv", jobsHandler.GetErrorReport)
+		jobsGroup.DELETE("/:id", jobsHandler.CancelJob)
+	}
+
+	// Fan-out subscription routes
+	fanoutGroup := router.Group("/fanout")
+	{
+		fanoutGroup.POST("/subscriptions", fanoutHandler.CreateSubscription)
+		fanoutGroup.GET("/subscriptions", fanoutHandler.ListSubscriptions)
+		fanoutGroup.GET("/subscriptions/:id", fanoutHandler.GetSubscription)
+		fanoutGroup.DELETE("/subscriptions/:id", fanoutHandler.DeleteSubscription)
+		fanoutGroup.GET("/dead-letters", fanoutHandler.ListDeadLetters)
+		fanoutGroup.POST("/dead-letters/replay", fanoutHandler.ReplayAllDeadLetters)
+		fanoutGroup.POST("/dead-letters/:id/replay", fanoutHandler.ReplayDeadLetter)
+	}
+
+	// Interrupted jobs and pending deliveries resume on the next start
+	shutdown := func() {
+		jobManager.Stop()
+		fanoutService.Stop()
+		if quotas != nil {
+			if err := quotas.Stop(); err != nil {
+				logrus.WithError(err).Error("Failed to persist rate limit quotas")
+			}
+		}
 	}
 
-	return router
+	return router, shutdown
 }
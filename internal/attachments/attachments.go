@@ -0,0 +1,181 @@
+package attachments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+)
+
+// FileError describes an uploaded file that was refused
+type FileError struct {
+	Name   string
+	Reason string
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("attachment %q %s", e.Name, e.Reason)
+}
+
+// Attachment is a stored file to be linked from an issue
+type Attachment struct {
+	Name        string
+	ContentType string
+	Size        int64
+	URL         string
+}
+
+// Uploader validates files uploaded with an issue and saves them in a store
+type Uploader struct {
+	store        Store
+	maxFileSize  int64
+	maxFiles     int
+	allowedTypes map[string]bool
+}
+
+// NewUploader creates an Uploader saving files in store
+func NewUploader(store Store, cfg config.AttachmentsConfig) *Uploader {
+	allowedTypes := make(map[string]bool)
+	for _, contentType := range cfg.AllowedTypes {
+		allowedTypes[strings.ToLower(contentType)] = true
+	}
+
+	return &Uploader{
+		store:        store,
+		maxFileSize:  cfg.MaxFileSize,
+		maxFiles:     cfg.MaxFiles,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// MaxRequestSize returns the largest request body that can carry the allowed
+// files, allowing room for the other fields
+func (u *Uploader) MaxRequestSize() int64 {
+	return int64(u.maxFiles)*u.maxFileSize + 1<<20
+}
+
+// Upload validates every file, then stores them under the repository. The
+// type of each file is detected from its content rather than trusted from the client.
+func (u *Uploader) Upload(ctx context.Context, repoName string, files []*multipart.FileHeader) ([]Attachment, error) {
+	if len(files) > u.maxFiles {
+		return nil, &FileError{Name: files[u.maxFiles].Filename, Reason: fmt.Sprintf("exceeds the limit of %d files", u.maxFiles)}
+	}
+
+	contents := make([][]byte, len(files))
+	attachments := make([]Attachment, len(files))
+	for i, file := range files {
+		name := sanitizeName(file.Filename)
+		if file.Size > u.maxFileSize {
+			return nil, &FileError{Name: name, Reason: fmt.Sprintf("is larger than %d bytes", u.maxFileSize)}
+		}
+
+		data, err := readFile(file, u.maxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > u.maxFileSize {
+			return nil, &FileError{Name: name, Reason: fmt.Sprintf("is larger than %d bytes", u.maxFileSize)}
+		}
+		if len(data) == 0 {
+			return nil, &FileError{Name: name, Reason: "is empty"}
+		}
+
+		contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+		if !u.allowedTypes[contentType] {
+			return nil, &FileError{Name: name, Reason: fmt.Sprintf("has type %s, which is not allowed", contentType)}
+		}
+
+		contents[i] = data
+		attachments[i] = Attachment{Name: name, ContentType: contentType, Size: int64(len(data))}
+	}
+
+	for i := range attachments {
+		id, err := randomID()
+		if err != nil {
+			return nil, err
+		}
+		key := repoName + "/" + id + "/" + attachments[i].Name
+		url, err := u.store.Put(ctx, key, attachments[i].ContentType, contents[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to store %s: %w", attachments[i].Name, err)
+		}
+		attachments[i].URL = url
+	}
+	return attachments, nil
+}
+
+// Markdown renders attachments for an issue body: images are embedded and
+// other files linked
+func Markdown(attachments []Attachment) string {
+	var b strings.Builder
+	b.WriteString("### Attachments\n")
+	for _, attachment := range attachments {
+		b.WriteString("\n")
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			fmt.Fprintf(&b, "![%s](%s)\n", attachment.Name, attachment.URL)
+			continue
+		}
+		fmt.Fprintf(&b, "- [%s](%s) (%s, %s)\n", attachment.Name, attachment.URL, attachment.ContentType, formatSize(attachment.Size))
+	}
+	return b.String()
+}
+
+// readFile reads an uploaded file, stopping one byte past limit
+func readFile(file *multipart.FileHeader, limit int64) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file.Filename, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Filename, err)
+	}
+	return data, nil
+}
+
+// sanitizeName reduces a client supplied file name to a safe base name
+func sanitizeName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// randomID returns a random identifier that keeps stored files unguessable
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate attachment ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// formatSize formats a size in bytes for people
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -0,0 +1,181 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3Timeout bounds each upload
+	s3Timeout = 30 * time.Second
+	// sigV4Algorithm identifies AWS Signature Version 4
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+)
+
+// S3Options configures an S3Store
+type S3Options struct {
+	// Endpoint is the S3 API URL, e.g. https://s3.eu-west-1.amazonaws.com or a MinIO server
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL is where the bucket's objects are fetched from; it defaults to the endpoint
+	PublicURL string
+}
+
+// S3Store keeps files in a bucket of S3 or an S3-compatible service.
+// Requests use path-style addressing, which every compatible service supports.
+type S3Store struct {
+	options   S3Options
+	endpoint  string
+	publicURL string
+	client    *http.Client
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewS3Store creates an S3Store
+func NewS3Store(options S3Options) (*S3Store, error) {
+	if options.Endpoint == "" || options.Bucket == "" || options.AccessKeyID == "" || options.SecretAccessKey == "" {
+		return nil, errors.New("the S3 attachment store requires an endpoint, bucket and credentials")
+	}
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+
+	endpoint := strings.TrimSuffix(options.Endpoint, "/")
+	publicURL := strings.TrimSuffix(options.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint + "/" + options.Bucket
+	}
+
+	return &S3Store{
+		options:   options,
+		endpoint:  endpoint,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: s3Timeout},
+		nowFunc:   time.Now,
+	}, nil
+}
+
+// Put implements Store, uploading the file with a PutObject request
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	path := "/" + uriEncode(s.options.Bucket, true) + "/" + uriEncode(key, false)
+	req, err := http.NewRequestWithContext(ctx, "PUT", s.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	sum := sha256.Sum256(data)
+	s.sign(req, hex.EncodeToString(sum[:]))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload attachment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("S3 returned %d: %s", resp.StatusCode, body)
+	}
+	return s.publicURL + "/" + uriEncode(key, false), nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header covering the
+// host and every header already set on req
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := s.nowFunc().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.options.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretAccessKey), date)
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.options.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery sorts and encodes query parameters as Signature Version 4 requires
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except unreserved characters, and
+// slashes unless encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,93 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+)
+
+// Blob store backends selected by config.AttachmentsConfig.Store
+const (
+	StoreNone  = ""
+	StoreLocal = "local"
+	StoreS3    = "s3"
+)
+
+// ErrInvalidKey is returned for keys that would escape the store
+var ErrInvalidKey = errors.New("invalid attachment key")
+
+// Store saves uploaded files and returns the URL they can be fetched from
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+}
+
+// NewStore creates the configured store, or returns nil when attachments are disabled
+func NewStore(cfg config.AttachmentsConfig) (Store, error) {
+	switch cfg.Store {
+	case StoreNone:
+		return nil, nil
+	case StoreLocal:
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL)
+	case StoreS3:
+		return NewS3Store(S3Options{
+			Endpoint:        cfg.S3Endpoint,
+			Bucket:          cfg.S3Bucket,
+			Region:          cfg.S3Region,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PublicURL:       cfg.PublicURL,
+		})
+	}
+	return nil, fmt.Errorf("unknown attachment store %q", cfg.Store)
+}
+
+// LocalStore keeps files in a directory that this service serves
+type LocalStore struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStore creates a LocalStore. Files are linked under publicURL, the
+// absolute URL the directory is served at.
+func NewLocalStore(dir, publicURL string) (*LocalStore, error) {
+	if publicURL == "" {
+		return nil, errors.New("the local attachment store requires a public URL")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	return &LocalStore{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}, nil
+}
+
+// Put implements Store
+func (s *LocalStore) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write attachment: %w", err)
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+// Path returns the file a key is stored in
+func (s *LocalStore) Path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || clean != "/"+strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
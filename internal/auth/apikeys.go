@@ -0,0 +1,291 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// apiKeyPrefix starts every API key so that keys are recognisable in
+// Authorization headers and by secret scanners
+const apiKeyPrefix = "gak_"
+
+// ErrKeyNotFound is returned when an API key ID does not exist
+var ErrKeyNotFound = errors.New("API key not found")
+
+// APIKey is a stored API key. Only the SHA-256 hash of the secret is kept.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// RotatedTo is the ID of the key that replaced this one
+	RotatedTo string `json:"rotated_to,omitempty"`
+}
+
+// activeAt reports whether the key can be used at t
+func (k *APIKey) activeAt(t time.Time) bool {
+	return k.ExpiresAt == nil || t.Before(*k.ExpiresAt)
+}
+
+// KeyStore validates API keys against a file of hashed keys
+type KeyStore struct {
+	store *storage.JSONFile
+
+	mu   sync.RWMutex
+	keys map[string]*APIKey
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewKeyStore creates a KeyStore persisted at path, loading any existing keys
+func NewKeyStore(path string) (*KeyStore, error) {
+	store, err := storage.NewJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &KeyStore{
+		store:   store,
+		keys:    make(map[string]*APIKey),
+		nowFunc: time.Now,
+	}
+	if err := store.Load(&s.keys); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Authenticate implements Authenticator using the X-API-Key header or an
+// Authorization header carrying an API key as a Bearer or ApiKey credential
+func (s *KeyStore) Authenticate(r *http.Request) (*Principal, error) {
+	token := r.Header.Get("X-API-Key")
+	if token == "" {
+		scheme, credential, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.HasPrefix(credential, apiKeyPrefix) {
+			return nil, ErrNoCredentials
+		}
+		if !strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, "ApiKey") {
+			return nil, ErrNoCredentials
+		}
+		token = credential
+	}
+
+	return s.Validate(token)
+}
+
+// Validate checks a plaintext API key and returns its principal
+func (s *KeyStore) Validate(token string) (*Principal, error) {
+	id, secret, ok := parseAPIKey(token)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	s.mu.RLock()
+	key, exists := s.keys[id]
+	s.mu.RUnlock()
+
+	// Hash even for unknown IDs so that timing does not reveal which IDs exist
+	sum := sha256.Sum256([]byte(secret))
+	hash := hex.EncodeToString(sum[:])
+	if !exists {
+		return nil, ErrInvalidCredentials
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) != 1 {
+		logrus.WithField("key_id", id).Warn("Rejected API key with invalid secret")
+		return nil, ErrInvalidCredentials
+	}
+	if !key.activeAt(s.nowFunc()) {
+		logrus.WithField("key_id", id).Warn("Rejected expired API key")
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Principal{
+		ID:     "key:" + key.ID,
+		Name:   key.Name,
+		Method: "api_key",
+		Scopes: append([]string(nil), key.Scopes...),
+	}, nil
+}
+
+// CreateKey creates a key and returns it with its plaintext, which is not
+// stored and cannot be recovered. A zero ttl creates a key that does not expire.
+func (s *KeyStore) CreateKey(name string, scopes []string, ttl time.Duration) (*APIKey, string, error) {
+	for _, scope := range scopes {
+		if !ValidScope(scope) {
+			return nil, "", fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, token, err := s.newKey(name, scopes, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.keys[key.ID] = key
+	if err := s.save(); err != nil {
+		delete(s.keys, key.ID)
+		return nil, "", err
+	}
+
+	logrus.WithFields(logrus.Fields{"key_id": key.ID, "name": name}).Info("Created API key")
+	copied := *key
+	return &copied, token, nil
+}
+
+// RotateKey replaces a key with a new one carrying the same name and scopes.
+// The old key keeps working for overlap so that clients can switch without
+// downtime; a zero overlap retires it immediately.
+func (s *KeyStore) RotateKey(id string, overlap time.Duration) (*APIKey, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.keys[id]
+	if !ok || !old.activeAt(s.nowFunc()) {
+		return nil, "", ErrKeyNotFound
+	}
+
+	var ttl time.Duration
+	if old.ExpiresAt != nil {
+		ttl = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+	key, token, err := s.newKey(old.Name, old.Scopes, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	previous := *old
+	retireAt := s.nowFunc().Add(overlap)
+	if old.ExpiresAt == nil || retireAt.Before(*old.ExpiresAt) {
+		old.ExpiresAt = &retireAt
+	}
+	old.RotatedTo = key.ID
+	s.keys[key.ID] = key
+
+	if err := s.save(); err != nil {
+		*old = previous
+		delete(s.keys, key.ID)
+		return nil, "", err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"key_id":      key.ID,
+		"replaces":    id,
+		"retires_at":  old.ExpiresAt,
+		"overlap_sec": int(overlap.Seconds()),
+	}).Info("Rotated API key")
+	copied := *key
+	return &copied, token, nil
+}
+
+// RevokeKey deletes a key immediately
+func (s *KeyStore) RevokeKey(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	delete(s.keys, id)
+	if err := s.save(); err != nil {
+		s.keys[id] = key
+		return err
+	}
+
+	logrus.WithField("key_id", id).Info("Revoked API key")
+	return nil
+}
+
+// ListKeys returns all keys, including those that have expired, ordered by creation time
+func (s *KeyStore) ListKeys() []APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key)
+	}
+	// Keys are read from a map, so keys created at the same instant, such as
+	// during a rotation, are ordered by ID to keep the listing stable
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// newKey generates a key and its plaintext; the caller must hold s.mu
+func (s *KeyStore) newKey(name string, scopes []string, ttl time.Duration) (*APIKey, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	now := s.nowFunc().UTC()
+	key := &APIKey{
+		ID:        id,
+		Name:      name,
+		Hash:      hex.EncodeToString(sum[:]),
+		Scopes:    append([]string(nil), scopes...),
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	return key, apiKeyPrefix + id + "_" + secret, nil
+}
+
+// save persists the keys; the caller must hold s.mu
+func (s *KeyStore) save() error {
+	return s.store.Save(s.keys)
+}
+
+// parseAPIKey splits a plaintext key into its ID and secret
+func parseAPIKey(token string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(token, apiKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
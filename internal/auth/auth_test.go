@@ -0,0 +1,471 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyStoreValidate tests key creation, validation and hashed persistence
+func TestKeyStoreValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := NewKeyStore(path)
+	require.NoError(t, err)
+
+	_, _, err = store.CreateKey("ci", []string{"write"}, 0)
+	assert.Error(t, err)
+
+	key, token, err := store.CreateKey("ci", []string{ScopeIssuesWrite}, 0)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "gak_"+key.ID+"_"))
+
+	// Only the hash reaches the disk
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), strings.TrimPrefix(token, "gak_"+key.ID+"_"))
+	assert.Contains(t, string(data), key.Hash)
+
+	// Keys survive a reload
+	reloaded, err := NewKeyStore(path)
+	require.NoError(t, err)
+
+	principal, err := reloaded.Validate(token)
+	require.NoError(t, err)
+	assert.Equal(t, "key:"+key.ID, principal.ID)
+	assert.True(t, principal.HasScope(ScopeIssuesWrite))
+	assert.False(t, principal.HasScope(ScopeRead))
+
+	// Test cases
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "Wrong Secret", token: "gak_" + key.ID + "_deadbeef"},
+		{name: "Unknown ID", token: "gak_0000000000000000_deadbeef"},
+		{name: "Malformed", token: "gak_" + key.ID},
+		{name: "Wrong Prefix", token: strings.TrimPrefix(token, "gak_")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := reloaded.Validate(tc.token)
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+}
+
+// TestKeyStoreRotation tests that a rotated key keeps working for the overlap only
+func TestKeyStoreRotation(t *testing.T) {
+	store, err := NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC)
+	store.nowFunc = func() time.Time { return now }
+
+	old, oldToken, err := store.CreateKey("deploy", []string{ScopeAdmin}, 0)
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+
+	rotated, newToken, err := store.RotateKey(old.ID, time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.ID, rotated.ID)
+	assert.Equal(t, []string{ScopeAdmin}, rotated.Scopes)
+
+	// Both keys work during the overlap
+	_, err = store.Validate(oldToken)
+	assert.NoError(t, err)
+	_, err = store.Validate(newToken)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = store.Validate(oldToken)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = store.Validate(newToken)
+	assert.NoError(t, err)
+
+	// A retired key cannot be rotated again
+	_, _, err = store.RotateKey(old.ID, time.Hour)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	keys := store.ListKeys()
+	require.Len(t, keys, 2)
+	assert.Equal(t, rotated.ID, keys[0].RotatedTo)
+
+	require.NoError(t, store.RevokeKey(rotated.ID))
+	_, err = store.Validate(newToken)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorIs(t, store.RevokeKey(rotated.ID), ErrKeyNotFound)
+}
+
+// TestListKeysOrder tests that keys created at the same instant are listed in a stable order
+func TestListKeysOrder(t *testing.T) {
+	store, err := NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC)
+	store.nowFunc = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		_, _, err := store.CreateKey("deploy", []string{ScopeRead}, 0)
+		require.NoError(t, err)
+	}
+
+	keys := store.ListKeys()
+	for i := 1; i < len(keys); i++ {
+		assert.Less(t, keys[i-1].ID, keys[i].ID)
+	}
+}
+
+// TestKeyStoreAuthenticate tests the headers API keys are read from
+func TestKeyStoreAuthenticate(t *testing.T) {
+	store, err := NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+	_, token, err := store.CreateKey("reader", []string{ScopeRead}, 0)
+	require.NoError(t, err)
+
+	// Test cases
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		expectedError error
+	}{
+		{name: "X-API-Key", header: "X-API-Key", value: token},
+		{name: "Bearer", header: "Authorization", value: "Bearer " + token},
+		{name: "ApiKey Scheme", header: "Authorization", value: "ApiKey " + token},
+		{name: "Other Bearer Token", header: "Authorization", value: "Bearer eyJhbGciOi", expectedError: ErrNoCredentials},
+		{name: "Basic", header: "Authorization", value: "Basic " + token, expectedError: ErrNoCredentials},
+		{name: "Missing", expectedError: ErrNoCredentials},
+		{name: "Invalid X-API-Key", header: "X-API-Key", value: "nope", expectedError: ErrInvalidCredentials},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/github", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+
+			principal, err := store.Authenticate(req)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "reader", principal.Name)
+		})
+	}
+}
+
+// testJWKS generates signing keys and serves them as a JWKS
+type testJWKS struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func newTestJWKS(t *testing.T) *testJWKS {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testJWKS{rsaKey: rsaKey, ecKey: ecKey}
+}
+
+func (j *testJWKS) document() []byte {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	data, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kid": "rsa-1", "kty": "RSA", "use": "sig", "n": encode(j.rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(j.rsaKey.E)).Bytes())},
+			{"kid": "ec-1", "kty": "EC", "crv": "P-256", "x": encode(j.ecKey.X.FillBytes(make([]byte, 32))), "y": encode(j.ecKey.Y.FillBytes(make([]byte, 32)))},
+			{"kid": "enc-1", "kty": "RSA", "use": "enc", "n": encode(j.rsaKey.N.Bytes()), "e": "AQAB"},
+		},
+	})
+	return data
+}
+
+func (j *testJWKS) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	var key interface{} = j.rsaKey
+	if _, ok := method.(*jwt.SigningMethodECDSA); ok {
+		key = j.ecKey
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// TestJWTAuthenticator tests validation of SSO tokens and the mapping of groups to scopes
+func TestJWTAuthenticator(t *testing.T) {
+	keys := newTestJWKS(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keys.document(), 0o600))
+
+	groupScopes, err := ParseGroupScopes("platform=admin; support=read,issues:write;readers=read")
+	require.NoError(t, err)
+
+	authenticator, err := NewJWTAuthenticator(JWTOptions{
+		JWKS:        path,
+		Issuer:      "https://sso.example.com",
+		Audience:    "github-api-service",
+		GroupScopes: groupScopes,
+	})
+	require.NoError(t, err)
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":    "https://sso.example.com",
+			"aud":    "github-api-service",
+			"sub":    "alice",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": []string{"support", "readers", "unmapped"},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	// Test cases
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedScopes []string
+		expectedError  error
+	}{
+		{
+			name:           "RSA Token",
+			authorization:  "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims(nil)),
+			expectedScopes: []string{ScopeRead, ScopeIssuesWrite},
+		},
+		{
+			name:           "EC Token With Audience List",
+			authorization:  "Bearer " + keys.sign(t, jwt.SigningMethodES256, "ec-1", claims(jwt.MapClaims{"aud": []string{"other", "github-api-service"}, "groups": "platform"})),
+			expectedScopes: []string{ScopeAdmin},
+		},
+		{
+			name:          "Wrong Issuer",
+			authorization: "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "Wrong Audience",
+			authorization: "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims(jwt.MapClaims{"aud": "other"})),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "Expired",
+			authorization: "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "Missing Expiry",
+			authorization: "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims(jwt.MapClaims{"exp": nil})),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "Encryption Key",
+			authorization: "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "enc-1", claims(nil)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "Symmetric Algorithm",
+			authorization: "Bearer " + hmacToken(t, claims(nil)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "API Key",
+			authorization: "Bearer gak_0123_secret",
+			expectedError: ErrNoCredentials,
+		},
+		{
+			name:          "Missing",
+			expectedError: ErrNoCredentials,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/github", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			principal, err := authenticator.Authenticate(req)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "jwt:alice", principal.ID)
+			assert.Equal(t, "jwt", principal.Method)
+			assert.ElementsMatch(t, tc.expectedScopes, principal.Scopes)
+		})
+	}
+}
+
+// hmacToken signs claims with HS256 using a guessable secret
+func hmacToken(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "rsa-1"
+	signed, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return signed
+}
+
+// TestKeySetCaching tests that a JWKS URL is cached and refetched on expiry or unknown key IDs
+func TestKeySetCaching(t *testing.T) {
+	keys := newTestJWKS(t)
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(keys.document())
+	}))
+	defer server.Close()
+
+	ks, err := newKeySet(server.URL, time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	ks.nowFunc = func() time.Time { return now }
+	assert.Equal(t, 1, fetches)
+
+	// Known keys are served from the cache
+	_, err = ks.get("rsa-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	// Unknown keys refetch at most once per interval
+	now = now.Add(2 * jwksMinRefresh)
+	_, err = ks.get("rsa-2")
+	assert.Error(t, err)
+	_, err = ks.get("rsa-2")
+	assert.Error(t, err)
+	assert.Equal(t, 2, fetches)
+
+	// Expired sets are refetched
+	now = now.Add(time.Hour)
+	_, err = ks.get("ec-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fetches)
+
+	_, err = newKeySet(filepath.Join(t.TempDir(), "missing.json"), time.Hour)
+	assert.Error(t, err)
+}
+
+// TestSessionManager tests that sessions survive a round trip and reject tampering, other keys and expiry
+func TestSessionManager(t *testing.T) {
+	_, err := NewSessionManager("short", time.Hour, true, nil)
+	assert.Error(t, err)
+
+	secret := strings.Repeat("s", 32)
+	sessions, err := NewSessionManager(secret, time.Hour, true, []string{ScopeRead})
+	require.NoError(t, err)
+	now := time.Now()
+	sessions.nowFunc = func() time.Time { return now }
+
+	value, err := sessions.Encode(sessions.NewSession("octocat", "gho_user"))
+	require.NoError(t, err)
+	assert.NotContains(t, value, "gho_user")
+
+	session, err := sessions.Decode(value)
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", session.Login)
+	assert.Equal(t, "gho_user", session.Token)
+
+	// A flipped byte fails authentication
+	sealed, _ := base64.RawURLEncoding.DecodeString(value)
+	sealed[len(sealed)-1] ^= 1
+	_, err = sessions.Decode(base64.RawURLEncoding.EncodeToString(sealed))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	other, err := NewSessionManager(strings.Repeat("o", 32), time.Hour, true, nil)
+	require.NoError(t, err)
+	_, err = other.Decode(value)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// The cookie authenticates the user with the configured scopes
+	req := httptest.NewRequest("GET", "/github", nil)
+	_, err = sessions.Authenticate(req)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: value})
+	principal, err := sessions.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, "github:octocat", principal.ID)
+	assert.Equal(t, []string{ScopeRead}, principal.Scopes)
+
+	now = now.Add(time.Hour)
+	_, err = sessions.Authenticate(req)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// TestOAuthClient tests the authorize URL, code exchange and user lookup against a fake GitHub
+func TestOAuthClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/oauth/access_token":
+			assert.Equal(t, "application/json", r.Header.Get("Accept"))
+			assert.Equal(t, "client-secret", r.FormValue("client_secret"))
+			if r.FormValue("code") != "good-code" {
+				w.Write([]byte(`{"error": "bad_verification_code", "error_description": "The code is incorrect"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "gho_user", "token_type": "bearer"}`))
+		case "/user":
+			if r.Header.Get("Authorization") != "token gho_user" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"login": "octocat"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(OAuthOptions{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://service.example.com/auth/github/callback",
+		Scopes:       []string{"public_repo", "read:org"},
+		TokenURL:     server.URL + "/login/oauth/access_token",
+		UserURL:      server.URL + "/user",
+	})
+
+	authURL := client.AuthCodeURL("xyz")
+	assert.True(t, strings.HasPrefix(authURL, githubAuthorizeURL+"?"))
+	assert.Contains(t, authURL, "state=xyz")
+	assert.Contains(t, authURL, "scope=public_repo+read%3Aorg")
+
+	_, err := client.Exchange("bad-code")
+	assert.ErrorContains(t, err, "bad_verification_code")
+
+	token, err := client.Exchange("good-code")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_user", token)
+
+	login, err := client.Login(token)
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", login)
+
+	_, err = client.Login("gho_other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jwksMinRefresh limits how often an unknown key ID triggers a refetch, so
+// that tokens with made-up key IDs cannot hammer the identity provider
+const jwksMinRefresh = time.Minute
+
+// jwk is a single JSON Web Key as published in a JWKS document
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet caches the public keys of a JWKS loaded from a file or URL
+type keySet struct {
+	source string
+	client *http.Client
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// newKeySet creates a keySet and loads it once. Sources starting with
+// http:// or https:// are fetched, anything else is read as a file.
+func newKeySet(source string, ttl time.Duration) (*keySet, error) {
+	ks := &keySet{
+		source:  source,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		ttl:     ttl,
+		keys:    make(map[string]crypto.PublicKey),
+		nowFunc: time.Now,
+	}
+	if err := ks.refresh(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// get returns the key with the given ID, refreshing the set when it has
+// expired or does not contain the key
+func (ks *keySet) get(kid string) (crypto.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := ks.nowFunc()
+	key, ok := ks.keys[kid]
+	stale := ks.ttl > 0 && now.Sub(ks.fetchedAt) >= ks.ttl
+	if (stale || !ok) && now.Sub(ks.attemptedAt) >= jwksMinRefresh {
+		if err := ks.refreshLocked(); err != nil {
+			// Keep serving the cached keys while the provider is unavailable
+			logrus.WithError(err).Warn("Failed to refresh JWKS")
+		}
+		key, ok = ks.keys[kid]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return key, nil
+}
+
+// refresh reloads the keys
+func (ks *keySet) refresh() error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return ks.refreshLocked()
+}
+
+// refreshLocked reloads the keys; the caller must hold ks.mu
+func (ks *keySet) refreshLocked() error {
+	ks.attemptedAt = ks.nowFunc()
+
+	data, err := ks.load()
+	if err != nil {
+		return err
+	}
+
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range document.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			logrus.WithError(err).WithField("kid", k.Kid).Warn("Skipping unusable JWKS key")
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS from %s contains no usable signing keys", ks.source)
+	}
+
+	ks.keys = keys
+	ks.fetchedAt = ks.attemptedAt
+	return nil
+}
+
+// load reads the raw JWKS document
+func (ks *keySet) load() ([]byte, error) {
+	if !strings.HasPrefix(ks.source, "http://") && !strings.HasPrefix(ks.source, "https://") {
+		data, err := os.ReadFile(ks.source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS: %w", err)
+		}
+		return data, nil
+	}
+
+	resp, err := ks.client.Get(ks.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status code %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// publicKey converts an RSA or EC JWK to a public key
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		// Parsing the uncompressed point rejects points that are not on the curve
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x.Bytes()) > size || len(y.Bytes()) > size {
+			return nil, fmt.Errorf("EC point is not on curve %s", k.Crv)
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("EC point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
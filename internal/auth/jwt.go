@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// jwtAlgorithms are the asymmetric algorithms accepted for SSO tokens.
+// Symmetric algorithms are refused so that a public key can never be used as an HMAC secret.
+var jwtAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWTOptions configures a JWTAuthenticator
+type JWTOptions struct {
+	// JWKS is a URL or file path of the signing keys
+	JWKS     string
+	Issuer   string
+	Audience string
+	// GroupsClaim names the claim listing the caller's groups
+	GroupsClaim string
+	// GroupScopes maps group names to the scopes they grant
+	GroupScopes map[string][]string
+	CacheTTL    time.Duration
+	Leeway      time.Duration
+}
+
+// JWTAuthenticator authenticates bearer JWTs issued by an SSO provider
+type JWTAuthenticator struct {
+	options JWTOptions
+	keys    *keySet
+	parser  *jwt.Parser
+}
+
+// NewJWTAuthenticator creates a JWTAuthenticator and loads its signing keys
+func NewJWTAuthenticator(options JWTOptions) (*JWTAuthenticator, error) {
+	if options.Issuer == "" || options.Audience == "" {
+		return nil, fmt.Errorf("JWT issuer and audience are required")
+	}
+	if options.GroupsClaim == "" {
+		options.GroupsClaim = "groups"
+	}
+	for group, scopes := range options.GroupScopes {
+		for _, scope := range scopes {
+			if !ValidScope(scope) {
+				return nil, fmt.Errorf("unknown scope %q for group %q", scope, group)
+			}
+		}
+	}
+
+	keys, err := newKeySet(options.JWKS, options.CacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTAuthenticator{
+		options: options,
+		keys:    keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(jwtAlgorithms),
+			jwt.WithIssuer(options.Issuer),
+			jwt.WithAudience(options.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(options.Leeway),
+		),
+	}, nil
+}
+
+// Authenticate implements Authenticator for Authorization: Bearer tokens
+// that are not API keys
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.HasPrefix(token, apiKeyPrefix) {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.get(kid)
+	})
+	if err != nil {
+		logrus.WithError(err).Warn("Rejected invalid JWT")
+		return nil, ErrInvalidCredentials
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		logrus.Warn("Rejected JWT without subject")
+		return nil, ErrInvalidCredentials
+	}
+
+	groups := claimStrings(claims[a.options.GroupsClaim])
+	return &Principal{
+		ID:     "jwt:" + subject,
+		Name:   subject,
+		Method: "jwt",
+		Scopes: a.scopesFor(groups),
+		Groups: groups,
+	}, nil
+}
+
+// scopesFor returns the scopes granted to a set of groups
+func (a *JWTAuthenticator) scopesFor(groups []string) []string {
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		for _, scope := range a.options.GroupScopes[group] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// claimStrings reads a claim holding a string or a list of strings
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// ParseGroupScopes parses a mapping such as "platform=admin;support=read,issues:write"
+func ParseGroupScopes(value string) (map[string][]string, error) {
+	mapping := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, scopes, ok := strings.Cut(entry, "=")
+		group = strings.TrimSpace(group)
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid group mapping %q", entry)
+		}
+		for _, scope := range strings.Split(scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				mapping[group] = append(mapping[group], scope)
+			}
+		}
+	}
+	return mapping, nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHub OAuth App endpoints
+const (
+	githubAuthorizeURL = "https://github.com/login/oauth/authorize"
+	githubTokenURL     = "https://github.com/login/oauth/access_token"
+	githubUserURL      = "https://api.github.com/user"
+)
+
+// OAuthOptions configures the GitHub OAuth App login flow
+type OAuthOptions struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback registered with the OAuth App
+	RedirectURL string
+	// Scopes are the GitHub scopes requested from the user
+	Scopes []string
+	// AuthorizeURL, TokenURL and UserURL default to GitHub's endpoints
+	AuthorizeURL string
+	TokenURL     string
+	UserURL      string
+}
+
+// OAuthClient performs the GitHub OAuth App web flow
+type OAuthClient struct {
+	options OAuthOptions
+	client  *http.Client
+}
+
+// NewOAuthClient creates an OAuthClient
+func NewOAuthClient(options OAuthOptions) *OAuthClient {
+	if options.AuthorizeURL == "" {
+		options.AuthorizeURL = githubAuthorizeURL
+	}
+	if options.TokenURL == "" {
+		options.TokenURL = githubTokenURL
+	}
+	if options.UserURL == "" {
+		options.UserURL = githubUserURL
+	}
+
+	return &OAuthClient{
+		options: options,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewState returns a random value binding a callback to the login that started it
+func NewState() (string, error) {
+	return randomHex(16)
+}
+
+// AuthCodeURL returns the URL that asks the user to authorize the app
+func (o *OAuthClient) AuthCodeURL(state string) string {
+	query := url.Values{
+		"client_id":    {o.options.ClientID},
+		"redirect_uri": {o.options.RedirectURL},
+		"state":        {state},
+	}
+	if len(o.options.Scopes) > 0 {
+		query.Set("scope", strings.Join(o.options.Scopes, " "))
+	}
+	return o.options.AuthorizeURL + "?" + query.Encode()
+}
+
+// Exchange trades an authorization code for the user's access token
+func (o *OAuthClient) Exchange(code string) (string, error) {
+	form := url.Values{
+		"client_id":     {o.options.ClientID},
+		"client_secret": {o.options.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {o.options.RedirectURL},
+	}
+	req, err := http.NewRequest("POST", o.options.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to exchange code: status code %d", resp.StatusCode)
+	}
+
+	// GitHub reports a bad code with a 200 response carrying an error
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("failed to exchange code: %s: %s", result.Error, result.ErrorDescription)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("failed to exchange code: no access token returned")
+	}
+
+	return result.AccessToken, nil
+}
+
+// Login returns the login of the user who owns token
+func (o *OAuthClient) Login(token string) (string, error) {
+	req, err := http.NewRequest("GET", o.options.UserURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch user: status code %d", resp.StatusCode)
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to decode user: %w", err)
+	}
+	if user.Login == "" {
+		return "", fmt.Errorf("failed to fetch user: no login returned")
+	}
+
+	return user.Login, nil
+}
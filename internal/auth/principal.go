@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Scopes granted to callers of the service's own endpoints
+const (
+	// ScopePublic marks routes that do not require authentication
+	ScopePublic = "public"
+	// ScopeRead allows reading GitHub data through the service
+	ScopeRead = "read"
+	// ScopeIssuesWrite allows creating and changing issues
+	ScopeIssuesWrite = "issues:write"
+	// ScopeAdmin allows everything, including managing credentials
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists the scopes that can be granted
+var Scopes = []string{ScopeRead, ScopeIssuesWrite, ScopeAdmin}
+
+var (
+	// ErrNoCredentials is returned when a request carries no credentials an authenticator understands
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned when credentials are malformed, unknown, expired or revoked
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	// ID identifies the credential, never the secret itself, and is safe to
+	// log. It is qualified by the method, as key:<key id>, jwt:<subject> or
+	// github:<login>, so that principals of different methods never share one.
+	ID     string
+	Name   string
+	Method string
+	Scopes []string
+	// Groups lists the SSO groups of JWT principals
+	Groups []string
+}
+
+// HasScope reports whether the principal was granted scope. Admin implies every scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator resolves the principal behind a request. It returns
+// ErrNoCredentials when the request carries no credentials it handles, so
+// that several authenticators can be tried in turn.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// RouteScopes maps "METHOD /route/pattern" to the scope the route requires
+type RouteScopes map[string]string
+
+// Lookup returns the scope a route requires. Routes that are not listed
+// require admin, so a route added without an entry is closed rather than open.
+func (s RouteScopes) Lookup(method, route string) string {
+	if scope, ok := s[method+" "+route]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// ValidScope reports whether scope can be granted
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
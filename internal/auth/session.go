@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SessionCookieName is the cookie carrying the encrypted GitHub session
+const SessionCookieName = "gh_session"
+
+// minSessionSecretLength keeps session secrets long enough to resist guessing
+const minSessionSecretLength = 32
+
+// Session is a GitHub user signed in through the OAuth flow
+type Session struct {
+	Login     string    `json:"login"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SessionManager keeps sessions in cookies encrypted with AES-GCM, so the
+// service holds no session state and the user's token is never readable by
+// the browser
+type SessionManager struct {
+	aead   cipher.AEAD
+	ttl    time.Duration
+	secure bool
+	scopes []string
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewSessionManager creates a SessionManager. The encryption key is derived
+// from secret, and scopes are granted to signed-in users when the service
+// requires authentication. Secure cookies are only sent over HTTPS.
+func NewSessionManager(secret string, ttl time.Duration, secure bool, scopes []string) (*SessionManager, error) {
+	if len(secret) < minSessionSecretLength {
+		return nil, fmt.Errorf("session secret must be at least %d characters", minSessionSecretLength)
+	}
+	for _, scope := range scopes {
+		if !ValidScope(scope) {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionManager{
+		aead:    aead,
+		ttl:     ttl,
+		secure:  secure,
+		scopes:  scopes,
+		nowFunc: time.Now,
+	}, nil
+}
+
+// NewSession creates a session for a user that expires after the session TTL
+func (m *SessionManager) NewSession(login, token string) *Session {
+	return &Session{
+		Login:     login,
+		Token:     token,
+		ExpiresAt: m.nowFunc().Add(m.ttl).UTC(),
+	}
+}
+
+// Encode encrypts a session into a cookie value
+func (m *SessionManager) Encode(session *Session) (string, error) {
+	plaintext, err := json.Marshal(session)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The cookie name is authenticated so the value cannot be replayed in another cookie
+	sealed := m.aead.Seal(nonce, nonce, plaintext, []byte(SessionCookieName))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decode decrypts a cookie value, rejecting tampered and expired sessions
+func (m *SessionManager) Decode(value string) (*Session, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(sealed) < m.aead.NonceSize() {
+		return nil, ErrInvalidCredentials
+	}
+
+	nonce, ciphertext := sealed[:m.aead.NonceSize()], sealed[m.aead.NonceSize():]
+	plaintext, err := m.aead.Open(nil, nonce, ciphertext, []byte(SessionCookieName))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	var session Session
+	if err := json.Unmarshal(plaintext, &session); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !m.nowFunc().Before(session.ExpiresAt) {
+		return nil, ErrInvalidCredentials
+	}
+	return &session, nil
+}
+
+// FromRequest returns the session carried by a request. It returns
+// ErrNoCredentials when there is no session cookie.
+func (m *SessionManager) FromRequest(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(SessionCookieName)
+	if errors.Is(err, http.ErrNoCookie) || (err == nil && cookie.Value == "") {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return m.Decode(cookie.Value)
+}
+
+// SetCookie writes a session cookie
+func (m *SessionManager) SetCookie(w http.ResponseWriter, session *Session) error {
+	value, err := m.Encode(session)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		MaxAge:   int(session.ExpiresAt.Sub(m.nowFunc()).Seconds()),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// ClearCookie removes the session cookie
+func (m *SessionManager) ClearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Authenticate implements Authenticator using the session cookie
+func (m *SessionManager) Authenticate(r *http.Request) (*Principal, error) {
+	session, err := m.FromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		ID:     "github:" + session.Login,
+		Name:   session.Login,
+		Method: "github_oauth",
+		Scopes: append([]string(nil), m.scopes...),
+	}, nil
+}
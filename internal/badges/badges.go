@@ -0,0 +1,270 @@
+package badges
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// Theme holds the colors used to render badges and cards
+type Theme struct {
+	Name        string
+	LabelColor  string
+	ValueColor  string
+	TextColor   string
+	Background  string
+	BorderColor string
+	TitleColor  string
+	MutedColor  string
+}
+
+// Themes lists the supported themes by name
+var Themes = map[string]Theme{
+	"light": {
+		Name:        "light",
+		LabelColor:  "#555",
+		ValueColor:  "#007ec6",
+		TextColor:   "#fff",
+		Background:  "#fffefe",
+		BorderColor: "#e4e2e2",
+		TitleColor:  "#2f80ed",
+		MutedColor:  "#434d58",
+	},
+	"dark": {
+		Name:        "dark",
+		LabelColor:  "#30363d",
+		ValueColor:  "#238636",
+		TextColor:   "#f0f6fc",
+		Background:  "#0d1117",
+		BorderColor: "#30363d",
+		TitleColor:  "#58a6ff",
+		MutedColor:  "#8b949e",
+	},
+	"flat": {
+		Name:        "flat",
+		LabelColor:  "#444d56",
+		ValueColor:  "#4c1",
+		TextColor:   "#fff",
+		Background:  "#ffffff",
+		BorderColor: "#d1d5da",
+		TitleColor:  "#24292e",
+		MutedColor:  "#586069",
+	},
+}
+
+// DefaultTheme is used when no theme or an unknown theme is requested
+const DefaultTheme = "light"
+
+// fallbackColor is the value color used for fallback images
+const fallbackColor = "#9f9f9f"
+
+// ThemeByName returns the named theme, falling back to the default theme
+func ThemeByName(name string) Theme {
+	if theme, ok := Themes[strings.ToLower(name)]; ok {
+		return theme
+	}
+	return Themes[DefaultTheme]
+}
+
+// Metric describes a repository value that can be rendered as a badge
+type Metric struct {
+	Label string
+	Value func(repo *models.Repository) string
+}
+
+// Metrics lists the supported badge metrics by name
+var Metrics = map[string]Metric{
+	"stars": {
+		Label: "stars",
+		Value: func(repo *models.Repository) string { return FormatCount(repo.StargazersCount) },
+	},
+	"forks": {
+		Label: "forks",
+		Value: func(repo *models.Repository) string { return FormatCount(repo.ForksCount) },
+	},
+	"watchers": {
+		Label: "watchers",
+		Value: func(repo *models.Repository) string { return FormatCount(repo.WatchersCount) },
+	},
+	"issues": {
+		Label: "open issues",
+		Value: func(repo *models.Repository) string { return FormatCount(repo.OpenIssuesCount) },
+	},
+	"language": {
+		Label: "language",
+		Value: func(repo *models.Repository) string {
+			if repo.Language == "" {
+				return "none"
+			}
+			return repo.Language
+		},
+	},
+}
+
+// FormatCount formats a count the way shields-style badges do, e.g. 1234 -> 1.2k
+func FormatCount(n int) string {
+	switch {
+	case n >= 1000000:
+		return trimDecimal(float64(n)/1000000) + "M"
+	case n >= 1000:
+		return trimDecimal(float64(n)/1000) + "k"
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// trimDecimal formats a value with one decimal place, dropping a trailing ".0"
+func trimDecimal(v float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0")
+}
+
+// textWidth estimates the rendered width of text in an 11px sans-serif font
+func textWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
+
+// RenderBadge renders a two-part label/value badge
+func RenderBadge(label, value string, theme Theme) []byte {
+	return renderBadge(label, value, theme.LabelColor, theme.ValueColor, theme.TextColor)
+}
+
+// RenderFallbackBadge renders the badge served when the upstream call fails
+func RenderFallbackBadge(label string, theme Theme) []byte {
+	return renderBadge(label, "unavailable", theme.LabelColor, fallbackColor, theme.TextColor)
+}
+
+func renderBadge(label, value, labelColor, valueColor, textColor string) []byte {
+	labelWidth := textWidth(label)
+	valueWidth := textWidth(value)
+	width := labelWidth + valueWidth
+	label = html.EscapeString(label)
+	value = html.EscapeString(value)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, value)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, value)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	b.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="%s"/>`, labelWidth, labelColor)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, valueWidth, valueColor)
+	b.WriteString(`</g>`)
+	fmt.Fprintf(&b, `<g fill="%s" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`, textColor)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+valueWidth/2, value)
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
+// LanguageShare is a language and its share of the user's repositories
+type LanguageShare struct {
+	Name    string
+	Count   int
+	Percent float64
+}
+
+// TopLanguages returns the most used primary languages across the repositories.
+// Forks are skipped since they don't reflect the user's own work.
+func TopLanguages(repos []models.Repository, limit int) []LanguageShare {
+	counts := make(map[string]int)
+	total := 0
+	for _, repo := range repos {
+		if repo.Fork || repo.Language == "" {
+			continue
+		}
+		counts[repo.Language]++
+		total++
+	}
+
+	shares := make([]LanguageShare, 0, len(counts))
+	for name, count := range counts {
+		shares = append(shares, LanguageShare{
+			Name:    name,
+			Count:   count,
+			Percent: float64(count) * 100 / float64(total),
+		})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Count != shares[j].Count {
+			return shares[i].Count > shares[j].Count
+		}
+		return shares[i].Name < shares[j].Name
+	})
+
+	if limit > 0 && len(shares) > limit {
+		shares = shares[:limit]
+	}
+	return shares
+}
+
+// languagePalette colors the language bars on the profile card
+var languagePalette = []string{"#3572A5", "#f1e05a", "#00ADD8", "#e34c26", "#b07219", "#178600"}
+
+// RenderProfileCard renders a stats card with the user's totals and top languages
+func RenderProfileCard(user models.UserResponse, repos []models.Repository, theme Theme) []byte {
+	stars := 0
+	forks := 0
+	for _, repo := range repos {
+		if repo.Fork {
+			continue
+		}
+		stars += repo.StargazersCount
+		forks += repo.ForksCount
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Login
+	}
+
+	languages := TopLanguages(repos, 5)
+	height := 150 + len(languages)*22
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="%d" role="img" aria-label="%s's GitHub stats">`, height, html.EscapeString(name))
+	fmt.Fprintf(&b, `<rect x="0.5" y="0.5" width="399" height="%d" rx="4.5" fill="%s" stroke="%s"/>`, height-1, theme.Background, theme.BorderColor)
+	b.WriteString(`<g font-family="Segoe UI,Ubuntu,sans-serif">`)
+	fmt.Fprintf(&b, `<text x="25" y="35" font-size="18" font-weight="600" fill="%s">%s's GitHub Stats</text>`, theme.TitleColor, html.EscapeString(name))
+
+	stats := []struct {
+		label string
+		value string
+	}{
+		{"Total Stars", FormatCount(stars)},
+		{"Total Forks", FormatCount(forks)},
+		{"Public Repos", FormatCount(user.PublicRepos)},
+		{"Followers", FormatCount(user.Followers)},
+	}
+	for i, stat := range stats {
+		x := 25 + (i%2)*185
+		y := 65 + (i/2)*22
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="14" fill="%s">%s: <tspan font-weight="700">%s</tspan></text>`, x, y, theme.MutedColor, stat.label, stat.value)
+	}
+
+	fmt.Fprintf(&b, `<text x="25" y="125" font-size="15" font-weight="600" fill="%s">Top Languages</text>`, theme.TitleColor)
+	for i, lang := range languages {
+		y := 145 + i*22
+		barWidth := int(lang.Percent * 2)
+		color := languagePalette[i%len(languagePalette)]
+		fmt.Fprintf(&b, `<text x="25" y="%d" font-size="13" fill="%s">%s</text>`, y+4, theme.MutedColor, html.EscapeString(lang.Name))
+		fmt.Fprintf(&b, `<rect x="130" y="%d" width="200" height="8" rx="4" fill="%s"/>`, y-4, theme.BorderColor)
+		fmt.Fprintf(&b, `<rect x="130" y="%d" width="%d" height="8" rx="4" fill="%s"/>`, y-4, barWidth, color)
+		fmt.Fprintf(&b, `<text x="340" y="%d" font-size="12" fill="%s">%.1f%%</text>`, y+4, theme.MutedColor, lang.Percent)
+	}
+
+	b.WriteString(`</g></svg>`)
+	return []byte(b.String())
+}
+
+// RenderFallbackCard renders the card served when the upstream call fails
+func RenderFallbackCard(theme Theme) []byte {
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="80" role="img" aria-label="GitHub stats unavailable">`)
+	fmt.Fprintf(&b, `<rect x="0.5" y="0.5" width="399" height="79" rx="4.5" fill="%s" stroke="%s"/>`, theme.Background, theme.BorderColor)
+	fmt.Fprintf(&b, `<text x="200" y="45" text-anchor="middle" font-family="Segoe UI,Ubuntu,sans-serif" font-size="14" fill="%s">GitHub stats are temporarily unavailable</text>`, theme.MutedColor)
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
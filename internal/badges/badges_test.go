@@ -0,0 +1,75 @@
+package badges
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// svgDocument is used to check that rendered output is well-formed XML
+type svgDocument struct {
+	XMLName xml.Name `xml:"svg"`
+}
+
+// TestFormatCount tests the shields-style count formatting
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1k"},
+		{1234, "1.2k"},
+		{1500000, "1.5M"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, FormatCount(tc.input))
+	}
+}
+
+// TestTopLanguages tests language aggregation across repositories
+func TestTopLanguages(t *testing.T) {
+	repos := []models.Repository{
+		{Language: "Go"},
+		{Language: "Go"},
+		{Language: "Python"},
+		{Language: "Rust", Fork: true},
+		{Language: ""},
+	}
+
+	languages := TopLanguages(repos, 5)
+
+	assert.Len(t, languages, 2)
+	assert.Equal(t, "Go", languages[0].Name)
+	assert.InDelta(t, 66.6, languages[0].Percent, 0.1)
+	assert.Equal(t, "Python", languages[1].Name)
+}
+
+// TestRenderBadge tests that badges are well-formed and escape their text
+func TestRenderBadge(t *testing.T) {
+	svg := RenderBadge("stars", "<1k>", ThemeByName("dark"))
+
+	assert.NoError(t, xml.Unmarshal(svg, new(svgDocument)))
+	assert.Contains(t, string(svg), "&lt;1k&gt;")
+	assert.Contains(t, string(svg), Themes["dark"].ValueColor)
+}
+
+// TestRenderProfileCard tests that the profile card is well-formed
+func TestRenderProfileCard(t *testing.T) {
+	user := models.UserResponse{Login: "test-user", PublicRepos: 2, Followers: 1200}
+	repos := []models.Repository{
+		{Language: "Go", StargazersCount: 10},
+		{Language: "Go & Friends", StargazersCount: 5},
+	}
+
+	svg := RenderProfileCard(user, repos, ThemeByName("unknown"))
+
+	assert.NoError(t, xml.Unmarshal(svg, new(svgDocument)))
+	assert.Contains(t, string(svg), "test-user's GitHub Stats")
+	assert.Contains(t, string(svg), "1.2k")
+	assert.Contains(t, string(svg), "Go &amp; Friends")
+}
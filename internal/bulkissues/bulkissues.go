@@ -0,0 +1,216 @@
+package bulkissues
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// JobType identifies bulk issue jobs
+	JobType = "bulk_issues"
+	// maxTitleLength is the longest issue title GitHub accepts
+	maxTitleLength = 256
+	// maxRateLimitRetries bounds how often a row is retried after GitHub limits the rate
+	maxRateLimitRetries = 3
+)
+
+// Payload is the work of a bulk issue job
+type Payload struct {
+	Repo   string                `json:"repo"`
+	Issues []models.IssueRequest `json:"issues"`
+}
+
+// Parse reads issues from a JSON array or from CSV with a header row naming
+// the title, body, labels and assignees columns. Labels and assignees are
+// comma separated within their cells.
+func Parse(contentType string, r io.Reader) ([]models.IssueRequest, error) {
+	switch contentType {
+	case "application/json":
+		var issues []models.IssueRequest
+		if err := json.NewDecoder(r).Decode(&issues); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return issues, nil
+	case "text/csv":
+		return parseCSV(r)
+	}
+	return nil, fmt.Errorf("unsupported content type %q: use application/json or text/csv", contentType)
+}
+
+// parseCSV reads issues from CSV
+func parseCSV(r io.Reader) ([]models.IssueRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: missing header row: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "title", "body", "labels", "assignees":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("invalid CSV: unknown column %q", name)
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("invalid CSV: a title column is required")
+	}
+
+	cell := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var issues []models.IssueRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return issues, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		issues = append(issues, models.IssueRequest{
+			Title:     cell(record, "title"),
+			Body:      cell(record, "body"),
+			Labels:    splitCell(cell(record, "labels")),
+			Assignees: splitCell(cell(record, "assignees")),
+		})
+	}
+}
+
+// Validate checks every row before anything is imported, returning the
+// problems found by row, counting from 1
+func Validate(issues []models.IssueRequest, maxRows int) []models.BulkRowError {
+	if len(issues) == 0 {
+		return []models.BulkRowError{{Row: 0, Error: "no issues to create"}}
+	}
+	if len(issues) > maxRows {
+		return []models.BulkRowError{{Row: maxRows + 1, Error: fmt.Sprintf("at most %d issues can be created at once", maxRows)}}
+	}
+
+	var problems []models.BulkRowError
+	for i, issue := range issues {
+		title := strings.TrimSpace(issue.Title)
+		switch {
+		case title == "":
+			problems = append(problems, models.BulkRowError{Row: i + 1, Error: "title is required"})
+		case len([]rune(title)) > maxTitleLength:
+			problems = append(problems, models.BulkRowError{Row: i + 1, Error: fmt.Sprintf("title is longer than %d characters", maxTitleLength)})
+		}
+	}
+	return problems
+}
+
+// NewHandler returns the job handler that creates the issues of a bulk job,
+// one every interval so as not to trip GitHub's limits on content creation.
+// Rows GitHub refuses with a rate limit are retried after backoff, doubling
+// each time. Rows already recorded are skipped, so a job can be run again.
+func NewHandler(service services.GitHubServiceInterface, interval, backoff time.Duration) jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job, run *jobs.Run) error {
+		var payload Payload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return jobs.Permanent(fmt.Errorf("invalid bulk issue payload: %w", err))
+		}
+
+		for i := len(job.Results); i < len(payload.Issues); i++ {
+			if i > 0 {
+				if err := sleep(ctx, interval); err != nil {
+					return err
+				}
+			}
+
+			issueRequest := payload.Issues[i]
+			issue, err := createIssue(ctx, service, payload.Repo, &issueRequest, backoff)
+			// An issue that was created is recorded even when the job is stopping
+			if err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			result := jobs.Result{Item: i + 1, Label: issueRequest.Title, Status: jobs.StatusSucceeded}
+			if err != nil {
+				result.Status = jobs.StatusFailed
+				result.Error = err.Error()
+				logrus.WithError(err).WithFields(logrus.Fields{
+					"job_id": job.ID,
+					"repo":   payload.Repo,
+					"row":    i + 1,
+				}).Warn("Failed to create issue from bulk import")
+			} else {
+				result.Output, _ = json.Marshal(map[string]interface{}{
+					"number":   issue.Number,
+					"html_url": issue.HTMLURL,
+				})
+			}
+			run.Record(result)
+		}
+		return nil
+	}
+}
+
+// createIssue creates an issue, waiting and retrying when GitHub limits the rate
+func createIssue(ctx context.Context, service services.GitHubServiceInterface, repoName string, issue *models.IssueRequest, backoff time.Duration) (*models.IssueResponse, error) {
+	for attempt := 0; ; attempt++ {
+		created, err := service.CreateIssue(repoName, issue)
+		if err == nil || !rateLimited(err) || attempt == maxRateLimitRetries {
+			return created, err
+		}
+		if err := sleep(ctx, backoff<<attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rateLimited reports whether GitHub refused a request because of a rate limit
+func rateLimited(err error) bool {
+	var apiErr *services.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests ||
+		(apiErr.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(apiErr.Body), "rate limit"))
+}
+
+// sleep waits for d unless ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// splitCell splits a comma separated cell, dropping empty items
+func splitCell(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
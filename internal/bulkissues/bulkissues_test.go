@@ -0,0 +1,139 @@
+package bulkissues
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/jobs"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService creates issues, failing according to a function of the title
+type fakeService struct {
+	services.GitHubServiceInterface
+	fail  func(title string, attempt int) error
+	calls map[string]int
+}
+
+func (f *fakeService) CreateIssue(repoName string, issue *models.IssueRequest) (*models.IssueResponse, error) {
+	f.calls[issue.Title]++
+	if err := f.fail(issue.Title, f.calls[issue.Title]); err != nil {
+		return nil, err
+	}
+	return &models.IssueResponse{Number: len(f.calls), Title: issue.Title}, nil
+}
+
+// TestParseCSV tests reading issues from CSV
+func TestParseCSV(t *testing.T) {
+	input := "Title,Body,Labels,Assignees\n" +
+		"Fix login,\"Steps:\n1. Log in\",\"bug, auth\",octocat\n" +
+		"Update docs,,,\n"
+
+	issues, err := Parse("text/csv", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "Fix login", issues[0].Title)
+	assert.Equal(t, "Steps:\n1. Log in", issues[0].Body)
+	assert.Equal(t, []string{"bug", "auth"}, issues[0].Labels)
+	assert.Equal(t, []string{"octocat"}, issues[0].Assignees)
+	assert.Equal(t, "Update docs", issues[1].Title)
+	assert.Nil(t, issues[1].Labels)
+
+	_, err = Parse("text/csv", strings.NewReader("title,priority\nFix login,high\n"))
+	assert.Error(t, err)
+	_, err = Parse("text/csv", strings.NewReader("body\nNo title\n"))
+	assert.Error(t, err)
+}
+
+// TestParseJSON tests reading issues from a JSON array
+func TestParseJSON(t *testing.T) {
+	issues, err := Parse("application/json", strings.NewReader(`[{"title":"Fix login","labels":["bug"]}]`))
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, []string{"bug"}, issues[0].Labels)
+
+	_, err = Parse("application/json", strings.NewReader(`{"title":"Fix login"}`))
+	assert.Error(t, err)
+}
+
+// TestValidate tests that every invalid row is reported
+func TestValidate(t *testing.T) {
+	issues := []models.IssueRequest{
+		{Title: "Fix login"},
+		{Title: "  "},
+		{Title: strings.Repeat("a", 257)},
+	}
+
+	problems := Validate(issues, 10)
+	require.Len(t, problems, 2)
+	assert.Equal(t, 2, problems[0].Row)
+	assert.Equal(t, 3, problems[1].Row)
+
+	assert.Len(t, Validate(issues, 2), 1)
+	assert.Len(t, Validate(nil, 10), 1)
+	assert.Empty(t, Validate(issues[:1], 10))
+}
+
+// TestHandler tests that rows are created in order, retrying rate limits
+func TestHandler(t *testing.T) {
+	service := &fakeService{
+		calls: make(map[string]int),
+		fail: func(title string, attempt int) error {
+			switch {
+			case title == "Limited" && attempt == 1:
+				return &services.APIError{StatusCode: 403, Body: `{"message":"You have exceeded a secondary rate limit"}`}
+			case title == "Invalid":
+				return &services.APIError{StatusCode: 422, Body: `{"message":"Validation Failed"}`}
+			}
+			return nil
+		},
+	}
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour, MaxAttempts: 1})
+	require.NoError(t, err)
+	manager.Register(JobType, NewHandler(service, 0, time.Millisecond))
+	manager.Start()
+	defer manager.Stop()
+
+	payload := Payload{Repo: "test-repo", Issues: []models.IssueRequest{{Title: "First"}, {Title: "Limited"}, {Title: "Invalid"}}}
+	job, err := manager.Enqueue(JobType, payload, 3)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		job, err = manager.Get(job.ID)
+		return err == nil && job.Status == jobs.StatusSucceeded
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, job.Completed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, 2, service.calls["Limited"])
+	assert.Equal(t, 1, service.calls["Invalid"])
+	assert.Equal(t, jobs.StatusFailed, job.Results[2].Status)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(job.Results[0].Output, &output))
+	assert.Equal(t, float64(1), output["number"])
+
+}
+
+// TestHandlerInvalidPayload tests that a payload that cannot be decoded fails the job without retries
+func TestHandlerInvalidPayload(t *testing.T) {
+	manager, err := jobs.NewManager(config.JobsConfig{Workers: 1, Retention: time.Hour, MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	require.NoError(t, err)
+	manager.Register(JobType, NewHandler(&fakeService{calls: make(map[string]int)}, 0, time.Millisecond))
+	manager.Start()
+	defer manager.Stop()
+
+	job, err := manager.Enqueue(JobType, []string{"not", "a", "payload"}, 0)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		job, err = manager.Get(job.ID)
+		return err == nil && job.Status == jobs.StatusFailed
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, job.Attempts)
+}
@@ -0,0 +1,157 @@
+package duplicates
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+const (
+	// Threshold is the score from which an open issue is reported as a possible duplicate
+	Threshold = 0.5
+	// maxCandidates bounds the candidates reported for one issue
+	maxCandidates = 5
+	// maxSearchTerms bounds the search terms; GitHub allows five operators per query
+	maxSearchTerms = 6
+	// titleWeight is the share of the score taken from the titles; bodies are
+	// longer and noisier, so they count for less
+	titleWeight = 0.7
+)
+
+// stopWords are too common in bug reports to tell issues apart
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "can": true, "was": true, "when": true, "with": true,
+	"this": true, "that": true, "from": true, "have": true, "has": true, "does": true,
+	"into": true, "after": true, "there": true, "what": true, "which": true, "while": true,
+	"issue": true, "bug": true, "error": true, "problem": true, "please": true,
+}
+
+// Keywords returns the distinct significant words of text in order of appearance
+func Keywords(text string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, word := range words(text) {
+		if !seen[word] {
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// SearchTerms builds a GitHub search query matching issues that share any
+// keyword with the new issue's title, or its body when the title has none
+func SearchTerms(issue *models.IssueRequest) string {
+	keywords := Keywords(issue.Title)
+	if len(keywords) == 0 {
+		keywords = Keywords(issue.Body)
+	}
+	if len(keywords) > maxSearchTerms {
+		keywords = keywords[:maxSearchTerms]
+	}
+	return strings.Join(keywords, " OR ")
+}
+
+// Similarity returns the cosine similarity of the word frequencies of a and b,
+// from 0 for no shared words to 1 for the same words
+func Similarity(a, b string) float64 {
+	fa, fb := frequencies(a), frequencies(b)
+	if len(fa) == 0 || len(fb) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for word, count := range fa {
+		dot += count * fb[word]
+		normA += count * count
+	}
+	for _, count := range fb {
+		normB += count * count
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Score rates how likely existing is a duplicate of issue
+func Score(issue *models.IssueRequest, existing *models.IssueResponse) float64 {
+	title := Similarity(issue.Title, existing.Title)
+	if strings.TrimSpace(issue.Body) == "" || strings.TrimSpace(existing.Body) == "" {
+		return title
+	}
+	return titleWeight*title + (1-titleWeight)*Similarity(issue.Body, existing.Body)
+}
+
+// Rank returns the existing issues scoring at least Threshold, best first
+func Rank(issue *models.IssueRequest, existing []models.IssueResponse) []models.DuplicateCandidate {
+	var candidates []models.DuplicateCandidate
+	for i := range existing {
+		score := Score(issue, &existing[i])
+		if score < Threshold {
+			continue
+		}
+		candidates = append(candidates, models.DuplicateCandidate{
+			Number:  existing[i].Number,
+			Title:   existing[i].Title,
+			HTMLURL: existing[i].HTMLURL,
+			Score:   math.Round(score*100) / 100,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	return candidates
+}
+
+// Comment formats the comment posted on an issue created despite its candidates
+func Comment(candidates []models.DuplicateCandidate) string {
+	var b strings.Builder
+	b.WriteString("This issue may be a duplicate of:\n\n")
+	for _, candidate := range candidates {
+		fmt.Fprintf(&b, "- #%d %s (similarity %.2f)\n", candidate.Number, candidate.Title, candidate.Score)
+	}
+	return b.String()
+}
+
+// frequencies counts the significant words of text
+func frequencies(text string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, word := range words(text) {
+		counts[stem(word)]++
+	}
+	return counts
+}
+
+// words splits text into lowercase significant words
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var result []string
+	for _, word := range fields {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		result = append(result, word)
+	}
+	return result
+}
+
+// stem trims a common English suffix from longer words so that "crashes"
+// and "crashing" match "crash"
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "es", "ed", "s"} {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
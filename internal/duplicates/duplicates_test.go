@@ -0,0 +1,74 @@
+package duplicates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearchTerms tests that searches use the significant words of the title
+func TestSearchTerms(t *testing.T) {
+	// Test cases
+	tests := []struct {
+		name     string
+		issue    *models.IssueRequest
+		expected string
+	}{
+		{
+			name:     "Title Keywords",
+			issue:    &models.IssueRequest{Title: "The app crashes when the app starts", Body: "Details"},
+			expected: "app OR crashes OR starts",
+		},
+		{
+			name:     "Body Fallback",
+			issue:    &models.IssueRequest{Title: "Bug", Body: "Login fails"},
+			expected: "login OR fails",
+		},
+		{
+			name:     "Bounded",
+			issue:    &models.IssueRequest{Title: "one two three four five six seven eight"},
+			expected: "one OR two OR three OR four OR five OR six",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SearchTerms(tc.issue))
+		})
+	}
+}
+
+// TestSimilarity tests scoring of shared and stemmed words
+func TestSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, Similarity("App crashes on login", "app crashing at LOGIN"), 0.001)
+	assert.InDelta(t, 0, Similarity("App crashes on login", "Dark mode colours"), 0.001)
+	assert.InDelta(t, 0, Similarity("", "Dark mode colours"), 0.001)
+
+	partial := Similarity("App crashes on login", "App crashes on logout")
+	assert.Greater(t, partial, 0.5)
+	assert.Less(t, partial, 1.0)
+}
+
+// TestRank tests that only likely duplicates are returned, best first
+func TestRank(t *testing.T) {
+	issue := &models.IssueRequest{Title: "App crashes on login", Body: "The app crashes after entering a password"}
+	existing := []models.IssueResponse{
+		{Number: 1, Title: "Dark mode colours", Body: "Colours are wrong"},
+		{Number: 2, Title: "App crashes on logout", Body: "Closing the app crashes it"},
+		{Number: 3, Title: "Login crashes the app", Body: "The app crashes after entering a password"},
+	}
+
+	candidates := Rank(issue, existing)
+	var numbers []int
+	for _, candidate := range candidates {
+		numbers = append(numbers, candidate.Number)
+	}
+	assert.Equal(t, []int{3, 2}, numbers)
+	assert.Equal(t, 1.0, candidates[0].Score)
+
+	comment := Comment(candidates)
+	assert.True(t, strings.HasPrefix(comment, "This issue may be a duplicate of:"))
+	assert.Contains(t, comment, "- #3 Login crashes the app (similarity 1.00)")
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a repository activity change delivered to stream subscribers
+type Message struct {
+	ID         uint64          `json:"id"`
+	Type       string          `json:"type"`
+	Action     string          `json:"action,omitempty"`
+	Repository string          `json:"repository"`
+	Source     string          `json:"source"`
+	ReceivedAt time.Time       `json:"received_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Filter selects the messages a subscriber receives
+type Filter struct {
+	// Repository is matched case-insensitively against the full name; empty matches all
+	Repository string
+	// Types lists the accepted event types; empty accepts all
+	Types []string
+	// Actions lists the accepted actions; empty accepts all
+	Actions []string
+}
+
+// Matches reports whether a message passes the filter
+func (f Filter) Matches(msg *Message) bool {
+	if f.Repository != "" && !strings.EqualFold(f.Repository, msg.Repository) {
+		return false
+	}
+	return contains(f.Types, msg.Type) && contains(f.Actions, msg.Action)
+}
+
+func contains(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives the messages matching its filter
+type Subscription struct {
+	// C delivers matching messages; it is closed when the subscription is closed
+	C <-chan Message
+
+	ch      chan Message
+	filter  Filter
+	broker  *Broker
+	dropped uint64
+}
+
+// Dropped returns the number of messages dropped because the subscriber fell behind
+func (s *Subscription) Dropped() uint64 {
+	s.broker.mu.RLock()
+	defer s.broker.mu.RUnlock()
+	return s.dropped
+}
+
+// Close unsubscribes and closes C
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if _, ok := s.broker.subscribers[s]; ok {
+		delete(s.broker.subscribers, s)
+		close(s.ch)
+	}
+}
+
+// Broker publishes messages to subscribers and keeps a bounded history so
+// that reconnecting clients can resume from the last message they saw
+type Broker struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	history     []Message
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker creates a Broker keeping historySize messages for resumption and
+// buffering up to bufferSize messages per subscriber
+func NewBroker(historySize, bufferSize int) *Broker {
+	return &Broker{
+		nextID:      1,
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the message an ID and delivers it to matching subscribers.
+// Subscribers whose buffer is full miss the message rather than block the publisher.
+func (b *Broker) Publish(msg Message) Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msg.ID = b.nextID
+	b.nextID++
+
+	b.history = append(b.history, msg)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(&msg) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			sub.dropped++
+		}
+	}
+
+	return msg
+}
+
+// Subscribe registers a subscriber and returns the retained messages after
+// lastEventID that match the filter. Pass 0 to skip the backlog.
+func (b *Broker) Subscribe(filter Filter, lastEventID uint64) (*Subscription, []Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Message, b.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, broker: b}
+	b.subscribers[sub] = struct{}{}
+
+	var backlog []Message
+	if lastEventID > 0 {
+		for _, msg := range b.history {
+			if msg.ID > lastEventID && filter.Matches(&msg) {
+				backlog = append(backlog, msg)
+			}
+		}
+	}
+
+	return sub, backlog
+}
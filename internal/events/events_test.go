@@ -0,0 +1,146 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBrokerResume tests that subscribers resume from the retained history
+func TestBrokerResume(t *testing.T) {
+	broker := NewBroker(3, 8)
+	for _, eventType := range []string{"push", "issues", "push", "release"} {
+		broker.Publish(Message{Type: eventType, Repository: "test-user/test-repo"})
+	}
+
+	// The first message has fallen out of the history
+	sub, backlog := broker.Subscribe(Filter{Repository: "Test-User/Test-Repo", Types: []string{"push", "release"}}, 1)
+	defer sub.Close()
+
+	require.Len(t, backlog, 2)
+	assert.Equal(t, uint64(3), backlog[0].ID)
+	assert.Equal(t, uint64(4), backlog[1].ID)
+
+	// Without a last event ID there is no backlog
+	other, backlog := broker.Subscribe(Filter{}, 0)
+	defer other.Close()
+	assert.Empty(t, backlog)
+}
+
+// TestBrokerFilterAndDrop tests live delivery filtering and that slow subscribers drop messages
+func TestBrokerFilterAndDrop(t *testing.T) {
+	broker := NewBroker(10, 1)
+	sub, _ := broker.Subscribe(Filter{Repository: "test-user/test-repo", Actions: []string{"opened"}}, 0)
+
+	broker.Publish(Message{Type: "issues", Action: "closed", Repository: "test-user/test-repo"})
+	broker.Publish(Message{Type: "issues", Action: "opened", Repository: "test-user/other-repo"})
+	broker.Publish(Message{Type: "issues", Action: "opened", Repository: "test-user/test-repo"})
+	broker.Publish(Message{Type: "pull_request", Action: "opened", Repository: "test-user/test-repo"})
+
+	msg := <-sub.C
+	assert.Equal(t, uint64(3), msg.ID)
+	assert.Equal(t, uint64(1), sub.Dropped())
+
+	sub.Close()
+	_, open := <-sub.C
+	assert.False(t, open)
+	sub.Close()
+}
+
+// TestHandleWebhook tests that only stream event types are published
+func TestHandleWebhook(t *testing.T) {
+	broker := NewBroker(10, 8)
+	sub, _ := broker.Subscribe(Filter{}, 0)
+	defer sub.Close()
+
+	assert.NoError(t, broker.HandleWebhook(&webhooks.Event{Type: webhooks.EventWorkflowRun, Repository: "test-user/test-repo"}))
+	assert.NoError(t, broker.HandleWebhook(&webhooks.Event{
+		Type:       webhooks.EventIssues,
+		Action:     "opened",
+		Repository: "test-user/test-repo",
+		Raw:        json.RawMessage(`{"action":"opened"}`),
+	}))
+
+	msg := <-sub.C
+	assert.Equal(t, webhooks.EventIssues, msg.Type)
+	assert.Equal(t, SourceWebhook, msg.Source)
+	assert.JSONEq(t, `{"action":"opened"}`, string(msg.Payload))
+	assert.Empty(t, sub.C)
+}
+
+// fakeSource serves scripted event pages
+type fakeSource struct {
+	mu    sync.Mutex
+	pages []*models.EventPage
+	etags []string
+}
+
+func (f *fakeSource) ListRepositoryEvents(repoName string, etag string) (*models.EventPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.etags = append(f.etags, etag)
+	if len(f.pages) == 0 {
+		return &models.EventPage{ETag: etag, NotModified: true}, nil
+	}
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+// TestPoller tests that the poller skips the initial backlog and publishes new events in order
+func TestPoller(t *testing.T) {
+	source := &fakeSource{pages: []*models.EventPage{
+		{ETag: `"a"`, Events: []models.Event{
+			{ID: "10", Type: "PushEvent", Repo: models.EventRepo{Name: "test-user/test-repo"}},
+		}},
+		{ETag: `"b"`, Events: []models.Event{
+			{ID: "13", Type: "IssuesEvent", Repo: models.EventRepo{Name: "test-user/test-repo"}, Payload: json.RawMessage(`{"action":"closed"}`)},
+			{ID: "12", Type: "WatchEvent", Repo: models.EventRepo{Name: "test-user/test-repo"}},
+			{ID: "11", Type: "ReleaseEvent", Repo: models.EventRepo{Name: "test-user/test-repo"}, Payload: json.RawMessage(`{"action":"published"}`)},
+			{ID: "10", Type: "PushEvent", Repo: models.EventRepo{Name: "test-user/test-repo"}},
+		}},
+	}}
+
+	broker := NewBroker(10, 8)
+	sub, _ := broker.Subscribe(Filter{}, 0)
+	defer sub.Close()
+
+	poller := NewPoller(source, broker, 10*time.Millisecond)
+	release := poller.Watch("test-repo")
+	// A second watcher shares the same polling loop
+	releaseOther := poller.Watch("Test-Repo")
+
+	var received []Message
+	for len(received) < 2 {
+		select {
+		case msg := <-sub.C:
+			received = append(received, msg)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for polled events")
+		}
+	}
+
+	assert.Equal(t, webhooks.EventRelease, received[0].Type)
+	assert.Equal(t, "published", received[0].Action)
+	assert.Equal(t, webhooks.EventIssues, received[1].Type)
+	assert.Equal(t, SourcePoll, received[1].Source)
+
+	release()
+	release()
+	releaseOther()
+
+	poller.mu.Lock()
+	assert.Empty(t, poller.watches)
+	poller.mu.Unlock()
+
+	source.mu.Lock()
+	assert.Equal(t, []string{"", `"a"`}, source.etags[:2])
+	source.mu.Unlock()
+}
@@ -0,0 +1,190 @@
+package events
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/sirupsen/logrus"
+)
+
+// Message sources
+const (
+	SourceWebhook = "webhook"
+	SourcePoll    = "poll"
+)
+
+// StreamTypes are the event types published to streams
+var StreamTypes = []string{
+	webhooks.EventIssues,
+	webhooks.EventPullRequest,
+	webhooks.EventPush,
+	webhooks.EventRelease,
+}
+
+// apiEventTypes maps events API types to their webhook equivalents
+var apiEventTypes = map[string]string{
+	"IssuesEvent":      webhooks.EventIssues,
+	"PullRequestEvent": webhooks.EventPullRequest,
+	"PushEvent":        webhooks.EventPush,
+	"ReleaseEvent":     webhooks.EventRelease,
+}
+
+// HandleWebhook publishes stream events received by the webhook receiver.
+// It is registered on the webhook dispatcher.
+func (b *Broker) HandleWebhook(event *webhooks.Event) error {
+	if !contains(StreamTypes, event.Type) || event.Repository == "" {
+		return nil
+	}
+
+	b.Publish(Message{
+		Type:       event.Type,
+		Action:     event.Action,
+		Repository: event.Repository,
+		Source:     SourceWebhook,
+		ReceivedAt: event.ReceivedAt,
+		Payload:    event.Raw,
+	})
+	return nil
+}
+
+// EventSource reads a repository's recent events conditionally
+type EventSource interface {
+	ListRepositoryEvents(repoName string, etag string) (*models.EventPage, error)
+}
+
+// Poller polls the events API for repositories that have stream subscribers.
+// It is the fallback for deployments that cannot receive webhooks.
+type Poller struct {
+	source   EventSource
+	broker   *Broker
+	interval time.Duration
+
+	mu      sync.Mutex
+	watches map[string]*watch
+}
+
+type watch struct {
+	refs int
+	stop chan struct{}
+}
+
+// NewPoller creates a Poller that publishes to broker. GitHub's
+// X-Poll-Interval is honoured when it is longer than interval.
+func NewPoller(source EventSource, broker *Broker, interval time.Duration) *Poller {
+	return &Poller{
+		source:   source,
+		broker:   broker,
+		interval: interval,
+		watches:  make(map[string]*watch),
+	}
+}
+
+// Watch starts polling a repository if it is not already being polled. The
+// returned function releases the watch; polling stops with the last release.
+func (p *Poller) Watch(repoName string) func() {
+	key := strings.ToLower(repoName)
+
+	p.mu.Lock()
+	w, ok := p.watches[key]
+	if !ok {
+		w = &watch{stop: make(chan struct{})}
+		p.watches[key] = w
+		go p.run(repoName, w.stop)
+	}
+	w.refs++
+	p.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			w.refs--
+			if w.refs == 0 {
+				close(w.stop)
+				delete(p.watches, key)
+			}
+		})
+	}
+}
+
+// run polls a repository until stop is closed. The first read only records
+// the newest event so that subscribers are not replayed GitHub's backlog.
+func (p *Poller) run(repoName string, stop <-chan struct{}) {
+	var etag string
+	var lastID int64
+	primed := false
+	interval := p.interval
+
+	for {
+		page, err := p.source.ListRepositoryEvents(repoName, etag)
+		if err != nil {
+			logrus.WithError(err).WithField("repo", repoName).Warn("Failed to poll repository events")
+		} else {
+			etag = page.ETag
+			if polled := time.Duration(page.PollInterval) * time.Second; polled > p.interval {
+				interval = polled
+			} else {
+				interval = p.interval
+			}
+			if !page.NotModified {
+				lastID = p.publish(page.Events, lastID, primed)
+				primed = true
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// publish publishes events newer than lastID in chronological order and
+// returns the newest event ID seen
+func (p *Poller) publish(events []models.Event, lastID int64, primed bool) int64 {
+	newest := lastID
+	// The events API lists the newest event first
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		id, err := strconv.ParseInt(event.ID, 10, 64)
+		if err != nil || id <= lastID {
+			continue
+		}
+		if id > newest {
+			newest = id
+		}
+
+		eventType, ok := apiEventTypes[event.Type]
+		if !primed || !ok {
+			continue
+		}
+
+		var payload struct {
+			Action string `json:"action"`
+		}
+		_ = json.Unmarshal(event.Payload, &payload)
+
+		receivedAt, err := time.Parse(time.RFC3339, event.CreatedAt)
+		if err != nil {
+			receivedAt = time.Now().UTC()
+		}
+
+		p.broker.Publish(Message{
+			Type:       eventType,
+			Action:     payload.Action,
+			Repository: event.Repo.Name,
+			Source:     SourcePoll,
+			ReceivedAt: receivedAt,
+			Payload:    event.Payload,
+		})
+	}
+	return newest
+}
@@ -0,0 +1,481 @@
+package fanout
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/storage"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotFound is returned when a subscription or dead letter does not exist
+var ErrNotFound = errors.New("not found")
+
+// queueSize bounds the number of deliveries waiting for a worker
+const queueSize = 1024
+
+// flushInterval is how often the outcomes of delivery attempts are persisted;
+// they are batched so that each attempt does not rewrite every payload
+const flushInterval = time.Second
+
+// state is the persisted registry and delivery state
+type state struct {
+	Subscriptions map[string]*Subscription `json:"subscriptions"`
+	Pending       map[string]*Delivery     `json:"pending"`
+	DeadLetters   map[string]*Delivery     `json:"dead_letters"`
+}
+
+// Service forwards webhook events to subscribers. Deliveries are persisted
+// before they are attempted and only removed once acknowledged, so every
+// event is delivered at least once even across restarts. The outcomes of
+// attempts are persisted in batches; one lost in a crash is only attempted
+// again.
+type Service struct {
+	config config.FanoutConfig
+	client *http.Client
+	store  *storage.JSONFile
+
+	mu    sync.Mutex
+	state state
+	// dirty is set when the state has changes that are not yet persisted
+	dirty bool
+
+	queue    chan string
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewService creates a Service, loading any persisted state
+func NewService(cfg config.FanoutConfig) (*Service, error) {
+	s := &Service{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		state: state{
+			Subscriptions: make(map[string]*Subscription),
+			Pending:       make(map[string]*Delivery),
+			DeadLetters:   make(map[string]*Delivery),
+		},
+		queue: make(chan string, queueSize),
+		stop:  make(chan struct{}),
+	}
+
+	if cfg.StatePath != "" {
+		store, err := storage.NewJSONFile(cfg.StatePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Load(&s.state); err != nil {
+			return nil, err
+		}
+		s.store = store
+	}
+	s.pruneDeadLettersLocked(time.Now())
+
+	return s, nil
+}
+
+// Start launches the delivery workers and resumes deliveries left pending
+func (s *Service) Start() {
+	workers := s.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	s.wg.Add(1)
+	go s.flusher()
+
+	s.mu.Lock()
+	pending := make([]string, 0, len(s.state.Pending))
+	for id := range s.state.Pending {
+		pending = append(pending, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range pending {
+		s.enqueue(id)
+	}
+	if len(pending) > 0 {
+		logrus.WithField("count", len(pending)).Info("Resumed pending fan-out deliveries")
+	}
+}
+
+// Stop stops the workers and persists their outcomes; pending deliveries
+// stay persisted for the next start
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.wg.Wait()
+	s.flush()
+}
+
+// CreateSubscription registers a new subscription
+func (s *Service) CreateSubscription(sub Subscription) (*Subscription, error) {
+	sub.ID = newID()
+	sub.CreatedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Subscriptions[sub.ID] = &sub
+	if err := s.persist(); err != nil {
+		delete(s.state.Subscriptions, sub.ID)
+		return nil, err
+	}
+
+	created := sub.Redacted()
+	return &created, nil
+}
+
+// ListSubscriptions returns all subscriptions without their secrets, oldest first
+func (s *Service) ListSubscriptions() []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(s.state.Subscriptions))
+	for _, sub := range s.state.Subscriptions {
+		subs = append(subs, sub.Redacted())
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs
+}
+
+// GetSubscription returns a subscription without its secret
+func (s *Service) GetSubscription(id string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.state.Subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	redacted := sub.Redacted()
+	return &redacted, nil
+}
+
+// DeleteSubscription removes a subscription along with its pending deliveries
+func (s *Service) DeleteSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.state.Subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.state.Subscriptions, id)
+	for deliveryID, delivery := range s.state.Pending {
+		if delivery.SubscriptionID == id {
+			delete(s.state.Pending, deliveryID)
+		}
+	}
+	return s.persist()
+}
+
+// HandleEvent queues a delivery of the event for every matching subscription.
+// It is registered on the webhook dispatcher.
+func (s *Service) HandleEvent(event *webhooks.Event) error {
+	s.mu.Lock()
+	var queued []string
+	for _, sub := range s.state.Subscriptions {
+		if !sub.Matches(event.Type, event.Repository) {
+			continue
+		}
+		delivery := &Delivery{
+			ID:             newID(),
+			SubscriptionID: sub.ID,
+			Event:          event.Type,
+			GitHubDelivery: event.DeliveryID,
+			Repository:     event.Repository,
+			Payload:        event.Raw,
+			CreatedAt:      time.Now().UTC(),
+		}
+		s.state.Pending[delivery.ID] = delivery
+		queued = append(queued, delivery.ID)
+	}
+
+	if len(queued) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+
+	if err := s.persist(); err != nil {
+		for _, id := range queued {
+			delete(s.state.Pending, id)
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("failed to persist fan-out deliveries: %w", err)
+	}
+	s.mu.Unlock()
+
+	for _, id := range queued {
+		s.enqueue(id)
+	}
+	return nil
+}
+
+// ListDeadLetters returns deliveries that exhausted their retries, oldest first
+func (s *Service) ListDeadLetters() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries := make([]Delivery, 0, len(s.state.DeadLetters))
+	for _, delivery := range s.state.DeadLetters {
+		deliveries = append(deliveries, *delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries
+}
+
+// Replay moves a dead letter back to the pending queue with a fresh retry budget
+func (s *Service) Replay(id string) error {
+	s.mu.Lock()
+	delivery, ok := s.state.DeadLetters[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	if _, ok := s.state.Subscriptions[delivery.SubscriptionID]; !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("subscription %s no longer exists: %w", delivery.SubscriptionID, ErrNotFound)
+	}
+
+	delete(s.state.DeadLetters, id)
+	delivery.Attempts = 0
+	s.state.Pending[id] = delivery
+	if err := s.persist(); err != nil {
+		delete(s.state.Pending, id)
+		s.state.DeadLetters[id] = delivery
+		s.mu.Unlock()
+		return err
+	}
+	s.mu.Unlock()
+
+	s.enqueue(id)
+	return nil
+}
+
+// ReplayAll replays every dead letter whose subscription still exists
+func (s *Service) ReplayAll() (int, error) {
+	replayed := 0
+	for _, delivery := range s.ListDeadLetters() {
+		err := s.Replay(delivery.ID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// enqueue hands a pending delivery to the workers, waiting for capacity if the queue is full
+func (s *Service) enqueue(id string) {
+	select {
+	case <-s.stop:
+	case s.queue <- id:
+	default:
+		time.AfterFunc(time.Second, func() { s.enqueue(id) })
+	}
+}
+
+// worker attempts queued deliveries until the service stops
+func (s *Service) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case id := <-s.queue:
+			s.attempt(id)
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome
+func (s *Service) attempt(id string) {
+	s.mu.Lock()
+	delivery, ok := s.state.Pending[id]
+	var sub *Subscription
+	if ok {
+		sub = s.state.Subscriptions[delivery.SubscriptionID]
+	}
+	if !ok || sub == nil {
+		s.mu.Unlock()
+		return
+	}
+	snapshot := *delivery
+	subscription := *sub
+	s.mu.Unlock()
+
+	err := s.send(&subscription, &snapshot)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The subscription may have been deleted while the request was in flight
+	if s.state.Pending[id] != delivery {
+		return
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	entry := logrus.WithFields(logrus.Fields{
+		"delivery_id":     delivery.ID,
+		"subscription_id": delivery.SubscriptionID,
+		"event":           delivery.Event,
+		"attempt":         delivery.Attempts,
+	})
+
+	switch {
+	case err == nil:
+		delete(s.state.Pending, id)
+		entry.Debug("Fan-out delivery succeeded")
+	case delivery.Attempts >= s.config.MaxAttempts:
+		delivery.LastError = err.Error()
+		delete(s.state.Pending, id)
+		s.state.DeadLetters[id] = delivery
+		s.pruneDeadLettersLocked(now)
+		entry.WithError(err).Error("Fan-out delivery moved to dead-letter queue")
+	default:
+		delivery.LastError = err.Error()
+		backoff := s.backoff(delivery.Attempts)
+		entry.WithError(err).WithField("retry_in", backoff).Warn("Fan-out delivery failed")
+		time.AfterFunc(backoff, func() { s.enqueue(id) })
+	}
+	s.dirty = true
+}
+
+// flusher persists the outcomes of attempts and ages out dead letters until
+// the service stops
+func (s *Service) flusher() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			s.pruneDeadLettersLocked(now)
+			s.mu.Unlock()
+			s.flush()
+		}
+	}
+}
+
+// flush persists the state if it has unsaved changes
+func (s *Service) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return
+	}
+	if err := s.persist(); err != nil {
+		logrus.WithError(err).Error("Failed to persist fan-out state")
+	}
+}
+
+// pruneDeadLettersLocked drops dead letters older than the configured TTL,
+// then the oldest beyond the configured maximum; the caller must hold s.mu
+func (s *Service) pruneDeadLettersLocked(now time.Time) {
+	var deadLetters []*Delivery
+	for id, delivery := range s.state.DeadLetters {
+		if s.config.DeadLetterTTL > 0 && now.Sub(delivery.deadAt()) > s.config.DeadLetterTTL {
+			delete(s.state.DeadLetters, id)
+			s.dirty = true
+			continue
+		}
+		deadLetters = append(deadLetters, delivery)
+	}
+
+	excess := len(deadLetters) - s.config.MaxDeadLetters
+	if s.config.MaxDeadLetters <= 0 || excess <= 0 {
+		return
+	}
+	sort.Slice(deadLetters, func(i, j int) bool { return deadLetters[i].deadAt().Before(deadLetters[j].deadAt()) })
+	for _, delivery := range deadLetters[:excess] {
+		delete(s.state.DeadLetters, delivery.ID)
+	}
+	s.dirty = true
+}
+
+// send POSTs a delivery to its subscriber
+func (s *Service) send(sub *Subscription, delivery *Delivery) error {
+	req, err := http.NewRequest("POST", sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "github-api-service-fanout")
+	req.Header.Set("X-GitHub-Event", delivery.Event)
+	req.Header.Set("X-GitHub-Delivery", delivery.GitHubDelivery)
+	req.Header.Set("X-Fanout-Delivery", delivery.ID)
+	req.Header.Set("X-Fanout-Attempt", strconv.Itoa(delivery.Attempts+1))
+	if sub.Secret != "" {
+		req.Header.Set("X-Hub-Signature-256", webhooks.Sign([]byte(sub.Secret), delivery.Payload))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("subscriber returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt, doubling after each
+// failure up to the configured maximum, with up to 20% jitter
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := float64(s.config.InitialBackoff) * math.Pow(2, float64(attempts-1))
+	if max := float64(s.config.MaxBackoff); max > 0 && delay > max {
+		delay = max
+	}
+	return time.Duration(delay * (1 + 0.2*mathrand.Float64()))
+}
+
+// persist writes the state to disk; callers must hold the lock
+func (s *Service) persist() error {
+	if s.store == nil {
+		s.dirty = false
+		return nil
+	}
+	if err := s.store.Save(&s.state); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// newID returns a random identifier
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
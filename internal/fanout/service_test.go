@@ -0,0 +1,197 @@
+package fanout
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns a fan-out configuration with short retry delays
+func testConfig(t *testing.T) config.FanoutConfig {
+	return config.FanoutConfig{
+		StatePath:      filepath.Join(t.TempDir(), "fanout.json"),
+		Workers:        2,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Timeout:        time.Second,
+	}
+}
+
+// testEvent returns a webhook event for a repository
+func testEvent(eventType, repository string) *webhooks.Event {
+	return &webhooks.Event{
+		Type:       eventType,
+		DeliveryID: "github-delivery",
+		Repository: repository,
+		Raw:        json.RawMessage(`{"action":"opened"}`),
+	}
+}
+
+// TestSubscriptionMatches tests event and repository filters
+func TestSubscriptionMatches(t *testing.T) {
+	sub := Subscription{Events: []string{"issues", "push"}, Repos: []string{"test-user/api-*"}}
+
+	assert.True(t, sub.Matches("issues", "test-user/api-gateway"))
+	assert.False(t, sub.Matches("release", "test-user/api-gateway"))
+	assert.False(t, sub.Matches("issues", "test-user/website"))
+	assert.True(t, (&Subscription{}).Matches("release", "anything/at-all"))
+}
+
+// TestDeliverySigned tests that matching events are forwarded with signature headers
+func TestDeliverySigned(t *testing.T) {
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service, err := NewService(testConfig(t))
+	require.NoError(t, err)
+	service.Start()
+	defer service.Stop()
+
+	_, err = service.CreateSubscription(Subscription{URL: server.URL, Events: []string{"issues"}, Secret: "sub-secret"})
+	require.NoError(t, err)
+
+	require.NoError(t, service.HandleEvent(testEvent("push", "test-user/test-repo")))
+	require.NoError(t, service.HandleEvent(testEvent("issues", "test-user/test-repo")))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "issues", received[0].Header.Get("X-GitHub-Event"))
+	assert.Equal(t, "github-delivery", received[0].Header.Get("X-GitHub-Delivery"))
+	assert.True(t, webhooks.VerifySignature([]byte("sub-secret"), bodies[0], received[0].Header.Get("X-Hub-Signature-256")))
+}
+
+// TestDeadLetterAndReplay tests retries, the persisted dead-letter queue and replay
+func TestDeadLetterAndReplay(t *testing.T) {
+	var healthy atomic.Bool
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testConfig(t)
+	service, err := NewService(cfg)
+	require.NoError(t, err)
+	service.Start()
+
+	_, err = service.CreateSubscription(Subscription{URL: server.URL})
+	require.NoError(t, err)
+	require.NoError(t, service.HandleEvent(testEvent("issues", "test-user/test-repo")))
+
+	assert.Eventually(t, func() bool { return len(service.ListDeadLetters()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(3), attempts.Load())
+	service.Stop()
+
+	// The dead letter survives a restart
+	restarted, err := NewService(cfg)
+	require.NoError(t, err)
+	restarted.Start()
+	defer restarted.Stop()
+
+	deadLetters := restarted.ListDeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Contains(t, deadLetters[0].LastError, "503")
+
+	// Replaying once the subscriber recovers delivers the event
+	healthy.Store(true)
+	require.NoError(t, restarted.Replay(deadLetters[0].ID))
+	assert.Eventually(t, func() bool { return attempts.Load() == 4 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, restarted.ListDeadLetters())
+	assert.ErrorIs(t, restarted.Replay(deadLetters[0].ID), ErrNotFound)
+}
+
+// TestPendingResumedOnStart tests that deliveries persisted before a restart are sent
+func TestPendingResumedOnStart(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := testConfig(t)
+
+	// Queue an event without starting the workers
+	service, err := NewService(cfg)
+	require.NoError(t, err)
+	_, err = service.CreateSubscription(Subscription{URL: server.URL})
+	require.NoError(t, err)
+	require.NoError(t, service.HandleEvent(testEvent("push", "test-user/test-repo")))
+	assert.Equal(t, int32(0), attempts.Load())
+
+	restarted, err := NewService(cfg)
+	require.NoError(t, err)
+	restarted.Start()
+	defer restarted.Stop()
+
+	assert.Eventually(t, func() bool { return attempts.Load() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+// TestDeadLetterPruning tests that dead letters are aged out and capped, oldest first
+func TestDeadLetterPruning(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxDeadLetters = 2
+	cfg.DeadLetterTTL = time.Hour
+	service, err := NewService(cfg)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	for i, age := range []time.Duration{2 * time.Hour, 30 * time.Minute, 20 * time.Minute, 10 * time.Minute} {
+		failedAt := now.Add(-age)
+		id := strconv.Itoa(i)
+		service.state.DeadLetters[id] = &Delivery{ID: id, CreatedAt: failedAt, LastAttemptAt: &failedAt}
+	}
+
+	service.mu.Lock()
+	service.pruneDeadLettersLocked(now)
+	service.mu.Unlock()
+
+	var kept []string
+	for _, delivery := range service.ListDeadLetters() {
+		kept = append(kept, delivery.ID)
+	}
+	assert.Equal(t, []string{"2", "3"}, kept)
+
+	// The pruned queue is persisted when the service stops
+	service.Stop()
+	cfg.MaxDeadLetters = 0
+	cfg.DeadLetterTTL = 0
+	restarted, err := NewService(cfg)
+	require.NoError(t, err)
+	assert.Len(t, restarted.ListDeadLetters(), 2)
+}
@@ -0,0 +1,78 @@
+package fanout
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+)
+
+// Subscription forwards matching webhook events to a downstream URL
+type Subscription struct {
+	ID string `json:"id"`
+	// URL receives a POST for every matching event
+	URL string `json:"url"`
+	// Events lists the event types to forward; empty forwards every type
+	Events []string `json:"events"`
+	// Repos lists glob patterns matched against the repository full name; empty matches every repository
+	Repos []string `json:"repos"`
+	// Secret signs forwarded payloads in the X-Hub-Signature-256 header
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether an event should be forwarded to the subscription
+func (s *Subscription) Matches(eventType, repository string) bool {
+	return matchesEvent(s.Events, eventType) && matchesRepo(s.Repos, repository)
+}
+
+// Redacted returns a copy of the subscription without its secret
+func (s Subscription) Redacted() Subscription {
+	s.Secret = ""
+	return s
+}
+
+func matchesEvent(events []string, eventType string) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, event := range events {
+		if event == "*" || event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRepo(patterns []string, repository string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repository); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a single event forwarded to a single subscription
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	Event          string          `json:"event"`
+	GitHubDelivery string          `json:"github_delivery"`
+	Repository     string          `json:"repository"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+}
+
+// deadAt returns when a dead letter failed its last attempt
+func (d *Delivery) deadAt() time.Time {
+	if d.LastAttemptAt != nil {
+		return *d.LastAttemptAt
+	}
+	return d.CreatedAt
+}
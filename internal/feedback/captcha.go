@@ -0,0 +1,51 @@
+package feedback
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// CAPTCHA providers selected by config.FeedbackConfig.Captcha
+const (
+	CaptchaNone = ""
+	CaptchaStub = "stub"
+)
+
+// ErrCaptchaFailed is returned when a CAPTCHA response is missing or wrong
+var ErrCaptchaFailed = errors.New("captcha verification failed")
+
+// Verifier checks the CAPTCHA response submitted with a form. Implementations
+// for hosted providers verify the response with the provider's API.
+type Verifier interface {
+	Verify(ctx context.Context, response, remoteIP string) error
+}
+
+// StubVerifier accepts one fixed response. It stands in for a CAPTCHA
+// provider in development and tests.
+type StubVerifier struct {
+	Response string
+}
+
+// Verify implements Verifier
+func (v StubVerifier) Verify(_ context.Context, response, _ string) error {
+	if response == "" || subtle.ConstantTimeCompare([]byte(response), []byte(v.Response)) != 1 {
+		return ErrCaptchaFailed
+	}
+	return nil
+}
+
+// NewVerifier returns the verifier for a provider, or nil when CAPTCHAs are disabled
+func NewVerifier(provider, secret string) (Verifier, error) {
+	switch provider {
+	case CaptchaNone:
+		return nil, nil
+	case CaptchaStub:
+		if secret == "" {
+			return nil, errors.New("the stub CAPTCHA requires a secret response")
+		}
+		return StubVerifier{Response: secret}, nil
+	}
+	return nil, fmt.Errorf("unknown CAPTCHA provider %q", provider)
+}
@@ -0,0 +1,86 @@
+package feedback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForms tests that form tokens are bound to a repository and a fill time window
+func TestForms(t *testing.T) {
+	forms, err := NewForms("test-secret", 3*time.Second, time.Hour)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	forms.nowFunc = func() time.Time { return now }
+	token, expiresAt := forms.Issue("website")
+	assert.Equal(t, now.Add(time.Hour), expiresAt)
+
+	assert.ErrorIs(t, forms.Check("website", token), ErrTooFast)
+
+	now = now.Add(10 * time.Second)
+	assert.ErrorIs(t, forms.Check("other-repo", token), ErrInvalidToken)
+	assert.NoError(t, forms.Check("website", token))
+	assert.ErrorIs(t, forms.Check("website", "1700000000.nonce.forged"), ErrInvalidToken)
+	assert.ErrorIs(t, forms.Check("website", "garbage"), ErrInvalidToken)
+
+	expiring, _ := forms.Issue("website")
+	now = now.Add(2 * time.Hour)
+	assert.ErrorIs(t, forms.Check("website", expiring), ErrExpired)
+
+	// Tokens from another secret are not accepted
+	other, err := NewForms("", 0, time.Hour)
+	require.NoError(t, err)
+	otherToken, _ := other.Issue("website")
+	assert.ErrorIs(t, forms.Check("website", otherToken), ErrInvalidToken)
+}
+
+// TestFormsSingleUse tests that a replayed form token is refused
+func TestFormsSingleUse(t *testing.T) {
+	forms, err := NewForms("test-secret", 0, time.Hour)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	forms.nowFunc = func() time.Time { return now }
+	token, _ := forms.Issue("website")
+	other, _ := forms.Issue("website")
+	assert.NotEqual(t, token, other)
+
+	// Validating a token does not use it up
+	assert.NoError(t, forms.Validate("website", token))
+	assert.NoError(t, forms.Validate("website", token))
+	assert.NoError(t, forms.Check("website", token))
+	assert.ErrorIs(t, forms.Validate("website", token), ErrUsed)
+	assert.ErrorIs(t, forms.Check("website", token), ErrUsed)
+	assert.NoError(t, forms.Check("website", other))
+
+	// Used tokens are forgotten once they expire, when they are refused as expired instead
+	now = now.Add(2 * time.Hour)
+	assert.ErrorIs(t, forms.Check("website", token), ErrExpired)
+	forms.mu.Lock()
+	forms.lastSweep = time.Time{}
+	forms.sweepLocked(now)
+	assert.Empty(t, forms.used)
+	forms.mu.Unlock()
+}
+
+// TestNewVerifier tests choosing and using CAPTCHA verifiers
+func TestNewVerifier(t *testing.T) {
+	verifier, err := NewVerifier(CaptchaNone, "")
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	verifier, err = NewVerifier(CaptchaStub, "let-me-in")
+	require.NoError(t, err)
+	assert.NoError(t, verifier.Verify(context.Background(), "let-me-in", "127.0.0.1"))
+	assert.ErrorIs(t, verifier.Verify(context.Background(), "wrong", "127.0.0.1"), ErrCaptchaFailed)
+	assert.ErrorIs(t, verifier.Verify(context.Background(), "", "127.0.0.1"), ErrCaptchaFailed)
+
+	_, err = NewVerifier(CaptchaStub, "")
+	assert.Error(t, err)
+	_, err = NewVerifier("recaptcha", "secret")
+	assert.Error(t, err)
+}
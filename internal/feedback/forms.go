@@ -0,0 +1,158 @@
+package feedback
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for form tokens that were not issued for the repository
+	ErrInvalidToken = errors.New("invalid form token")
+	// ErrTooFast is returned for forms submitted sooner than a person could fill them in
+	ErrTooFast = errors.New("form submitted too quickly")
+	// ErrExpired is returned for form tokens older than the maximum age
+	ErrExpired = errors.New("form token expired")
+	// ErrUsed is returned for form tokens that were already submitted
+	ErrUsed = errors.New("form token already used")
+)
+
+// usedSweepInterval is how often expired tokens are forgotten
+const usedSweepInterval = time.Minute
+
+// Forms issues and checks the tokens embedded in feedback forms. A token
+// records when the form was shown, so that submissions made sooner than a
+// person could fill the form in are refused, and carries a random nonce so
+// that each form can be submitted only once.
+type Forms struct {
+	secret      []byte
+	minFillTime time.Duration
+	maxAge      time.Duration
+
+	// used maps the nonces of submitted tokens to when the tokens expire;
+	// they are remembered in memory only, until then
+	mu        sync.Mutex
+	used      map[string]time.Time
+	lastSweep time.Time
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewForms creates a Forms. Without a secret a random one is generated, so
+// tokens issued before a restart are no longer accepted.
+func NewForms(secret string, minFillTime, maxAge time.Duration) (*Forms, error) {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate form secret: %w", err)
+		}
+	}
+
+	return &Forms{
+		secret:      key,
+		minFillTime: minFillTime,
+		maxAge:      maxAge,
+		used:        make(map[string]time.Time),
+		lastSweep:   time.Now(),
+		nowFunc:     time.Now,
+	}, nil
+}
+
+// Issue returns a token for a form posting feedback to repo, and when it expires
+func (f *Forms) Issue(repo string) (string, time.Time) {
+	issuedAt := f.nowFunc().Unix()
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	nonce := hex.EncodeToString(b)
+	return fmt.Sprintf("%d.%s.%s", issuedAt, nonce, f.sign(repo, issuedAt, nonce)), time.Unix(issuedAt, 0).Add(f.maxAge)
+}
+
+// Validate verifies that token was issued for repo long enough ago, but not
+// too long, and has not been submitted before, without using it up. It lets a
+// submission be rejected for other reasons and then corrected and resent.
+func (f *Forms) Validate(repo, token string) error {
+	nonce, _, err := f.verify(repo, token)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, used := f.used[nonce]; used {
+		return ErrUsed
+	}
+	return nil
+}
+
+// Check verifies token like Validate. A token that passes is used up.
+func (f *Forms) Check(repo, token string) error {
+	nonce, expiresAt, err := f.verify(repo, token)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sweepLocked(f.nowFunc())
+	if _, used := f.used[nonce]; used {
+		return ErrUsed
+	}
+	f.used[nonce] = expiresAt
+	return nil
+}
+
+// verify checks the signature and age of token, returning its nonce and when
+// it expires
+func (f *Forms) verify(repo, token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	issued, nonce, signature := parts[0], parts[1], parts[2]
+	issuedAt, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(f.sign(repo, issuedAt, nonce))) {
+		return "", time.Time{}, ErrInvalidToken
+	}
+
+	elapsed := f.nowFunc().Sub(time.Unix(issuedAt, 0))
+	if elapsed < f.minFillTime {
+		return "", time.Time{}, ErrTooFast
+	}
+	if elapsed > f.maxAge {
+		return "", time.Time{}, ErrExpired
+	}
+	return nonce, time.Unix(issuedAt, 0).Add(f.maxAge), nil
+}
+
+// sweepLocked forgets used tokens that have expired, as they would be
+// refused anyway; the caller must hold f.mu
+func (f *Forms) sweepLocked(now time.Time) {
+	if now.Sub(f.lastSweep) < usedSweepInterval {
+		return
+	}
+	f.lastSweep = now
+
+	for nonce, expiresAt := range f.used {
+		if now.After(expiresAt) {
+			delete(f.used, nonce)
+		}
+	}
+}
+
+// sign returns the signature binding a token to its repository, issue time and nonce
+func (f *Forms) sign(repo string, issuedAt int64, nonce string) string {
+	mac := hmac.New(sha256.New, f.secret)
+	fmt.Fprintf(mac, "%s\n%d\n%s", repo, issuedAt, nonce)
+	return hex.EncodeToString(mac.Sum(nil))
+}
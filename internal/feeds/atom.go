@@ -0,0 +1,259 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// Feed represents an Atom feed document
+type Feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Author  *Person  `xml:"author,omitempty"`
+	Entries []Entry  `xml:"entry"`
+
+	updatedAt time.Time
+}
+
+// Entry represents a single Atom feed entry
+type Entry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Updated   string   `xml:"updated"`
+	Published string   `xml:"published,omitempty"`
+	Link      Link     `xml:"link"`
+	Author    *Person  `xml:"author,omitempty"`
+	Content   *Content `xml:"content,omitempty"`
+}
+
+// Link represents an Atom link element
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Person represents an Atom author
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// Content represents the content of an Atom entry
+type Content struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// UpdatedAt returns the time the feed was last updated
+func (f *Feed) UpdatedAt() time.Time {
+	return f.updatedAt
+}
+
+// Marshal renders the feed as an XML document
+func (f *Feed) Marshal() ([]byte, error) {
+	body, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// newFeed creates a feed and stamps it with the latest entry update time.
+// A feed without entries is stamped with the Unix epoch so that its
+// validators stay stable between requests.
+func newFeed(id, title, selfURL, htmlURL string, entries []Entry, times []time.Time) *Feed {
+	updated := time.Unix(0, 0).UTC()
+	for _, t := range times {
+		if t.After(updated) {
+			updated = t
+		}
+	}
+
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	return &Feed{
+		ID:      id,
+		Title:   title,
+		Updated: formatTime(updated),
+		Links: []Link{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: htmlURL, Rel: "alternate", Type: "text/html"},
+		},
+		Entries:   entries,
+		updatedAt: updated,
+	}
+}
+
+// ReleasesFeed builds a feed of a repository's published releases
+func ReleasesFeed(owner, repo string, releases []models.Release, selfURL string) *Feed {
+	var entries []Entry
+	var times []time.Time
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		published := parseTime(release.PublishedAt)
+		title := release.Name
+		if title == "" {
+			title = release.TagName
+		}
+
+		entries = append(entries, Entry{
+			ID:        fmt.Sprintf("tag:github.com,2008:Repository/%s/%s/Release/%d", owner, repo, release.ID),
+			Title:     title,
+			Updated:   formatTime(published),
+			Published: formatTime(published),
+			Link:      Link{Href: release.HTMLURL, Rel: "alternate", Type: "text/html"},
+			Author:    &Person{Name: release.Author.Login, URI: release.Author.HTMLURL},
+			Content:   &Content{Type: "text", Body: release.Body},
+		})
+		times = append(times, published)
+	}
+
+	return newFeed(
+		fmt.Sprintf("tag:github.com,2008:Repository/%s/%s/releases", owner, repo),
+		fmt.Sprintf("Releases of %s/%s", owner, repo),
+		selfURL,
+		fmt.Sprintf("https://github.com/%s/%s/releases", owner, repo),
+		entries,
+		times,
+	)
+}
+
+// IssuesFeed builds a feed of a repository's recently updated issues
+func IssuesFeed(owner, repo string, issues []models.IssueResponse, selfURL string) *Feed {
+	var entries []Entry
+	var times []time.Time
+	for _, issue := range issues {
+		updated := parseTime(issue.UpdatedAt)
+
+		entries = append(entries, Entry{
+			ID:        fmt.Sprintf("tag:github.com,2008:Repository/%s/%s/Issue/%d", owner, repo, issue.ID),
+			Title:     fmt.Sprintf("#%d %s [%s]", issue.Number, issue.Title, issue.State),
+			Updated:   formatTime(updated),
+			Published: formatTime(parseTime(issue.CreatedAt)),
+			Link:      Link{Href: issue.HTMLURL, Rel: "alternate", Type: "text/html"},
+			Author:    &Person{Name: issue.User.Login, URI: issue.User.HTMLURL},
+			Content:   &Content{Type: "text", Body: issue.Body},
+		})
+		times = append(times, updated)
+	}
+
+	return newFeed(
+		fmt.Sprintf("tag:github.com,2008:Repository/%s/%s/issues", owner, repo),
+		fmt.Sprintf("Issues of %s/%s", owner, repo),
+		selfURL,
+		fmt.Sprintf("https://github.com/%s/%s/issues", owner, repo),
+		entries,
+		times,
+	)
+}
+
+// ActivityFeed builds a feed of a user's public activity
+func ActivityFeed(username string, events []models.Event, selfURL string) *Feed {
+	var entries []Entry
+	var times []time.Time
+	for _, event := range events {
+		// The feed is public, so events from private repositories are never listed
+		if !event.Public {
+			continue
+		}
+		created := parseTime(event.CreatedAt)
+		title, link := describeEvent(event)
+
+		entries = append(entries, Entry{
+			ID:      fmt.Sprintf("tag:github.com,2008:%s/%s", event.Type, event.ID),
+			Title:   title,
+			Updated: formatTime(created),
+			Link:    Link{Href: link, Rel: "alternate", Type: "text/html"},
+			Author:  &Person{Name: event.Actor.Login, URI: "https://github.com/" + event.Actor.Login},
+		})
+		times = append(times, created)
+	}
+
+	return newFeed(
+		fmt.Sprintf("tag:github.com,2008:User/%s/activity", username),
+		fmt.Sprintf("Activity of %s", username),
+		selfURL,
+		"https://github.com/"+username,
+		entries,
+		times,
+	)
+}
+
+// eventPayload holds the payload fields used to describe an event
+type eventPayload struct {
+	Action  string `json:"action"`
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+	Issue   *struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	} `json:"issue"`
+	PullRequest *struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	} `json:"pull_request"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	} `json:"release"`
+}
+
+// describeEvent returns a human readable title and a link for an event
+func describeEvent(event models.Event) (string, string) {
+	actor := event.Actor.Login
+	repo := event.Repo.Name
+	link := "https://github.com/" + repo
+
+	var payload eventPayload
+	_ = json.Unmarshal(event.Payload, &payload)
+
+	switch {
+	case event.Type == "PushEvent":
+		return fmt.Sprintf("%s pushed to %s in %s", actor, payload.Ref, repo), link
+	case event.Type == "IssuesEvent" && payload.Issue != nil:
+		return fmt.Sprintf("%s %s issue #%d in %s: %s", actor, payload.Action, payload.Issue.Number, repo, payload.Issue.Title), payload.Issue.HTMLURL
+	case event.Type == "IssueCommentEvent" && payload.Issue != nil:
+		return fmt.Sprintf("%s commented on #%d in %s: %s", actor, payload.Issue.Number, repo, payload.Issue.Title), payload.Issue.HTMLURL
+	case event.Type == "PullRequestEvent" && payload.PullRequest != nil:
+		return fmt.Sprintf("%s %s pull request #%d in %s: %s", actor, payload.Action, payload.PullRequest.Number, repo, payload.PullRequest.Title), payload.PullRequest.HTMLURL
+	case event.Type == "ReleaseEvent" && payload.Release != nil:
+		return fmt.Sprintf("%s %s release %s in %s", actor, payload.Action, payload.Release.TagName, repo), payload.Release.HTMLURL
+	case event.Type == "CreateEvent":
+		return fmt.Sprintf("%s created %s %s in %s", actor, payload.RefType, payload.Ref, repo), link
+	case event.Type == "WatchEvent":
+		return fmt.Sprintf("%s starred %s", actor, repo), link
+	case event.Type == "ForkEvent":
+		return fmt.Sprintf("%s forked %s", actor, repo), link
+	default:
+		return fmt.Sprintf("%s: %s in %s", actor, event.Type, repo), link
+	}
+}
+
+// parseTime parses a GitHub timestamp, returning the zero time if it is invalid
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// formatTime formats a time as an Atom date construct
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,74 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReleasesFeed tests that drafts are skipped and the feed is stamped with the latest release
+func TestReleasesFeed(t *testing.T) {
+	releases := []models.Release{
+		{ID: 2, TagName: "v1.1.0", PublishedAt: "2025-03-02T10:00:00Z", HTMLURL: "https://github.com/test-user/test-repo/releases/v1.1.0"},
+		{ID: 1, TagName: "v1.0.0", Name: "First", PublishedAt: "2025-03-01T10:00:00Z"},
+		{ID: 3, TagName: "v2.0.0", Draft: true},
+	}
+
+	feed := ReleasesFeed("test-user", "test-repo", releases, "http://localhost/feeds/test-repo/releases.atom")
+
+	assert.Len(t, feed.Entries, 2)
+	assert.Equal(t, "2025-03-02T10:00:00Z", feed.Updated)
+	assert.Equal(t, "tag:github.com,2008:Repository/test-user/test-repo/Release/2", feed.Entries[0].ID)
+	assert.Equal(t, "v1.1.0", feed.Entries[0].Title)
+	assert.Equal(t, "First", feed.Entries[1].Title)
+
+	body, err := feed.Marshal()
+	assert.NoError(t, err)
+
+	var decoded Feed
+	assert.NoError(t, xml.Unmarshal(body, &decoded))
+	assert.Equal(t, feed.ID, decoded.ID)
+}
+
+// TestEmptyFeedIsStable tests that an empty feed has a fixed updated timestamp
+func TestEmptyFeedIsStable(t *testing.T) {
+	feed := IssuesFeed("test-user", "test-repo", nil, "http://localhost/feeds/test-repo/issues.atom")
+
+	assert.Equal(t, time.Unix(0, 0).UTC(), feed.UpdatedAt())
+	assert.Empty(t, feed.Entries)
+}
+
+// TestActivityFeed tests that public events are described from their payloads
+func TestActivityFeed(t *testing.T) {
+	events := []models.Event{
+		{
+			ID:        "42",
+			Type:      "IssuesEvent",
+			Actor:     models.EventActor{Login: "test-user"},
+			Repo:      models.EventRepo{Name: "test-user/test-repo"},
+			Payload:   json.RawMessage(`{"action":"opened","issue":{"number":7,"title":"Bug","html_url":"https://github.com/test-user/test-repo/issues/7"}}`),
+			Public:    true,
+			CreatedAt: "2025-03-09T08:00:00Z",
+		},
+		{
+			ID:        "43",
+			Type:      "PushEvent",
+			Actor:     models.EventActor{Login: "test-user"},
+			Repo:      models.EventRepo{Name: "test-user/private-repo"},
+			Payload:   json.RawMessage(`{"ref":"refs/heads/main"}`),
+			Public:    false,
+			CreatedAt: "2025-03-09T09:00:00Z",
+		},
+	}
+
+	feed := ActivityFeed("test-user", events, "http://localhost/feeds/activity.atom")
+
+	assert.Len(t, feed.Entries, 1)
+	assert.Equal(t, "tag:github.com,2008:IssuesEvent/42", feed.Entries[0].ID)
+	assert.Equal(t, "test-user opened issue #7 in test-user/test-repo: Bug", feed.Entries[0].Title)
+	assert.Equal(t, "https://github.com/test-user/test-repo/issues/7", feed.Entries[0].Link.Href)
+}
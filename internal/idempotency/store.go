@@ -0,0 +1,100 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInFlight is returned when a key is reused while its first request is still running
+	ErrInFlight = errors.New("request with this idempotency key is in progress")
+	// ErrMismatch is returned when a key is reused for a different request
+	ErrMismatch = errors.New("idempotency key was used for a different request")
+)
+
+// Response is a stored response that is replayed for retries
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// entry is a key's request fingerprint and, once it completes, its response
+type entry struct {
+	fingerprint string
+	response    *Response
+	expiresAt   time.Time
+}
+
+// Store remembers the response to each idempotency key for a window so
+// that retried requests are answered without running them again
+type Store struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*entry
+	nowFunc func() time.Time
+}
+
+// NewStore creates a Store that remembers keys for ttl
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+		nowFunc: time.Now,
+	}
+}
+
+// Begin reserves key for a request. It returns the stored response when the
+// request already completed, ErrInFlight while it is running, ErrMismatch
+// when the key was used with another fingerprint, and nil values when the
+// caller should run the request and then call Complete or Release.
+func (s *Store) Begin(key, fingerprint string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.nowFunc()
+	s.evictExpired(now)
+
+	e, ok := s.entries[key]
+	if !ok {
+		s.entries[key] = &entry{fingerprint: fingerprint, expiresAt: now.Add(s.ttl)}
+		return nil, nil
+	}
+	if e.fingerprint != fingerprint {
+		return nil, ErrMismatch
+	}
+	if e.response == nil {
+		return nil, ErrInFlight
+	}
+	return e.response, nil
+}
+
+// Complete stores the response to a key reserved by Begin
+func (s *Store) Complete(key string, response *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.response = response
+		e.expiresAt = s.nowFunc().Add(s.ttl)
+	}
+}
+
+// Release forgets a key reserved by Begin so that a retry runs the request again
+func (s *Store) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// evictExpired removes expired keys; callers must hold the lock
+func (s *Store) evictExpired(now time.Time) {
+	for key, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStore tests reservation, replay, mismatches, release and expiry
+func TestStore(t *testing.T) {
+	store := NewStore(time.Hour)
+	now := time.Now()
+	store.nowFunc = func() time.Time { return now }
+
+	response, err := store.Begin("k1", "a")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	_, err = store.Begin("k1", "a")
+	assert.ErrorIs(t, err, ErrInFlight)
+	_, err = store.Begin("k1", "b")
+	assert.ErrorIs(t, err, ErrMismatch)
+
+	store.Complete("k1", &Response{Status: 201, Body: []byte(`{"number":1}`)})
+	response, err = store.Begin("k1", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, 201, response.Status)
+
+	_, _ = store.Begin("k2", "a")
+	store.Release("k2")
+	response, err = store.Begin("k2", "a")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	// Keys are forgotten after the window
+	now = now.Add(2 * time.Hour)
+	response, err = store.Begin("k1", "b")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
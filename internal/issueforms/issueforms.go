@@ -0,0 +1,526 @@
+package issueforms
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the directory GitHub reads a repository's issue templates from
+const Dir = ".github/ISSUE_TEMPLATE"
+
+// Kinds of issue template
+const (
+	// KindMarkdown templates prefill a free text body
+	KindMarkdown = "markdown"
+	// KindForm templates are YAML issue forms made of typed fields
+	KindForm = "form"
+)
+
+// Issue form element types
+const (
+	elementMarkdown   = "markdown"
+	elementInput      = "input"
+	elementTextarea   = "textarea"
+	elementDropdown   = "dropdown"
+	elementCheckboxes = "checkboxes"
+)
+
+// noResponse is what GitHub renders for a field left empty
+const noResponse = "_No response_"
+
+// schemaDialect is the JSON Schema version of the schemas returned by Schema
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ErrNotTemplate is returned by Parse for files in the template directory
+// that are not templates, such as the template chooser's config.yml
+var ErrNotTemplate = errors.New("not an issue template")
+
+// ValidationError lists the submitted values that do not match a form, by field
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid values for %d fields", len(e.Fields))
+}
+
+// Template is a parsed issue template or issue form
+type Template struct {
+	Kind        string
+	Name        string
+	Description string
+	Title       string
+	Labels      []string
+	Assignees   []string
+	// Markdown is the body prefilled by a markdown template
+	Markdown string
+	// Elements are the fields and text of an issue form
+	Elements []Element
+}
+
+// Element is a field or block of text in an issue form
+type Element struct {
+	Type        string      `yaml:"type"`
+	ID          string      `yaml:"id"`
+	Attributes  Attributes  `yaml:"attributes"`
+	Validations Validations `yaml:"validations"`
+}
+
+// Attributes configure how an issue form element is shown
+type Attributes struct {
+	Label       string   `yaml:"label"`
+	Description string   `yaml:"description"`
+	Placeholder string   `yaml:"placeholder"`
+	Value       string   `yaml:"value"`
+	Render      string   `yaml:"render"`
+	Multiple    bool     `yaml:"multiple"`
+	Default     *int     `yaml:"default"`
+	Options     []Option `yaml:"options"`
+}
+
+// Validations constrain the value of an issue form element
+type Validations struct {
+	Required bool `yaml:"required"`
+}
+
+// Option is a dropdown option or a checkbox
+type Option struct {
+	Label    string `yaml:"label"`
+	Required bool   `yaml:"required"`
+}
+
+// UnmarshalYAML accepts dropdown options, which are plain strings, as well as checkboxes
+func (o *Option) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		o.Label = value.Value
+		return nil
+	}
+	type option Option
+	return value.Decode((*option)(o))
+}
+
+// stringList accepts a YAML list or a comma separated string, as GitHub does for labels and assignees
+type stringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	var items []string
+	if value.Kind == yaml.ScalarNode {
+		items = strings.Split(value.Value, ",")
+	} else if err := value.Decode(&items); err != nil {
+		return err
+	}
+
+	*l = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// header holds the settings shared by markdown templates and issue forms
+type header struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	About       string     `yaml:"about"`
+	Title       string     `yaml:"title"`
+	Labels      stringList `yaml:"labels"`
+	Assignees   stringList `yaml:"assignees"`
+}
+
+// form is the layout of an issue form file
+type form struct {
+	header `yaml:",inline"`
+	Body   []Element `yaml:"body"`
+}
+
+// IsTemplateFile reports whether file can hold a template, by its name
+func IsTemplateFile(file string) bool {
+	name := strings.ToLower(path.Base(file))
+	switch path.Ext(name) {
+	case ".md":
+		return true
+	case ".yml", ".yaml":
+		return strings.TrimSuffix(name, path.Ext(name)) != "config"
+	}
+	return false
+}
+
+// Parse parses the template in file, choosing markdown or issue form by its extension
+func Parse(file string, data []byte) (*Template, error) {
+	if !IsTemplateFile(file) {
+		return nil, ErrNotTemplate
+	}
+
+	var t *Template
+	var err error
+	if strings.EqualFold(path.Ext(file), ".md") {
+		t, err = parseMarkdown(data)
+	} else {
+		t, err = parseForm(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Name == "" {
+		return nil, errors.New("issue template has no name")
+	}
+	return t, nil
+}
+
+// parseMarkdown parses a markdown template, whose settings are in YAML front matter
+func parseMarkdown(data []byte) (*Template, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, errors.New("markdown template has no front matter")
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return nil, errors.New("markdown template front matter is not closed")
+	}
+	frontMatter, body := text[4:4+end], text[4+end+len("\n---"):]
+
+	var h header
+	if err := yaml.Unmarshal([]byte(frontMatter), &h); err != nil {
+		return nil, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+
+	t := newTemplate(KindMarkdown, &h)
+	t.Markdown = strings.TrimPrefix(body, "\n")
+	return t, nil
+}
+
+// parseForm parses a YAML issue form, checking the elements GitHub requires
+func parseForm(data []byte) (*Template, error) {
+	var f form
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse issue form: %w", err)
+	}
+	if len(f.Body) == 0 {
+		return nil, errors.New("issue form has no body")
+	}
+
+	seen := make(map[string]bool)
+	for i, element := range f.Body {
+		switch element.Type {
+		case elementMarkdown:
+			continue
+		case elementInput, elementTextarea:
+		case elementDropdown, elementCheckboxes:
+			if len(element.Attributes.Options) == 0 {
+				return nil, fmt.Errorf("body[%d]: %s has no options", i, element.Type)
+			}
+		default:
+			return nil, fmt.Errorf("body[%d]: unknown element type %q", i, element.Type)
+		}
+
+		if element.Attributes.Label == "" {
+			return nil, fmt.Errorf("body[%d]: %s has no label", i, element.Type)
+		}
+		key := element.Key()
+		if seen[key] {
+			return nil, fmt.Errorf("body[%d]: duplicate field %q", i, key)
+		}
+		seen[key] = true
+	}
+
+	t := newTemplate(KindForm, &f.header)
+	t.Elements = f.Body
+	return t, nil
+}
+
+// newTemplate creates a template from its settings
+func newTemplate(kind string, h *header) *Template {
+	description := h.Description
+	if description == "" {
+		description = h.About
+	}
+	return &Template{
+		Kind:        kind,
+		Name:        h.Name,
+		Description: description,
+		Title:       h.Title,
+		Labels:      h.Labels,
+		Assignees:   h.Assignees,
+	}
+}
+
+// Key returns the name the element's value is submitted under: its id, or
+// its label when it has none
+func (e *Element) Key() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.Attributes.Label
+}
+
+// optionLabels returns the labels of the element's options
+func (e *Element) optionLabels() []string {
+	labels := make([]string, 0, len(e.Attributes.Options))
+	for _, option := range e.Attributes.Options {
+		labels = append(labels, option.Label)
+	}
+	return labels
+}
+
+// Summary describes the template as returned by the API
+func (t *Template) Summary(file string) models.IssueTemplate {
+	return models.IssueTemplate{
+		File:        file,
+		Kind:        t.Kind,
+		Name:        t.Name,
+		Description: t.Description,
+		Title:       t.Title,
+		Labels:      t.Labels,
+		Assignees:   t.Assignees,
+		Schema:      t.Schema(),
+	}
+}
+
+// Schema returns a JSON schema of the values accepted by Render
+func (t *Template) Schema() *models.JSONSchema {
+	closed := false
+	schema := &models.JSONSchema{
+		Schema:               schemaDialect,
+		Title:                t.Name,
+		Description:          t.Description,
+		Type:                 "object",
+		Properties:           make(map[string]*models.JSONSchema),
+		AdditionalProperties: &closed,
+	}
+
+	if t.Kind == KindMarkdown {
+		schema.Properties["body"] = &models.JSONSchema{Title: "Body", Type: "string", Default: t.Markdown}
+		schema.PropertyOrder = []string{"body"}
+		return schema
+	}
+
+	for i := range t.Elements {
+		element := &t.Elements[i]
+		if element.Type == elementMarkdown {
+			continue
+		}
+
+		attributes := element.Attributes
+		property := &models.JSONSchema{
+			Title:       attributes.Label,
+			Description: attributes.Description,
+		}
+		required := element.Validations.Required
+
+		switch element.Type {
+		case elementInput, elementTextarea:
+			property.Type = "string"
+			if attributes.Value != "" {
+				property.Default = attributes.Value
+			}
+		case elementDropdown:
+			options := element.optionLabels()
+			var defaultOption string
+			if attributes.Default != nil && *attributes.Default >= 0 && *attributes.Default < len(options) {
+				defaultOption = options[*attributes.Default]
+			}
+			if attributes.Multiple {
+				property.Type = "array"
+				property.Items = &models.JSONSchema{Type: "string", Enum: options}
+				property.UniqueItems = true
+				if defaultOption != "" {
+					property.Default = []string{defaultOption}
+				}
+			} else {
+				property.Type = "string"
+				property.Enum = options
+				if defaultOption != "" {
+					property.Default = defaultOption
+				}
+			}
+		case elementCheckboxes:
+			property.Type = "array"
+			property.Items = &models.JSONSchema{Type: "string", Enum: element.optionLabels()}
+			property.UniqueItems = true
+			for _, option := range attributes.Options {
+				required = required || option.Required
+			}
+		}
+
+		key := element.Key()
+		schema.Properties[key] = property
+		schema.PropertyOrder = append(schema.PropertyOrder, key)
+		if required {
+			schema.Required = append(schema.Required, key)
+		}
+	}
+	return schema
+}
+
+// Render validates the submitted values and renders the issue body the way
+// GitHub does: a heading per field, followed by its value
+func (t *Template) Render(values map[string]interface{}) (string, error) {
+	errs := make(map[string]string)
+	known := t.Schema().Properties
+	for key := range values {
+		if known[key] == nil {
+			errs[key] = "is not a field of this template"
+		}
+	}
+
+	if t.Kind == KindMarkdown {
+		body, ok := stringValue(values["body"])
+		if !ok {
+			errs["body"] = "must be a string"
+		}
+		if len(errs) > 0 {
+			return "", &ValidationError{Fields: errs}
+		}
+		if strings.TrimSpace(body) == "" {
+			body = t.Markdown
+		}
+		return body, nil
+	}
+
+	var sections []string
+	for i := range t.Elements {
+		element := &t.Elements[i]
+		if element.Type == elementMarkdown {
+			continue
+		}
+
+		value, problem := renderElement(element, values[element.Key()])
+		if problem != "" {
+			errs[element.Key()] = problem
+			continue
+		}
+		sections = append(sections, "### "+element.Attributes.Label+"\n\n"+value)
+	}
+	if len(errs) > 0 {
+		return "", &ValidationError{Fields: errs}
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// renderElement validates and renders the value of one field, returning a
+// description of the problem when the value is invalid
+func renderElement(element *Element, value interface{}) (string, string) {
+	attributes := element.Attributes
+	required := element.Validations.Required
+
+	switch element.Type {
+	case elementInput, elementTextarea:
+		text, ok := stringValue(value)
+		if !ok {
+			return "", "must be a string"
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			if required {
+				return "", "is required"
+			}
+			return noResponse, ""
+		}
+		if element.Type == elementTextarea && attributes.Render != "" {
+			return "```" + attributes.Render + "\n" + text + "\n```", ""
+		}
+		return text, ""
+
+	case elementDropdown:
+		var selected []string
+		if attributes.Multiple {
+			var ok bool
+			if selected, ok = stringsValue(value); !ok {
+				return "", "must be a list of options"
+			}
+		} else {
+			option, ok := stringValue(value)
+			if !ok {
+				return "", "must be one of the options"
+			}
+			if option != "" {
+				selected = []string{option}
+			}
+		}
+		for _, option := range selected {
+			if !contains(element.optionLabels(), option) {
+				return "", fmt.Sprintf("%q is not one of the options", option)
+			}
+		}
+		if len(selected) == 0 {
+			if required {
+				return "", "is required"
+			}
+			return noResponse, ""
+		}
+		return strings.Join(selected, ", "), ""
+
+	case elementCheckboxes:
+		checked, ok := stringsValue(value)
+		if !ok {
+			return "", "must be a list of checked options"
+		}
+		for _, option := range checked {
+			if !contains(element.optionLabels(), option) {
+				return "", fmt.Sprintf("%q is not one of the options", option)
+			}
+		}
+
+		lines := make([]string, 0, len(attributes.Options))
+		for _, option := range attributes.Options {
+			mark := " "
+			if contains(checked, option.Label) {
+				mark = "X"
+			} else if option.Required {
+				return "", fmt.Sprintf("%q must be checked", option.Label)
+			}
+			lines = append(lines, "- ["+mark+"] "+option.Label)
+		}
+		return strings.Join(lines, "\n"), ""
+	}
+	return "", "has an unknown type"
+}
+
+// stringValue reads a submitted string; a missing value is empty
+func stringValue(value interface{}) (string, bool) {
+	if value == nil {
+		return "", true
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// stringsValue reads a submitted list of strings; a missing value is empty
+func stringsValue(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
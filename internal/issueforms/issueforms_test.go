@@ -0,0 +1,152 @@
+package issueforms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bugReportForm = `name: Bug report
+description: File a bug report
+title: "[Bug]: "
+labels: ["bug", "triage"]
+assignees: octocat
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      required: true
+  - type: textarea
+    id: logs
+    attributes:
+      label: Relevant log output
+      render: shell
+  - type: dropdown
+    id: browsers
+    attributes:
+      label: Browsers
+      multiple: true
+      options:
+        - Firefox
+        - Chrome
+        - Safari
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow this project's Code of Conduct
+          required: true
+        - label: I searched for existing issues
+`
+
+const featureTemplate = `---
+name: Feature request
+about: Suggest an idea
+title: ''
+labels: enhancement, idea
+assignees: ''
+---
+
+**Describe the solution you'd like**
+`
+
+// TestParse tests parsing issue forms and markdown templates
+func TestParse(t *testing.T) {
+	form, err := Parse("bug_report.yml", []byte(bugReportForm))
+	require.NoError(t, err)
+	assert.Equal(t, KindForm, form.Kind)
+	assert.Equal(t, "Bug report", form.Name)
+	assert.Equal(t, "[Bug]: ", form.Title)
+	assert.Equal(t, []string{"bug", "triage"}, form.Labels)
+	assert.Equal(t, []string{"octocat"}, form.Assignees)
+	assert.Len(t, form.Elements, 5)
+
+	markdown, err := Parse("feature.md", []byte(featureTemplate))
+	require.NoError(t, err)
+	assert.Equal(t, KindMarkdown, markdown.Kind)
+	assert.Equal(t, "Suggest an idea", markdown.Description)
+	assert.Equal(t, []string{"enhancement", "idea"}, markdown.Labels)
+	assert.Empty(t, markdown.Assignees)
+	assert.Equal(t, "\n**Describe the solution you'd like**\n", markdown.Markdown)
+
+	_, err = Parse("config.yml", []byte("blank_issues_enabled: false"))
+	assert.True(t, errors.Is(err, ErrNotTemplate))
+	_, err = Parse("README.txt", []byte("text"))
+	assert.True(t, errors.Is(err, ErrNotTemplate))
+
+	_, err = Parse("broken.yml", []byte("name: Broken\nbody:\n  - type: slider\n    attributes:\n      label: Level\n"))
+	assert.ErrorContains(t, err, "unknown element type")
+	_, err = Parse("nameless.md", []byte("---\nabout: x\n---\nbody"))
+	assert.ErrorContains(t, err, "no name")
+}
+
+// TestSchema tests describing an issue form's fields as a JSON schema
+func TestSchema(t *testing.T) {
+	form, err := Parse("bug_report.yml", []byte(bugReportForm))
+	require.NoError(t, err)
+
+	schema := form.Schema()
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"version", "logs", "browsers", "terms"}, schema.PropertyOrder)
+	assert.Equal(t, []string{"version", "terms"}, schema.Required)
+	assert.Equal(t, "string", schema.Properties["version"].Type)
+	assert.Equal(t, "array", schema.Properties["browsers"].Type)
+	assert.Equal(t, []string{"Firefox", "Chrome", "Safari"}, schema.Properties["browsers"].Items.Enum)
+	require.NotNil(t, schema.AdditionalProperties)
+	assert.False(t, *schema.AdditionalProperties)
+}
+
+// TestRender tests validating form values and rendering them as GitHub does
+func TestRender(t *testing.T) {
+	form, err := Parse("bug_report.yml", []byte(bugReportForm))
+	require.NoError(t, err)
+
+	body, err := form.Render(map[string]interface{}{
+		"version":  "1.2.0",
+		"browsers": []interface{}{"Firefox", "Safari"},
+		"terms":    []interface{}{"I agree to follow this project's Code of Conduct"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "### Version\n\n1.2.0\n\n"+
+		"### Relevant log output\n\n_No response_\n\n"+
+		"### Browsers\n\nFirefox, Safari\n\n"+
+		"### Code of Conduct\n\n- [X] I agree to follow this project's Code of Conduct\n- [ ] I searched for existing issues", body)
+
+	body, err = form.Render(map[string]interface{}{
+		"version": "1.2.0",
+		"logs":    "panic: boom",
+		"terms":   []interface{}{"I agree to follow this project's Code of Conduct"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, body, "### Relevant log output\n\n```shell\npanic: boom\n```")
+
+	_, err = form.Render(map[string]interface{}{
+		"browsers": []interface{}{"Lynx"},
+		"color":    "blue",
+	})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, map[string]string{
+		"version":  "is required",
+		"browsers": `"Lynx" is not one of the options`,
+		"terms":    `"I agree to follow this project's Code of Conduct" must be checked`,
+		"color":    "is not a field of this template",
+	}, validationErr.Fields)
+
+	markdown, err := Parse("feature.md", []byte(featureTemplate))
+	require.NoError(t, err)
+	body, err = markdown.Render(nil)
+	require.NoError(t, err)
+	assert.Equal(t, markdown.Markdown, body)
+	body, err = markdown.Render(map[string]interface{}{"body": "Dark mode"})
+	require.NoError(t, err)
+	assert.Equal(t, "Dark mode", body)
+}
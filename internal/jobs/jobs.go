@@ -0,0 +1,535 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNotFound is returned when a job does not exist
+	ErrNotFound = errors.New("job not found")
+	// ErrUnknownType is returned when no handler is registered for a job's type
+	ErrUnknownType = errors.New("unknown job type")
+	// ErrFinished is returned when cancelling a job that has already ended
+	ErrFinished = errors.New("job has already finished")
+)
+
+// queueSize bounds the number of jobs waiting for a worker
+const queueSize = 1024
+
+// Status is the stage a job has reached
+type Status string
+
+// Job statuses
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished reports whether a job in this status will not run again
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Result is the outcome of one item of a job, such as a row of a bulk import
+type Result struct {
+	Item int `json:"item"`
+	// Label describes the item to people, e.g. the title of an imported issue
+	Label  string          `json:"label,omitempty"`
+	Status Status          `json:"status"`
+	Error  string          `json:"error,omitempty"`
+	Output json.RawMessage `json:"output,omitempty"`
+}
+
+// Job is a unit of background work and its progress
+type Job struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Status  Status          `json:"status"`
+	Payload json.RawMessage `json:"-"`
+	// Owner is the ID of the principal that created the job, and Repo the
+	// repository it works on, so that access to it can be checked
+	Owner string `json:"owner,omitempty"`
+	Repo  string `json:"repo,omitempty"`
+	// Total is the number of items; Completed and Failed count those finished
+	Total     int      `json:"total"`
+	Completed int      `json:"completed"`
+	Failed    int      `json:"failed"`
+	Results   []Result `json:"results"`
+	// Attempts counts the runs so far; Error is why the last one failed
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// RunAt is when a delayed or retried job is next due
+	RunAt     *time.Time `json:"run_at,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// Handler runs a job, reporting the outcome of each item through run. A
+// returned error fails the run, which is retried unless the error is
+// Permanent. A job can be run more than once, whether retried or resumed after
+// a restart, so handlers should skip the items already in job.Results.
+type Handler func(ctx context.Context, job *Job, run *Run) error
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so that the job fails without being retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Run records the progress of a running job
+type Run struct {
+	manager *Manager
+	id      string
+}
+
+// Record stores the outcome of one item. Only the new result is written,
+// outside the lock, so that long jobs do not hold up other callers.
+func (r *Run) Record(result Result) {
+	m := r.manager
+	m.mu.Lock()
+	job, ok := m.jobs[r.id]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	job.Results = append(job.Results, result)
+	if result.Status == StatusFailed {
+		job.Failed++
+	} else {
+		job.Completed++
+	}
+	index := len(job.Results) - 1
+	m.mu.Unlock()
+
+	if m.store == nil {
+		return
+	}
+	if err := m.store.saveResult(r.id, index, result); err != nil {
+		logrus.WithError(err).WithField("job_id", r.id).Error("Failed to persist job progress")
+	}
+}
+
+// Manager runs jobs in the background on a pool of workers and keeps their
+// state so that callers can follow progress. Jobs are persisted, so those
+// queued or interrupted when the service stops run again on the next start.
+type Manager struct {
+	config   config.JobsConfig
+	handlers map[string]Handler
+	store    *store
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	running map[string]context.CancelFunc
+
+	queue    chan string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewManager creates a Manager, loading any persisted jobs
+func NewManager(cfg config.JobsConfig) (*Manager, error) {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		config:   cfg,
+		handlers: make(map[string]Handler),
+		jobs:     make(map[string]*Job),
+		running:  make(map[string]context.CancelFunc),
+		queue:    make(chan string, queueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if cfg.DBPath != "" {
+		store, err := openStore(cfg.DBPath)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		jobs, err := store.load()
+		if err != nil {
+			store.close()
+			cancel()
+			return nil, err
+		}
+		for _, job := range jobs {
+			// The service stopped while the job was running, so it runs again
+			if job.Status == StatusRunning {
+				job.Status = StatusQueued
+			}
+			m.jobs[job.ID] = job
+		}
+		m.store = store
+	}
+
+	return m, nil
+}
+
+// Register sets the handler for jobs of a type; it must be called before Start
+func (m *Manager) Register(jobType string, handler Handler) {
+	m.handlers[jobType] = handler
+}
+
+// Start launches the workers and queues the jobs that were waiting when the service last stopped
+func (m *Manager) Start() {
+	for i := 0; i < m.config.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+
+	m.mu.Lock()
+	var pending []*Job
+	for _, job := range m.jobs {
+		if job.Status == StatusQueued {
+			pending = append(pending, job.snapshot())
+		}
+	}
+	m.mu.Unlock()
+
+	for _, job := range pending {
+		m.schedule(job.ID, job.RunAt)
+	}
+	if len(pending) > 0 {
+		logrus.WithField("count", len(pending)).Info("Resumed pending jobs")
+	}
+}
+
+// Stop interrupts running jobs and waits for the workers to return. The
+// interrupted jobs stay queued and resume on the next start.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		m.cancel()
+		m.wg.Wait()
+		if m.store != nil {
+			if err := m.store.close(); err != nil {
+				logrus.WithError(err).Error("Failed to close job database")
+			}
+		}
+	})
+}
+
+// Enqueue creates a job of a type for total items and queues it to run as
+// soon as a worker is free. The payload is stored as JSON and handed to the
+// job's handler.
+func (m *Manager) Enqueue(jobType string, payload interface{}, total int) (*Job, error) {
+	return m.Schedule(jobType, payload, total, Options{})
+}
+
+// Options are the optional settings of a scheduled job
+type Options struct {
+	// RunAt delays the job; the zero time runs it as soon as a worker is free
+	RunAt time.Time
+	// Owner and Repo are recorded on the job
+	Owner string
+	Repo  string
+}
+
+// Schedule creates a job like Enqueue with options
+func (m *Manager) Schedule(jobType string, payload interface{}, total int, opts Options) (*Job, error) {
+	if _, ok := m.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &Job{
+		ID:        newID(),
+		Type:      jobType,
+		Status:    StatusQueued,
+		Payload:   data,
+		Owner:     opts.Owner,
+		Repo:      opts.Repo,
+		Total:     total,
+		Results:   []Result{},
+		CreatedAt: time.Now().UTC(),
+	}
+	if runAt := opts.RunAt; runAt.After(job.CreatedAt) {
+		runAt = runAt.UTC()
+		job.RunAt = &runAt
+	}
+
+	m.mu.Lock()
+	m.evictFinished(job.CreatedAt)
+	m.jobs[job.ID] = job
+	if err := m.persist(job); err != nil {
+		delete(m.jobs, job.ID)
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to persist job: %w", err)
+	}
+	snapshot := job.snapshot()
+	m.mu.Unlock()
+
+	m.schedule(job.ID, job.RunAt)
+	return snapshot, nil
+}
+
+// Get returns a copy of a job
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.snapshot(), nil
+}
+
+// Cancel stops a job. A queued job never runs; a running job's context is
+// cancelled, and the items it already finished stay recorded.
+func (m *Manager) Cancel(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if job.Status.Finished() {
+		return nil, ErrFinished
+	}
+
+	now := time.Now().UTC()
+	job.Status = StatusCancelled
+	job.RunAt = nil
+	job.EndedAt = &now
+	if cancel, ok := m.running[id]; ok {
+		cancel()
+	}
+	if err := m.persist(job); err != nil {
+		logrus.WithError(err).WithField("job_id", id).Error("Failed to persist cancelled job")
+	}
+	return job.snapshot(), nil
+}
+
+// schedule queues a job once runAt has passed
+func (m *Manager) schedule(id string, runAt *time.Time) {
+	if runAt != nil {
+		if delay := time.Until(*runAt); delay > 0 {
+			time.AfterFunc(delay, func() { m.enqueue(id) })
+			return
+		}
+	}
+	m.enqueue(id)
+}
+
+// enqueue hands a job to the workers, waiting for capacity if the queue is full
+func (m *Manager) enqueue(id string) {
+	select {
+	case <-m.ctx.Done():
+	case m.queue <- id:
+	default:
+		time.AfterFunc(time.Second, func() { m.enqueue(id) })
+	}
+}
+
+// worker runs queued jobs until the manager stops
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case id := <-m.queue:
+			m.run(id)
+		}
+	}
+}
+
+// run runs a job once and records how it ended
+func (m *Manager) run(id string) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	// The job may have been cancelled while it waited
+	if !ok || job.Status != StatusQueued {
+		m.mu.Unlock()
+		return
+	}
+	handler, ok := m.handlers[job.Type]
+	if !ok {
+		m.finish(job, StatusFailed, fmt.Errorf("%w: %s", ErrUnknownType, job.Type))
+		m.mu.Unlock()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+	now := time.Now().UTC()
+	job.Status = StatusRunning
+	job.Attempts++
+	job.RunAt = nil
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	m.running[id] = cancel
+	if err := m.persist(job); err != nil {
+		logrus.WithError(err).WithField("job_id", id).Error("Failed to persist job")
+	}
+	snapshot := job.snapshot()
+	m.mu.Unlock()
+
+	entry := logrus.WithFields(logrus.Fields{
+		"job_id":   id,
+		"job_type": snapshot.Type,
+		"attempt":  snapshot.Attempts,
+	})
+	entry.Info("Job started")
+
+	err := handler(ctx, snapshot, &Run{manager: m, id: id})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.running, id)
+
+	var permanent *permanentError
+	switch {
+	case job.Status == StatusCancelled:
+		entry.Info("Job cancelled")
+		if err := m.persist(job); err != nil {
+			entry.WithError(err).Error("Failed to persist job")
+		}
+	case err != nil && m.ctx.Err() != nil:
+		// Stopping is not the job's fault, so the run does not count
+		job.Status = StatusQueued
+		job.Attempts--
+		if err := m.persist(job); err != nil {
+			entry.WithError(err).Error("Failed to persist job")
+		}
+		entry.Info("Job interrupted; it resumes on the next start")
+	case err == nil:
+		m.finish(job, StatusSucceeded, nil)
+		entry.WithFields(logrus.Fields{
+			"completed": job.Completed,
+			"failed":    job.Failed,
+		}).Info("Job finished")
+	case errors.As(err, &permanent) || job.Attempts >= m.config.MaxAttempts:
+		m.finish(job, StatusFailed, err)
+		entry.WithError(err).Error("Job failed")
+	default:
+		backoff := m.backoff(job.Attempts)
+		runAt := time.Now().UTC().Add(backoff)
+		job.Status = StatusQueued
+		job.Error = err.Error()
+		job.RunAt = &runAt
+		if err := m.persist(job); err != nil {
+			entry.WithError(err).Error("Failed to persist job")
+		}
+		entry.WithError(err).WithField("retry_in", backoff).Warn("Job failed; retrying")
+		m.schedule(id, &runAt)
+	}
+}
+
+// finish records that a job has ended; callers must hold the lock
+func (m *Manager) finish(job *Job, status Status, err error) {
+	now := time.Now().UTC()
+	job.Status = status
+	job.EndedAt = &now
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if err := m.persist(job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("Failed to persist job")
+	}
+}
+
+// backoff returns the delay before the next run, doubling after each
+// failure up to the configured maximum, with up to 20% jitter
+func (m *Manager) backoff(attempts int) time.Duration {
+	delay := float64(m.config.InitialBackoff) * math.Pow(2, float64(attempts-1))
+	if max := float64(m.config.MaxBackoff); max > 0 && delay > max {
+		delay = max
+	}
+	return time.Duration(delay * (1 + 0.2*mathrand.Float64()))
+}
+
+// evictFinished forgets jobs that ended more than the retention ago; callers must hold the lock
+func (m *Manager) evictFinished(now time.Time) {
+	var evicted []string
+	for id, job := range m.jobs {
+		if job.EndedAt != nil && now.Sub(*job.EndedAt) > m.config.Retention {
+			delete(m.jobs, id)
+			evicted = append(evicted, id)
+		}
+	}
+	if len(evicted) > 0 && m.store != nil {
+		if err := m.store.delete(evicted); err != nil {
+			logrus.WithError(err).Error("Failed to delete expired jobs")
+		}
+	}
+}
+
+// persist writes a job to the database; callers must hold the lock
+func (m *Manager) persist(job *Job) error {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.save(job)
+}
+
+// snapshot returns a copy of the job that is safe to use without the lock
+func (j *Job) snapshot() *Job {
+	snapshot := *j
+	snapshot.Results = append([]Result{}, j.Results...)
+	return &snapshot
+}
+
+// newID returns a random identifier
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ErrorReport writes the failed items of a job as CSV
+func ErrorReport(job *Job, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"item", "label", "error"}); err != nil {
+		return err
+	}
+	for _, result := range job.Results {
+		if result.Status != StatusFailed {
+			continue
+		}
+		if err := writer.Write([]string{strconv.Itoa(result.Item), result.Label, result.Error}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,306 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// testConfig returns a job configuration with a temporary database and short retry delays
+func testConfig(t *testing.T) config.JobsConfig {
+	return config.JobsConfig{
+		DBPath:         filepath.Join(t.TempDir(), "jobs.db"),
+		Workers:        1,
+		Retention:      time.Hour,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+// newTestManager creates a manager that is stopped when the test ends
+func newTestManager(t *testing.T, cfg config.JobsConfig) *Manager {
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(manager.Stop)
+	return manager
+}
+
+// waitForStatus waits for a job to reach a status and returns it
+func waitForStatus(t *testing.T, manager *Manager, id string, status Status) *Job {
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = manager.Get(id)
+		return err == nil && job.Status == status
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+// TestJobProgress tests that results are recorded as a job runs
+func TestJobProgress(t *testing.T) {
+	manager := newTestManager(t, testConfig(t))
+	manager.Register("count", func(ctx context.Context, job *Job, run *Run) error {
+		var items []string
+		require.NoError(t, json.Unmarshal(job.Payload, &items))
+		for i, item := range items {
+			result := Result{Item: i + 1, Label: item, Status: StatusSucceeded}
+			if item == "bad" {
+				result.Status = StatusFailed
+				result.Error = "bad item"
+			}
+			run.Record(result)
+		}
+		return nil
+	})
+	manager.Start()
+
+	job, err := manager.Enqueue("count", []string{"a", "bad", "c"}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+
+	job = waitForStatus(t, manager, job.ID, StatusSucceeded)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 2, job.Completed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Len(t, job.Results, 3)
+	assert.Equal(t, 1, job.Attempts)
+	assert.NotNil(t, job.EndedAt)
+
+	var report bytes.Buffer
+	require.NoError(t, ErrorReport(job, &report))
+	assert.Equal(t, "item,label,error\n2,bad,bad item\n", report.String())
+}
+
+// TestJobRetried tests that failed runs are retried until MaxAttempts
+func TestJobRetried(t *testing.T) {
+	var runs atomic.Int32
+	manager := newTestManager(t, testConfig(t))
+	manager.Register("flaky", func(ctx context.Context, job *Job, run *Run) error {
+		if runs.Add(1) < 2 {
+			return errors.New("upstream unavailable")
+		}
+		return nil
+	})
+	manager.Register("broken", func(ctx context.Context, job *Job, run *Run) error {
+		return errors.New("upstream unavailable")
+	})
+	manager.Start()
+
+	flaky, err := manager.Enqueue("flaky", nil, 0)
+	require.NoError(t, err)
+	flaky = waitForStatus(t, manager, flaky.ID, StatusSucceeded)
+	assert.Equal(t, 2, flaky.Attempts)
+
+	broken, err := manager.Enqueue("broken", nil, 0)
+	require.NoError(t, err)
+	broken = waitForStatus(t, manager, broken.ID, StatusFailed)
+	assert.Equal(t, 3, broken.Attempts)
+	assert.Equal(t, "upstream unavailable", broken.Error)
+}
+
+// TestJobPermanentError tests that permanent errors are not retried
+func TestJobPermanentError(t *testing.T) {
+	manager := newTestManager(t, testConfig(t))
+	manager.Register("invalid", func(ctx context.Context, job *Job, run *Run) error {
+		return Permanent(errors.New("invalid payload"))
+	})
+	manager.Start()
+
+	job, err := manager.Enqueue("invalid", nil, 0)
+	require.NoError(t, err)
+
+	job = waitForStatus(t, manager, job.ID, StatusFailed)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Equal(t, "invalid payload", job.Error)
+}
+
+// TestJobScheduled tests that a delayed job does not run before it is due
+func TestJobScheduled(t *testing.T) {
+	manager := newTestManager(t, testConfig(t))
+	manager.Register("noop", func(ctx context.Context, job *Job, run *Run) error { return nil })
+	manager.Start()
+
+	runAt := time.Now().Add(100 * time.Millisecond)
+	job, err := manager.Schedule("noop", nil, 0, Options{RunAt: runAt})
+	require.NoError(t, err)
+	require.NotNil(t, job.RunAt)
+
+	time.Sleep(20 * time.Millisecond)
+	job, err = manager.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+
+	job = waitForStatus(t, manager, job.ID, StatusSucceeded)
+	assert.False(t, job.StartedAt.Before(runAt.Truncate(time.Millisecond)))
+}
+
+// TestJobCancelled tests cancelling queued and running jobs
+func TestJobCancelled(t *testing.T) {
+	started := make(chan struct{})
+	manager := newTestManager(t, testConfig(t))
+	manager.Register("wait", func(ctx context.Context, job *Job, run *Run) error {
+		run.Record(Result{Item: 1, Status: StatusSucceeded})
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	manager.Start()
+
+	running, err := manager.Enqueue("wait", nil, 2)
+	require.NoError(t, err)
+	<-started
+
+	delayed, err := manager.Schedule("wait", nil, 2, Options{RunAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	cancelled, err := manager.Cancel(delayed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+
+	cancelled, err = manager.Cancel(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+
+	job := waitForStatus(t, manager, running.ID, StatusCancelled)
+	assert.Equal(t, 1, job.Completed)
+
+	_, err = manager.Cancel(running.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+	_, err = manager.Cancel("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestJobResumed tests that jobs interrupted by a restart run again, keeping their progress
+func TestJobResumed(t *testing.T) {
+	cfg := testConfig(t)
+	started := make(chan struct{})
+
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	manager.Register("import", func(ctx context.Context, job *Job, run *Run) error {
+		run.Record(Result{Item: 1, Status: StatusSucceeded})
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	manager.Start()
+
+	job, err := manager.Enqueue("import", map[string]string{"repo": "test-repo"}, 2)
+	require.NoError(t, err)
+	delayed, err := manager.Schedule("import", nil, 0, Options{RunAt: time.Now().Add(time.Hour), Owner: "key:ci", Repo: "test-repo"})
+	require.NoError(t, err)
+	<-started
+	manager.Stop()
+
+	var resumedFrom int
+	var payload json.RawMessage
+	restarted := newTestManager(t, cfg)
+	restarted.Register("import", func(ctx context.Context, job *Job, run *Run) error {
+		resumedFrom = len(job.Results)
+		payload = job.Payload
+		run.Record(Result{Item: 2, Status: StatusSucceeded})
+		return nil
+	})
+
+	interrupted, err := restarted.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, interrupted.Status)
+	restarted.Start()
+
+	job = waitForStatus(t, restarted, job.ID, StatusSucceeded)
+	assert.Equal(t, 1, resumedFrom)
+	assert.JSONEq(t, `{"repo":"test-repo"}`, string(payload))
+	assert.Equal(t, 2, job.Completed)
+	assert.Equal(t, 1, job.Attempts)
+
+	// A delayed job keeps its schedule across the restart
+	delayed, err = restarted.Get(delayed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, delayed.Status)
+	assert.NotNil(t, delayed.RunAt)
+	assert.Equal(t, "key:ci", delayed.Owner)
+	assert.Equal(t, "test-repo", delayed.Repo)
+}
+
+// TestEnqueueUnknownType tests that jobs need a registered handler
+func TestEnqueueUnknownType(t *testing.T) {
+	manager := newTestManager(t, testConfig(t))
+
+	_, err := manager.Enqueue("missing", nil, 0)
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	_, err = manager.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestEvictFinished tests that finished jobs are forgotten after the retention
+func TestEvictFinished(t *testing.T) {
+	cfg := testConfig(t)
+	manager, err := NewManager(cfg)
+	require.NoError(t, err)
+	manager.Register("noop", func(ctx context.Context, job *Job, run *Run) error { return nil })
+
+	old, err := manager.Enqueue("noop", nil, 0)
+	require.NoError(t, err)
+	ended := time.Now().Add(-2 * time.Hour)
+	manager.finish(manager.jobs[old.ID], StatusSucceeded, nil)
+	manager.jobs[old.ID].EndedAt = &ended
+
+	queued, err := manager.Enqueue("noop", nil, 0)
+	require.NoError(t, err)
+
+	_, err = manager.Get(old.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = manager.Get(queued.ID)
+	assert.NoError(t, err)
+	manager.Stop()
+
+	// The evicted job is gone from the database too
+	reopened := newTestManager(t, cfg)
+	_, err = reopened.Get(old.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = reopened.Get(queued.ID)
+	assert.NoError(t, err)
+}
+
+// TestStoreResults tests that results are stored apart from their job and removed with it
+func TestStoreResults(t *testing.T) {
+	s, err := openStore(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+	defer s.close()
+
+	job := &Job{ID: "job-1", Type: "import", Status: StatusRunning, Total: 3, Results: []Result{}}
+	require.NoError(t, s.save(job))
+	for i, status := range []Status{StatusSucceeded, StatusFailed, StatusSucceeded} {
+		require.NoError(t, s.saveResult(job.ID, i, Result{Item: i + 1, Status: status}))
+	}
+
+	// The job record stays the same size however many results it has
+	require.NoError(t, s.db.View(func(tx *bolt.Tx) error {
+		assert.NotContains(t, string(tx.Bucket(jobsBucket).Get([]byte(job.ID))), "results")
+		return nil
+	}))
+
+	jobs, err := s.load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, []int{1, 2, 3}, []int{jobs[0].Results[0].Item, jobs[0].Results[1].Item, jobs[0].Results[2].Item})
+	assert.Equal(t, 2, jobs[0].Completed)
+	assert.Equal(t, 1, jobs[0].Failed)
+
+	require.NoError(t, s.delete([]string{job.ID, "missing"}))
+	require.NoError(t, s.db.View(func(tx *bolt.Tx) error {
+		assert.Nil(t, tx.Bucket(resultsBucket).Bucket([]byte(job.ID)))
+		return nil
+	}))
+}
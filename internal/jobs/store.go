@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
+)
+
+var (
+	// jobsBucket holds one record per job, keyed by ID
+	jobsBucket = []byte("jobs")
+	// resultsBucket holds a bucket of results per job, keyed by item order, so
+	// that recording a result does not rewrite those before it
+	resultsBucket = []byte("results")
+)
+
+// record is how a job is stored; unlike the API view it keeps the payload
+type record struct {
+	*Job
+	Payload json.RawMessage `json:"payload"`
+	// Results shadows the job's results, which are kept in resultsBucket
+	Results []Result `json:"results,omitempty"`
+}
+
+// store persists jobs in a BoltDB file so that they survive restarts
+type store struct {
+	db *bolt.DB
+}
+
+// openStore opens or creates the database at path
+func openStore(path string) (*store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job database %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{jobsBucket, resultsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise job database %s: %w", path, err)
+	}
+	return &store{db: db}, nil
+}
+
+// load returns every stored job with its results
+func (s *store) load() ([]*Job, error) {
+	var jobs []*Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		results := tx.Bucket(resultsBucket)
+		return tx.Bucket(jobsBucket).ForEach(func(key, value []byte) error {
+			rec := record{Job: &Job{}}
+			if err := json.Unmarshal(value, &rec); err != nil {
+				return fmt.Errorf("failed to decode job %s: %w", key, err)
+			}
+			job := rec.Job
+			job.Payload = rec.Payload
+			job.Results = []Result{}
+
+			if bucket := results.Bucket(key); bucket != nil {
+				err := bucket.ForEach(func(_, value []byte) error {
+					var result Result
+					if err := json.Unmarshal(value, &result); err != nil {
+						return fmt.Errorf("failed to decode result of job %s: %w", key, err)
+					}
+					job.Results = append(job.Results, result)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			// The counts are saved with the job less often than its results
+			job.Completed, job.Failed = 0, 0
+			for _, result := range job.Results {
+				if result.Status == StatusFailed {
+					job.Failed++
+				} else {
+					job.Completed++
+				}
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+// saveResult writes the result of a job's item at index
+func (s *store) saveResult(jobID string, index int, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of job %s: %w", jobID, err)
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(index))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(resultsBucket).CreateBucketIfNotExists([]byte(jobID))
+		if err != nil {
+			return err
+		}
+		return bucket.Put(key, data)
+	})
+}
+
+// save writes a job without its results, replacing any earlier version
+func (s *store) save(job *Job) error {
+	data, err := json.Marshal(record{Job: job, Payload: job.Payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+// delete removes jobs
+func (s *store) delete(ids []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, results := tx.Bucket(jobsBucket), tx.Bucket(resultsBucket)
+		for _, id := range ids {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+			if err := results.DeleteBucket([]byte(id)); err != nil && !errors.Is(err, berrors.ErrBucketNotFound) {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// close closes the database
+func (s *store) close() error {
+	return s.db.Close()
+}
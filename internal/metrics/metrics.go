@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default is the registry exposed on the /metrics endpoint
+var Default = NewRegistry()
+
+// Registry holds metrics and renders them in the Prometheus text format
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// metric is a named family of series distinguished by label values
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// Counter is a monotonically increasing metric
+type Counter struct{ m *metric }
+
+// Gauge is a metric that can go up and down
+type Gauge struct{ m *metric }
+
+// NewCounter registers a counter with the given label names. Registering the
+// same name twice returns the existing counter.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(name, help, "counter", labels)}
+}
+
+// NewGauge registers a gauge with the given label names. Registering the
+// same name twice returns the existing gauge.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(name, help, "gauge", labels)}
+}
+
+func (r *Registry) register(name, help, kind string, labels []string) *metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		return m
+	}
+	m := &metric{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*series)}
+	r.metrics[name] = m
+	return m
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.m.add(1, labelValues)
+}
+
+// Add increments the counter by delta, which must not be negative
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.m.add(delta, labelValues)
+}
+
+// Value returns the current value of a series
+func (c *Counter) Value(labelValues ...string) float64 {
+	return c.m.value(labelValues)
+}
+
+// Set sets the gauge to value
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.m.mu.Lock()
+	defer g.m.mu.Unlock()
+	g.m.get(labelValues).value = value
+}
+
+// Add adds delta to the gauge
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.m.add(delta, labelValues)
+}
+
+// Value returns the current value of a series
+func (g *Gauge) Value(labelValues ...string) float64 {
+	return g.m.value(labelValues)
+}
+
+func (m *metric) add(delta float64, labelValues []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(labelValues).value += delta
+}
+
+func (m *metric) value(labelValues []string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(labelValues).value
+}
+
+// get returns the series for the label values; the caller must hold m.mu
+func (m *metric) get(labelValues []string) *series {
+	// Missing label values are reported as empty rather than panicking
+	values := make([]string, len(m.labels))
+	copy(values, labelValues)
+
+	key := strings.Join(values, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: values}
+		m.series[key] = s
+	}
+	return s
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.Lock()
+		m := r.metrics[name]
+		r.mu.Unlock()
+
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		lines := make([]string, 0, len(m.series))
+		for _, s := range m.series {
+			lines = append(lines, fmt.Sprintf("%s%s %g", m.name, formatLabels(m.labels, s.labelValues), s.value))
+		}
+		m.mu.Unlock()
+		sort.Strings(lines)
+
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry in the Prometheus text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// formatLabels renders a label set such as {policy="drop_oldest"}
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(values[i])
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, value)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryText tests the Prometheus text rendering of counters and gauges
+func TestRegistryText(t *testing.T) {
+	registry := NewRegistry()
+
+	dropped := registry.NewCounter("messages_dropped_total", "Dropped messages", "policy")
+	dropped.Inc("drop_oldest")
+	dropped.Add(2, "drop_oldest")
+	dropped.Add(-1, "drop_oldest")
+	dropped.Inc(`say "hi"`)
+
+	clients := registry.NewGauge("clients", "Connected clients")
+	clients.Add(3)
+	clients.Add(-1)
+
+	// Registering again returns the same metric
+	assert.Equal(t, float64(3), registry.NewCounter("messages_dropped_total", "Dropped messages", "policy").Value("drop_oldest"))
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	resp := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `# HELP clients Connected clients
+# TYPE clients gauge
+clients 2
+# HELP messages_dropped_total Dropped messages
+# TYPE messages_dropped_total counter
+messages_dropped_total{policy="drop_oldest"} 3
+messages_dropped_total{policy="say \"hi\""} 1
+`, resp.Body.String())
+}
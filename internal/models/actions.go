@@ -0,0 +1,97 @@
+package models
+
+// Workflow represents a GitHub Actions workflow
+type Workflow struct {
+	ID        int64  `json:"id"`
+	NodeID    string `json:"node_id"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	State     string `json:"state"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	URL       string `json:"url"`
+	HTMLURL   string `json:"html_url"`
+	BadgeURL  string `json:"badge_url"`
+}
+
+// WorkflowList represents a page of workflows
+type WorkflowList struct {
+	TotalCount int        `json:"total_count"`
+	Workflows  []Workflow `json:"workflows"`
+}
+
+// WorkflowRun represents a single run of a workflow
+type WorkflowRun struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	NodeID       string `json:"node_id"`
+	HeadBranch   string `json:"head_branch"`
+	HeadSHA      string `json:"head_sha"`
+	Path         string `json:"path"`
+	DisplayTitle string `json:"display_title"`
+	RunNumber    int    `json:"run_number"`
+	RunAttempt   int    `json:"run_attempt"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	Conclusion   string `json:"conclusion"`
+	WorkflowID   int64  `json:"workflow_id"`
+	URL          string `json:"url"`
+	HTMLURL      string `json:"html_url"`
+	Actor        Owner  `json:"actor"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+	RunStartedAt string `json:"run_started_at"`
+}
+
+// WorkflowRunList represents a page of workflow runs
+type WorkflowRunList struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// WorkflowRunFilter holds the optional filters for listing workflow runs
+type WorkflowRunFilter struct {
+	Workflow string `form:"workflow"`
+	Branch   string `form:"branch"`
+	Status   string `form:"status"`
+	Event    string `form:"event"`
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+}
+
+// WorkflowJob represents a job within a workflow run
+type WorkflowJob struct {
+	ID          int64          `json:"id"`
+	RunID       int64          `json:"run_id"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Conclusion  string         `json:"conclusion"`
+	StartedAt   string         `json:"started_at"`
+	CompletedAt string         `json:"completed_at"`
+	HTMLURL     string         `json:"html_url"`
+	RunnerName  string         `json:"runner_name"`
+	Labels      []string       `json:"labels"`
+	Steps       []WorkflowStep `json:"steps"`
+}
+
+// WorkflowStep represents a step within a workflow job
+type WorkflowStep struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Conclusion  string `json:"conclusion"`
+	Number      int    `json:"number"`
+	StartedAt   string `json:"started_at"`
+	CompletedAt string `json:"completed_at"`
+}
+
+// WorkflowJobList represents a page of workflow jobs
+type WorkflowJobList struct {
+	TotalCount int           `json:"total_count"`
+	Jobs       []WorkflowJob `json:"jobs"`
+}
+
+// WorkflowDispatchRequest represents a request to trigger a workflow_dispatch event
+type WorkflowDispatchRequest struct {
+	Ref    string                 `json:"ref" binding:"required"`
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Installation represents a GitHub App installation on a user or organization
+type Installation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+	} `json:"account"`
+}
+
+// InstallationToken represents an access token for a GitHub App installation
+type InstallationToken struct {
+	Token       string            `json:"token"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	Permissions map[string]string `json:"permissions"`
+}
+
+// TokenCheck describes the access GitHub reports for the service's token
+type TokenCheck struct {
+	// Token labels the checked token without revealing it
+	Token string
+	// Scopes lists the OAuth scopes of a classic token
+	Scopes []string
+	// Permissions maps the permissions of a GitHub App installation to read or write
+	Permissions map[string]string
+	// Introspectable is false for fine-grained tokens, whose permissions GitHub does not report
+	Introspectable bool
+}
@@ -0,0 +1,14 @@
+package models
+
+// BulkRowError describes a row of a bulk import that cannot be used
+type BulkRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// BulkValidationResponse is returned when rows of a bulk import are invalid;
+// nothing is imported until every row is valid
+type BulkValidationResponse struct {
+	Error string         `json:"error"`
+	Rows  []BulkRowError `json:"rows"`
+}
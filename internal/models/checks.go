@@ -0,0 +1,80 @@
+package models
+
+// CommitStatusRequest represents a request to create a commit status
+type CommitStatusRequest struct {
+	State       string `json:"state" binding:"required,oneof=error failure pending success"`
+	TargetURL   string `json:"target_url,omitempty" binding:"omitempty,url"`
+	Description string `json:"description,omitempty" binding:"max=140"`
+	Context     string `json:"context,omitempty"`
+}
+
+// CommitStatus represents a commit status reported to GitHub
+type CommitStatus struct {
+	ID          int64  `json:"id"`
+	NodeID      string `json:"node_id"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+	Context     string `json:"context"`
+	Creator     Owner  `json:"creator"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// CheckRunRequest represents a request to create or update a check run.
+// Name and HeadSHA are only required when creating a check run.
+type CheckRunRequest struct {
+	Name        string          `json:"name,omitempty"`
+	HeadSHA     string          `json:"head_sha,omitempty"`
+	DetailsURL  string          `json:"details_url,omitempty" binding:"omitempty,url"`
+	ExternalID  string          `json:"external_id,omitempty"`
+	Status      string          `json:"status,omitempty" binding:"omitempty,oneof=queued in_progress completed"`
+	Conclusion  string          `json:"conclusion,omitempty" binding:"required_if=Status completed,omitempty,oneof=action_required cancelled failure neutral success skipped stale timed_out"`
+	StartedAt   string          `json:"started_at,omitempty"`
+	CompletedAt string          `json:"completed_at,omitempty"`
+	Output      *CheckRunOutput `json:"output,omitempty"`
+}
+
+// CheckRunOutput represents the output of a check run
+type CheckRunOutput struct {
+	Title       string               `json:"title" binding:"required"`
+	Summary     string               `json:"summary" binding:"required"`
+	Text        string               `json:"text,omitempty"`
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty" binding:"dive"`
+}
+
+// CheckRunAnnotation represents an annotation on a specific line of a file
+type CheckRunAnnotation struct {
+	Path            string `json:"path" binding:"required"`
+	StartLine       int    `json:"start_line" binding:"required,min=1"`
+	EndLine         int    `json:"end_line" binding:"required,gtefield=StartLine"`
+	StartColumn     int    `json:"start_column,omitempty"`
+	EndColumn       int    `json:"end_column,omitempty"`
+	AnnotationLevel string `json:"annotation_level" binding:"required,oneof=notice warning failure"`
+	Message         string `json:"message" binding:"required"`
+	Title           string `json:"title,omitempty"`
+	RawDetails      string `json:"raw_details,omitempty"`
+}
+
+// CheckRun represents a check run reported to GitHub
+type CheckRun struct {
+	ID          int64  `json:"id"`
+	HeadSHA     string `json:"head_sha"`
+	NodeID      string `json:"node_id"`
+	ExternalID  string `json:"external_id"`
+	URL         string `json:"url"`
+	HTMLURL     string `json:"html_url"`
+	DetailsURL  string `json:"details_url"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Conclusion  string `json:"conclusion"`
+	StartedAt   string `json:"started_at"`
+	CompletedAt string `json:"completed_at"`
+	Output      struct {
+		Title            string `json:"title"`
+		Summary          string `json:"summary"`
+		Text             string `json:"text"`
+		AnnotationsCount int    `json:"annotations_count"`
+	} `json:"output"`
+}
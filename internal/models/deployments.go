@@ -0,0 +1,83 @@
+package models
+
+// DeploymentRequest represents a request to create a deployment
+type DeploymentRequest struct {
+	Ref                   string      `json:"ref" binding:"required"`
+	Task                  string      `json:"task,omitempty"`
+	AutoMerge             *bool       `json:"auto_merge,omitempty"`
+	RequiredContexts      *[]string   `json:"required_contexts,omitempty"`
+	Payload               interface{} `json:"payload,omitempty"`
+	Environment           string      `json:"environment,omitempty"`
+	Description           string      `json:"description,omitempty"`
+	TransientEnvironment  bool        `json:"transient_environment,omitempty"`
+	ProductionEnvironment *bool       `json:"production_environment,omitempty"`
+}
+
+// Deployment represents a GitHub deployment
+type Deployment struct {
+	ID                    int64       `json:"id"`
+	NodeID                string      `json:"node_id"`
+	URL                   string      `json:"url"`
+	SHA                   string      `json:"sha"`
+	Ref                   string      `json:"ref"`
+	Task                  string      `json:"task"`
+	Payload               interface{} `json:"payload"`
+	Environment           string      `json:"environment"`
+	OriginalEnvironment   string      `json:"original_environment"`
+	Description           string      `json:"description"`
+	Creator               Owner       `json:"creator"`
+	CreatedAt             string      `json:"created_at"`
+	UpdatedAt             string      `json:"updated_at"`
+	StatusesURL           string      `json:"statuses_url"`
+	TransientEnvironment  bool        `json:"transient_environment"`
+	ProductionEnvironment bool        `json:"production_environment"`
+}
+
+// DeploymentMergedResponse reports that GitHub merged the default branch into
+// the ref instead of creating a deployment
+type DeploymentMergedResponse struct {
+	Message string `json:"message"`
+}
+
+// DeploymentFilter holds the optional filters for listing deployments
+type DeploymentFilter struct {
+	SHA         string `form:"sha"`
+	Ref         string `form:"ref"`
+	Task        string `form:"task"`
+	Environment string `form:"environment"`
+}
+
+// DeploymentStatusRequest represents a request to create a deployment status
+type DeploymentStatusRequest struct {
+	State          string `json:"state" binding:"required,oneof=error failure inactive in_progress queued pending success"`
+	LogURL         string `json:"log_url,omitempty" binding:"omitempty,url"`
+	EnvironmentURL string `json:"environment_url,omitempty" binding:"omitempty,url"`
+	Description    string `json:"description,omitempty" binding:"max=140"`
+	Environment    string `json:"environment,omitempty"`
+	AutoInactive   *bool  `json:"auto_inactive,omitempty"`
+}
+
+// DeploymentStatus represents a status of a GitHub deployment
+type DeploymentStatus struct {
+	ID             int64  `json:"id"`
+	NodeID         string `json:"node_id"`
+	URL            string `json:"url"`
+	State          string `json:"state"`
+	Creator        Owner  `json:"creator"`
+	Description    string `json:"description"`
+	Environment    string `json:"environment"`
+	LogURL         string `json:"log_url"`
+	EnvironmentURL string `json:"environment_url"`
+	DeploymentURL  string `json:"deployment_url"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// DeploymentCompletionRequest represents a request to move a deployment
+// through in_progress to a final state
+type DeploymentCompletionRequest struct {
+	State          string `json:"state" binding:"required,oneof=success failure error"`
+	LogURL         string `json:"log_url,omitempty" binding:"omitempty,url"`
+	EnvironmentURL string `json:"environment_url,omitempty" binding:"omitempty,url"`
+	Description    string `json:"description,omitempty" binding:"max=140"`
+}
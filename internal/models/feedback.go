@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// FeedbackForm is issued to a page showing the feedback form and is
+// submitted back with it
+type FeedbackForm struct {
+	Token           string    `json:"token"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	CaptchaRequired bool      `json:"captcha_required"`
+}
+
+// FeedbackRequest represents feedback submitted through the public form, as
+// JSON or a form post. Website is a honeypot that people leave empty.
+type FeedbackRequest struct {
+	Title           string `json:"title" form:"title" binding:"required"`
+	Body            string `json:"body" form:"body" binding:"required"`
+	Website         string `json:"website" form:"website"`
+	FormToken       string `json:"form_token" form:"form_token" binding:"required"`
+	CaptchaResponse string `json:"captcha_response" form:"captcha_response"`
+}
+
+// FeedbackResponse acknowledges feedback without revealing the issue it created
+type FeedbackResponse struct {
+	Status string `json:"status"`
+}
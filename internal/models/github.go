@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // UserResponse represents GitHub user data
 type UserResponse struct {
 	Login             string `json:"login"`
@@ -148,8 +150,10 @@ type GithubProfile struct {
 
 // IssueRequest represents a request to create a new GitHub issue
 type IssueRequest struct {
-	Title string `json:"title" binding:"required"`
-	Body  string `json:"body" binding:"required"`
+	Title     string   `json:"title" form:"title" binding:"required"`
+	Body      string   `json:"body" form:"body" binding:"required"`
+	Labels    []string `json:"labels,omitempty" form:"labels"`
+	Assignees []string `json:"assignees,omitempty" form:"assignees"`
 }
 
 // IssueResponse represents a created GitHub issue
@@ -165,7 +169,7 @@ type IssueResponse struct {
 	Number                   int           `json:"number"`
 	Title                    string        `json:"title"`
 	User                     Owner         `json:"user"`
-	Labels                   []string      `json:"labels"`
+	Labels                   []Label       `json:"labels"`
 	State                    string        `json:"state"`
 	Locked                   bool          `json:"locked"`
 	Assignee                 interface{}   `json:"assignee"`
@@ -185,6 +189,84 @@ type IssueResponse struct {
 	State_reason             interface{}   `json:"state_reason"`
 }
 
+// IssueSearchResult represents a page of issue search results
+type IssueSearchResult struct {
+	TotalCount int             `json:"total_count"`
+	Items      []IssueResponse `json:"items"`
+}
+
+// DuplicateCandidate is an open issue that resembles a new one
+type DuplicateCandidate struct {
+	Number  int     `json:"number"`
+	Title   string  `json:"title"`
+	HTMLURL string  `json:"html_url"`
+	Score   float64 `json:"score"`
+}
+
+// DuplicateIssueResponse is returned when an issue is rejected as a likely duplicate
+type DuplicateIssueResponse struct {
+	Error      string               `json:"error"`
+	Candidates []DuplicateCandidate `json:"candidates"`
+}
+
+// Label represents a label attached to a GitHub issue
+type Label struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// Release represents a GitHub release
+type Release struct {
+	ID          int    `json:"id"`
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	Author      Owner  `json:"author"`
+	CreatedAt   string `json:"created_at"`
+	PublishedAt string `json:"published_at"`
+}
+
+// Event represents an entry in the GitHub events API
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     EventActor      `json:"actor"`
+	Repo      EventRepo       `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+	Public    bool            `json:"public"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// EventActor represents the user that triggered an event
+type EventActor struct {
+	ID           int    `json:"id"`
+	Login        string `json:"login"`
+	DisplayLogin string `json:"display_login"`
+	URL          string `json:"url"`
+	AvatarURL    string `json:"avatar_url"`
+}
+
+// EventRepo represents the repository an event belongs to
+type EventRepo struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// EventPage is a conditional read of a repository's events. When NotModified
+// is set, Events is empty and the previously returned events are still current.
+type EventPage struct {
+	Events       []Event
+	ETag         string
+	NotModified  bool
+	PollInterval int
+}
+
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
 	Error string `json:"error"`
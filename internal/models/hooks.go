@@ -0,0 +1,52 @@
+package models
+
+// HookConfig holds the delivery settings of a repository webhook
+type HookConfig struct {
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type,omitempty" binding:"omitempty,oneof=json form"`
+	Secret      string `json:"secret,omitempty"`
+	InsecureSSL string `json:"insecure_ssl,omitempty" binding:"omitempty,oneof=0 1"`
+}
+
+// HookRequest represents a request to create or update a repository webhook
+type HookRequest struct {
+	Name   string      `json:"name,omitempty"`
+	Active *bool       `json:"active,omitempty"`
+	Events []string    `json:"events,omitempty"`
+	Config *HookConfig `json:"config,omitempty"`
+}
+
+// Hook represents a repository webhook
+type Hook struct {
+	ID            int64      `json:"id"`
+	Type          string     `json:"type"`
+	Name          string     `json:"name"`
+	Active        bool       `json:"active"`
+	Events        []string   `json:"events"`
+	Config        HookConfig `json:"config"`
+	URL           string     `json:"url"`
+	TestURL       string     `json:"test_url"`
+	PingURL       string     `json:"ping_url"`
+	DeliveriesURL string     `json:"deliveries_url"`
+	LastResponse  struct {
+		Code    *int   `json:"code"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"last_response"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// HookDelivery represents a recent delivery attempt of a repository webhook
+type HookDelivery struct {
+	ID           int64   `json:"id"`
+	GUID         string  `json:"guid"`
+	DeliveredAt  string  `json:"delivered_at"`
+	Redelivery   bool    `json:"redelivery"`
+	Duration     float64 `json:"duration"`
+	Status       string  `json:"status"`
+	StatusCode   int     `json:"status_code"`
+	Event        string  `json:"event"`
+	Action       string  `json:"action"`
+	RepositoryID int64   `json:"repository_id"`
+}
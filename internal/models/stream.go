@@ -0,0 +1,44 @@
+package models
+
+import "encoding/json"
+
+// Stream client message types
+const (
+	StreamSubscribe   = "subscribe"
+	StreamUnsubscribe = "unsubscribe"
+	StreamAck         = "ack"
+	StreamPing        = "ping"
+)
+
+// Stream server message types
+const (
+	StreamSubscribed   = "subscribed"
+	StreamUnsubscribed = "unsubscribed"
+	StreamEvent        = "event"
+	StreamPong         = "pong"
+	StreamDropped      = "dropped"
+	StreamError        = "error"
+)
+
+// StreamClientMessage is a control message sent by a WebSocket client
+type StreamClientMessage struct {
+	Type string `json:"type"`
+	// ID names a subscription; it is chosen by the client
+	ID          string   `json:"id,omitempty"`
+	Repo        string   `json:"repo,omitempty"`
+	Types       []string `json:"types,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+	LastEventID uint64   `json:"last_event_id,omitempty"`
+	// EventID is the latest event the client has processed, for ack messages
+	EventID uint64 `json:"event_id,omitempty"`
+}
+
+// StreamServerMessage is a message sent to a WebSocket client
+type StreamServerMessage struct {
+	Type        string          `json:"type"`
+	ID          string          `json:"id,omitempty"`
+	Event       json.RawMessage `json:"event,omitempty"`
+	Count       uint64          `json:"count,omitempty"`
+	LastAckedID uint64          `json:"last_acked_id,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
@@ -0,0 +1,55 @@
+package models
+
+// ContentEntry represents a file or directory in a repository
+type ContentEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	SHA      string `json:"sha"`
+	Size     int    `json:"size"`
+	Type     string `json:"type"`
+	Encoding string `json:"encoding,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// JSONSchema describes the values accepted by an issue template
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	// PropertyOrder lists the properties in the order the form shows them
+	PropertyOrder        []string    `json:"propertyOrder,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties *bool       `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema `json:"items,omitempty"`
+	Enum                 []string    `json:"enum,omitempty"`
+	UniqueItems          bool        `json:"uniqueItems,omitempty"`
+	Default              interface{} `json:"default,omitempty"`
+}
+
+// IssueTemplate represents an issue template or issue form defined in a repository
+type IssueTemplate struct {
+	// File is the template's file name in .github/ISSUE_TEMPLATE
+	File        string      `json:"file"`
+	Kind        string      `json:"kind"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Labels      []string    `json:"labels,omitempty"`
+	Assignees   []string    `json:"assignees,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+}
+
+// TemplateIssueRequest represents a request to create an issue from a template.
+// The title defaults to the template's title.
+type TemplateIssueRequest struct {
+	Title  string                 `json:"title"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// FieldErrorResponse is returned when submitted values do not match a form
+type FieldErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields"`
+}
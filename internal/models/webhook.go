@@ -0,0 +1,121 @@
+package models
+
+// WebhookRepository represents the repository included in webhook payloads.
+// It is kept separate from Repository because push payloads encode
+// timestamps as Unix seconds rather than strings.
+type WebhookRepository struct {
+	ID            int64  `json:"id"`
+	NodeID        string `json:"node_id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Private       bool   `json:"private"`
+	Owner         Owner  `json:"owner"`
+	HTMLURL       string `json:"html_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// WebhookCommit represents a commit included in a push payload
+type WebhookCommit struct {
+	ID        string        `json:"id"`
+	TreeID    string        `json:"tree_id"`
+	Message   string        `json:"message"`
+	Timestamp string        `json:"timestamp"`
+	URL       string        `json:"url"`
+	Author    WebhookAuthor `json:"author"`
+	Committer WebhookAuthor `json:"committer"`
+	Added     []string      `json:"added"`
+	Removed   []string      `json:"removed"`
+	Modified  []string      `json:"modified"`
+	Distinct  bool          `json:"distinct"`
+}
+
+// WebhookAuthor represents a git author or committer in a push payload
+type WebhookAuthor struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// PushEvent represents the payload of a push webhook
+type PushEvent struct {
+	Ref        string            `json:"ref"`
+	Before     string            `json:"before"`
+	After      string            `json:"after"`
+	Created    bool              `json:"created"`
+	Deleted    bool              `json:"deleted"`
+	Forced     bool              `json:"forced"`
+	Compare    string            `json:"compare"`
+	Commits    []WebhookCommit   `json:"commits"`
+	HeadCommit *WebhookCommit    `json:"head_commit"`
+	Pusher     WebhookAuthor     `json:"pusher"`
+	Repository WebhookRepository `json:"repository"`
+	Sender     Owner             `json:"sender"`
+}
+
+// IssuesEvent represents the payload of an issues webhook
+type IssuesEvent struct {
+	Action     string            `json:"action"`
+	Issue      IssueResponse     `json:"issue"`
+	Label      *Label            `json:"label,omitempty"`
+	Repository WebhookRepository `json:"repository"`
+	Sender     Owner             `json:"sender"`
+}
+
+// PullRequest represents a pull request included in webhook payloads
+type PullRequest struct {
+	ID        int64  `json:"id"`
+	Number    int    `json:"number"`
+	State     string `json:"state"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	HTMLURL   string `json:"html_url"`
+	User      Owner  `json:"user"`
+	Draft     bool   `json:"draft"`
+	Merged    bool   `json:"merged"`
+	MergedAt  string `json:"merged_at"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	ClosedAt  string `json:"closed_at"`
+	Head      struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"base"`
+}
+
+// PullRequestEvent represents the payload of a pull_request webhook
+type PullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest PullRequest       `json:"pull_request"`
+	Repository  WebhookRepository `json:"repository"`
+	Sender      Owner             `json:"sender"`
+}
+
+// ReleaseEvent represents the payload of a release webhook
+type ReleaseEvent struct {
+	Action     string            `json:"action"`
+	Release    Release           `json:"release"`
+	Repository WebhookRepository `json:"repository"`
+	Sender     Owner             `json:"sender"`
+}
+
+// WorkflowRunEvent represents the payload of a workflow_run webhook
+type WorkflowRunEvent struct {
+	Action      string            `json:"action"`
+	WorkflowRun WorkflowRun       `json:"workflow_run"`
+	Workflow    Workflow          `json:"workflow"`
+	Repository  WebhookRepository `json:"repository"`
+	Sender      Owner             `json:"sender"`
+}
+
+// PingEvent represents the payload sent when a webhook is created or pinged
+type PingEvent struct {
+	Zen        string             `json:"zen"`
+	HookID     int64              `json:"hook_id"`
+	Repository *WebhookRepository `json:"repository,omitempty"`
+	Sender     Owner              `json:"sender"`
+}
@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule effects
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Anonymous is the principal evaluated when authentication is disabled
+var Anonymous = &auth.Principal{ID: "anonymous", Name: "anonymous"}
+
+// Rule grants or denies operations on repositories to principals. Every
+// list holds glob patterns; an empty list matches anything.
+type Rule struct {
+	Name   string `yaml:"name" json:"name,omitempty"`
+	Effect string `yaml:"effect" json:"effect"`
+	// Principals match the principal's ID, such as key:<key id>,
+	// jwt:<subject> or github:<login>. Names are not matched, as an API key's
+	// name is a free-form label that anyone creating a key can choose.
+	Principals []string `yaml:"principals" json:"principals,omitempty"`
+	// Groups match any of the principal's SSO groups
+	Groups []string `yaml:"groups" json:"groups,omitempty"`
+	// Repos match regardless of case, as GitHub resolves repository names
+	Repos []string `yaml:"repos" json:"repos,omitempty"`
+	// Operations match the scope a route requires, such as read or issues:write
+	Operations []string `yaml:"operations" json:"operations,omitempty"`
+}
+
+// Policy is a declarative access policy
+type Policy struct {
+	// Default is the effect when no rule matches; it defaults to deny
+	Default string `yaml:"default"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Request is an access request to evaluate
+type Request struct {
+	Principal *auth.Principal
+	Repo      string
+	Operation string
+}
+
+// RuleTrace records how one rule was evaluated
+type RuleTrace struct {
+	Rule    string `json:"rule"`
+	Effect  string `json:"effect"`
+	Matched bool   `json:"matched"`
+	// Reason explains why a rule did not match
+	Reason string `json:"reason,omitempty"`
+}
+
+// Decision is the outcome of evaluating a request
+type Decision struct {
+	Allowed   bool   `json:"allowed"`
+	Principal string `json:"principal"`
+	Repo      string `json:"repo"`
+	Operation string `json:"operation"`
+	// Rule names the deciding rule, or "default" when no rule matched
+	Rule   string      `json:"rule"`
+	Reason string      `json:"reason"`
+	Trace  []RuleTrace `json:"trace"`
+}
+
+// Engine evaluates requests against a policy
+type Engine struct {
+	policy Policy
+}
+
+// Load reads a YAML policy file
+func Load(filePath string) (*Engine, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a YAML policy
+func Parse(data []byte) (*Engine, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+
+	if p.Default == "" {
+		p.Default = EffectDeny
+	}
+	if p.Default != EffectAllow && p.Default != EffectDeny {
+		return nil, fmt.Errorf("default must be allow or deny, got %q", p.Default)
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+			return nil, fmt.Errorf("%s: effect must be allow or deny, got %q", rule.Name, rule.Effect)
+		}
+		for j, pattern := range rule.Repos {
+			rule.Repos[j] = strings.ToLower(pattern)
+		}
+		for _, patterns := range [][]string{rule.Principals, rule.Groups, rule.Repos, rule.Operations} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("%s: invalid pattern %q", rule.Name, pattern)
+				}
+			}
+		}
+	}
+
+	return &Engine{policy: p}, nil
+}
+
+// Evaluate decides a request. A matching deny rule always wins over allow
+// rules; when no rule matches the policy default applies. The repository is
+// compared without regard to case, so /github/Secret-Repo is the same
+// repository as secret-repo to the policy just as it is to GitHub.
+func (e *Engine) Evaluate(req Request) Decision {
+	principal := req.Principal
+	if principal == nil {
+		principal = Anonymous
+	}
+
+	decision := Decision{
+		Principal: principal.ID,
+		Repo:      req.Repo,
+		Operation: req.Operation,
+		Trace:     make([]RuleTrace, 0, len(e.policy.Rules)),
+	}
+
+	var allowRule, denyRule string
+	for _, rule := range e.policy.Rules {
+		reason := rule.mismatch(principal, strings.ToLower(req.Repo), req.Operation)
+		decision.Trace = append(decision.Trace, RuleTrace{
+			Rule:    rule.Name,
+			Effect:  rule.Effect,
+			Matched: reason == "",
+			Reason:  reason,
+		})
+		if reason != "" {
+			continue
+		}
+
+		if rule.Effect == EffectDeny && denyRule == "" {
+			denyRule = rule.Name
+		}
+		if rule.Effect == EffectAllow && allowRule == "" {
+			allowRule = rule.Name
+		}
+	}
+
+	switch {
+	case denyRule != "":
+		decision.Rule = denyRule
+		decision.Reason = "denied by " + denyRule
+	case allowRule != "":
+		decision.Allowed = true
+		decision.Rule = allowRule
+		decision.Reason = "allowed by " + allowRule
+	default:
+		decision.Allowed = e.policy.Default == EffectAllow
+		decision.Rule = "default"
+		decision.Reason = "no rule matched, default is " + e.policy.Default
+	}
+
+	return decision
+}
+
+// mismatch returns why the rule does not apply, or "" when it does
+func (r *Rule) mismatch(principal *auth.Principal, repo, operation string) string {
+	if len(r.Principals) > 0 || len(r.Groups) > 0 {
+		if !matchAny(r.Principals, principal.ID) && !matchAny(r.Groups, principal.Groups...) {
+			return "principal does not match"
+		}
+	}
+	if len(r.Repos) > 0 && !matchAny(r.Repos, repo) {
+		return "repository does not match"
+	}
+	if len(r.Operations) > 0 && !matchAny(r.Operations, operation) {
+		return "operation does not match"
+	}
+	return ""
+}
+
+// matchAny reports whether any value matches any pattern
+func matchAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
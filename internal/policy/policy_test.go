@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+rules:
+  - name: platform-everything
+    effect: allow
+    groups: [platform]
+  - name: ci-reads
+    effect: allow
+    principals: ["key:ci-*"]
+    operations: [read]
+  - name: support-issues
+    effect: allow
+    groups: [support]
+    repos: ["service-*"]
+    operations: [read, "issues:*"]
+  - name: freeze-billing
+    effect: deny
+    repos: [billing-*]
+    operations: ["issues:write", admin]
+`
+
+// TestEvaluate tests glob matching, deny overrides and the default decision
+func TestEvaluate(t *testing.T) {
+	engine, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	platform := &auth.Principal{ID: "alice", Groups: []string{"platform"}}
+	support := &auth.Principal{ID: "bob", Groups: []string{"support"}}
+	ci := &auth.Principal{ID: "key:ci-deploy"}
+	impostor := &auth.Principal{ID: "key:k2", Name: "key:ci-deploy"}
+
+	// Test cases
+	tests := []struct {
+		name            string
+		principal       *auth.Principal
+		repo            string
+		operation       string
+		expectedAllowed bool
+		expectedRule    string
+	}{
+		{name: "Group Allowed", principal: platform, repo: "anything", operation: auth.ScopeAdmin, expectedAllowed: true, expectedRule: "platform-everything"},
+		{name: "Deny Overrides Allow", principal: platform, repo: "billing-api", operation: auth.ScopeIssuesWrite, expectedRule: "freeze-billing"},
+		{name: "Deny Ignores Repo Case", principal: platform, repo: "Billing-API", operation: auth.ScopeIssuesWrite, expectedRule: "freeze-billing"},
+		{name: "Deny Only Listed Operations", principal: platform, repo: "billing-api", operation: auth.ScopeRead, expectedAllowed: true, expectedRule: "platform-everything"},
+		{name: "Principal Glob", principal: ci, repo: "web", operation: auth.ScopeRead, expectedAllowed: true, expectedRule: "ci-reads"},
+		{name: "Name Not Matched", principal: impostor, repo: "web", operation: auth.ScopeRead, expectedRule: "default"},
+		{name: "Operation Not Granted", principal: ci, repo: "web", operation: auth.ScopeIssuesWrite, expectedRule: "default"},
+		{name: "Repo Glob", principal: support, repo: "service-api", operation: auth.ScopeIssuesWrite, expectedAllowed: true, expectedRule: "support-issues"},
+		{name: "Repo Outside Glob", principal: support, repo: "web", operation: auth.ScopeRead, expectedRule: "default"},
+		{name: "Anonymous", repo: "web", operation: auth.ScopeRead, expectedRule: "default"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := engine.Evaluate(Request{Principal: tc.principal, Repo: tc.repo, Operation: tc.operation})
+
+			assert.Equal(t, tc.expectedAllowed, decision.Allowed)
+			assert.Equal(t, tc.expectedRule, decision.Rule)
+			assert.Len(t, decision.Trace, 4)
+		})
+	}
+
+	decision := engine.Evaluate(Request{Principal: support, Repo: "web", Operation: auth.ScopeRead})
+	assert.Equal(t, "principal does not match", decision.Trace[0].Reason)
+	assert.Equal(t, "repository does not match", decision.Trace[2].Reason)
+	assert.Equal(t, "anonymous", engine.Evaluate(Request{}).Principal)
+}
+
+// TestParse tests policy validation
+func TestParse(t *testing.T) {
+	engine, err := Parse([]byte("default: allow\n"))
+	require.NoError(t, err)
+	assert.True(t, engine.Evaluate(Request{Repo: "web", Operation: auth.ScopeRead}).Allowed)
+
+	// Test cases
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{name: "Unknown Default", policy: "default: maybe\n"},
+		{name: "Unknown Effect", policy: "rules:\n  - effect: permit\n"},
+		{name: "Bad Pattern", policy: "rules:\n  - effect: allow\n    repos: [\"[\"]\n"},
+		{name: "Invalid YAML", policy: "rules: {"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.policy))
+			assert.Error(t, err)
+		})
+	}
+}
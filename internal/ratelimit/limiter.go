@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have refilled are forgotten
+const sweepInterval = time.Minute
+
+// Result describes a rate limit decision in terms of the RateLimit headers
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the limit is fully available again
+	Reset time.Duration
+	// RetryAfter is how long a rejected caller should wait
+	RetryAfter time.Duration
+}
+
+// RouteCosts maps "METHOD /route/pattern" to the tokens a request costs.
+// Routes that are not listed cost one token; a cost of zero exempts a route.
+type RouteCosts map[string]int
+
+// Lookup returns the cost of a route
+func (c RouteCosts) Lookup(method, route string) int {
+	if cost, ok := c[method+" "+route]; ok {
+		return cost
+	}
+	return 1
+}
+
+// Max returns the highest cost of any route, which a limiter's burst must
+// cover for that route to ever be allowed
+func (c RouteCosts) Max() int {
+	highest := 1
+	for _, cost := range c {
+		if cost > highest {
+			highest = cost
+		}
+	}
+	return highest
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter is a token bucket per key. Each bucket holds up to burst tokens and
+// refills at rate tokens per second.
+type Limiter struct {
+	rate  float64
+	burst int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewLimiter creates a Limiter allowing perMinute requests a minute on
+// average, in bursts of up to burst
+func NewLimiter(perMinute, burst int) *Limiter {
+	return &Limiter{
+		rate:      float64(perMinute) / 60,
+		burst:     burst,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		nowFunc:   time.Now,
+	}
+}
+
+// Rate returns how many tokens the buckets refill per second
+func (l *Limiter) Rate() float64 {
+	return l.rate
+}
+
+// Allow takes cost tokens from key's bucket if it holds enough
+func (l *Limiter) Allow(key string, cost int) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.nowFunc()
+	l.sweepLocked(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	result := Result{Limit: l.burst}
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.wait(float64(cost) - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = l.wait(float64(l.burst) - b.tokens)
+	return result
+}
+
+// wait returns how long the bucket takes to refill tokens
+func (l *Limiter) wait(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / l.rate * float64(time.Second)))
+}
+
+// sweepLocked forgets buckets that are full again, as they are identical to
+// new ones; the caller must hold l.mu
+func (l *Limiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// quotaFlushInterval is how often changed usage is written to disk. Usage
+// since the last flush is lost if the process crashes.
+const quotaFlushInterval = 10 * time.Second
+
+// usage is the cost a key has spent on one UTC day
+type usage struct {
+	Day  string `json:"day"`
+	Used int    `json:"used"`
+}
+
+// QuotaStore enforces a daily quota per key, persisting usage so that a
+// restart does not reset it. Quotas reset at midnight UTC.
+type QuotaStore struct {
+	store *storage.JSONFile
+	daily int
+
+	mu    sync.Mutex
+	usage map[string]*usage
+	dirty bool
+	stop  chan struct{}
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewQuotaStore creates a QuotaStore persisted at path, loading any existing usage
+func NewQuotaStore(path string, daily int) (*QuotaStore, error) {
+	store, err := storage.NewJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &QuotaStore{
+		store:   store,
+		daily:   daily,
+		usage:   make(map[string]*usage),
+		stop:    make(chan struct{}),
+		nowFunc: time.Now,
+	}
+	if err := store.Load(&q.usage); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Start begins writing changed usage to disk periodically
+func (q *QuotaStore) Start() {
+	go func() {
+		ticker := time.NewTicker(quotaFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := q.Flush(); err != nil {
+					logrus.WithError(err).Error("Failed to persist rate limit quotas")
+				}
+			case <-q.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic writes and writes any remaining changes
+func (q *QuotaStore) Stop() error {
+	close(q.stop)
+	return q.Flush()
+}
+
+// Use spends cost from key's quota for today if enough is left
+func (q *QuotaStore) Use(key string, cost int) Result {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.nowFunc().UTC()
+	day := now.Format("2006-01-02")
+	u, ok := q.usage[key]
+	if !ok || u.Day != day {
+		u = &usage{Day: day}
+		q.usage[key] = u
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	result := Result{Limit: q.daily, Reset: midnight.Sub(now)}
+	if u.Used+cost <= q.daily {
+		u.Used += cost
+		q.dirty = true
+		result.Allowed = true
+	} else {
+		result.RetryAfter = result.Reset
+	}
+	result.Remaining = q.daily - u.Used
+	return result
+}
+
+// Flush writes usage to disk if it changed, dropping keys from earlier days
+func (q *QuotaStore) Flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.dirty {
+		return nil
+	}
+
+	day := q.nowFunc().UTC().Format("2006-01-02")
+	for key, u := range q.usage {
+		if u.Day != day {
+			delete(q.usage, key)
+		}
+	}
+	if err := q.store.Save(q.usage); err != nil {
+		return err
+	}
+	q.dirty = false
+	return nil
+}
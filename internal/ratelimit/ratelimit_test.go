@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLimiter tests bursts, costs, refill and per-key buckets
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(60, 10)
+	now := time.Now()
+	limiter.nowFunc = func() time.Time { return now }
+
+	result := limiter.Allow("a", 8)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, 8*time.Second, result.Reset)
+
+	// A request costing more than is left is rejected without spending tokens
+	result = limiter.Allow("a", 5)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, 3*time.Second, result.RetryAfter)
+
+	assert.True(t, limiter.Allow("b", 10).Allowed)
+
+	now = now.Add(3 * time.Second)
+	assert.True(t, limiter.Allow("a", 5).Allowed)
+
+	// Full buckets are forgotten by the sweep
+	now = now.Add(time.Hour)
+	limiter.Allow("c", 1)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+// TestRouteCosts tests the default cost of unlisted routes and the highest cost
+func TestRouteCosts(t *testing.T) {
+	costs := RouteCosts{"POST /github/:repo/issues": 10, "GET /health": 0}
+
+	assert.Equal(t, 10, costs.Lookup("POST", "/github/:repo/issues"))
+	assert.Equal(t, 0, costs.Lookup("GET", "/health"))
+	assert.Equal(t, 1, costs.Lookup("GET", "/github/:repo"))
+	assert.Equal(t, 10, costs.Max())
+	assert.Equal(t, 1, RouteCosts{"GET /health": 0}.Max())
+}
+
+// TestQuotaStore tests daily quotas, their reset at midnight UTC and persistence
+func TestQuotaStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotas.json")
+	quotas, err := NewQuotaStore(path, 20)
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)
+	quotas.nowFunc = func() time.Time { return now }
+
+	result := quotas.Use("a", 15)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 5, result.Remaining)
+	assert.Equal(t, time.Hour, result.Reset)
+
+	result = quotas.Use("a", 10)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Hour, result.RetryAfter)
+	require.NoError(t, quotas.Flush())
+
+	// Usage survives a restart
+	reloaded, err := NewQuotaStore(path, 20)
+	require.NoError(t, err)
+	reloaded.nowFunc = quotas.nowFunc
+	assert.False(t, reloaded.Use("a", 10).Allowed)
+	assert.True(t, reloaded.Use("b", 10).Allowed)
+
+	now = now.Add(2 * time.Hour)
+	result = reloaded.Use("a", 10)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 10, result.Remaining)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ListWorkflows retrieves the workflows defined in a repository
+func (s *GitHubService) ListWorkflows(repoName string) ([]models.Workflow, error) {
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/actions/workflows?per_page=100", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.WorkflowList
+	if err := s.do(req, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Workflows, nil
+}
+
+// ListWorkflowRuns retrieves workflow runs, optionally narrowed to a single workflow
+func (s *GitHubService) ListWorkflowRuns(repoName string, filter *models.WorkflowRunFilter) (*models.WorkflowRunList, error) {
+	path := s.repoPath(repoName) + "/actions/runs"
+	query := url.Values{}
+	if filter != nil {
+		if filter.Workflow != "" {
+			path = fmt.Sprintf("%s/actions/workflows/%s/runs", s.repoPath(repoName), url.PathEscape(filter.Workflow))
+		}
+		if filter.Branch != "" {
+			query.Set("branch", filter.Branch)
+		}
+		if filter.Status != "" {
+			query.Set("status", filter.Status)
+		}
+		if filter.Event != "" {
+			query.Set("event", filter.Event)
+		}
+		if filter.Page > 0 {
+			query.Set("page", strconv.Itoa(filter.Page))
+		}
+		if filter.PerPage > 0 {
+			query.Set("per_page", strconv.Itoa(filter.PerPage))
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := s.newRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.WorkflowRunList
+	if err := s.do(req, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// ListWorkflowRunJobs retrieves the jobs and steps of the latest attempt of a workflow run
+func (s *GitHubService) ListWorkflowRunJobs(repoName string, runID int64) ([]models.WorkflowJob, error) {
+	path := fmt.Sprintf("%s/actions/runs/%d/jobs?per_page=100", s.repoPath(repoName), runID)
+	req, err := s.newRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.WorkflowJobList
+	if err := s.do(req, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Jobs, nil
+}
+
+// DownloadWorkflowRunLogs streams the zip archive of a workflow run's logs.
+// The caller is responsible for closing the returned reader.
+func (s *GitHubService) DownloadWorkflowRunLogs(repoName string, runID int64) (io.ReadCloser, error) {
+	path := fmt.Sprintf("%s/actions/runs/%d/logs", s.repoPath(repoName), runID)
+	req, err := s.newRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub redirects to a short-lived archive URL; the client drops the
+	// Authorization header when following the redirect to another host.
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		logrus.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"response":    string(body),
+		}).Error("GitHub API error")
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return resp.Body, nil
+}
+
+// RerunFailedJobs re-runs the failed jobs of a workflow run
+func (s *GitHubService) RerunFailedJobs(repoName string, runID int64) error {
+	path := fmt.Sprintf("%s/actions/runs/%d/rerun-failed-jobs", s.repoPath(repoName), runID)
+	req, err := s.newRequest("POST", path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusCreated, nil)
+}
+
+// CancelWorkflowRun cancels a queued or in-progress workflow run
+func (s *GitHubService) CancelWorkflowRun(repoName string, runID int64) error {
+	path := fmt.Sprintf("%s/actions/runs/%d/cancel", s.repoPath(repoName), runID)
+	req, err := s.newRequest("POST", path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusAccepted, nil)
+}
+
+// DispatchWorkflow triggers a workflow_dispatch event for a workflow ID or file name
+func (s *GitHubService) DispatchWorkflow(repoName string, workflow string, dispatch *models.WorkflowDispatchRequest) error {
+	path := fmt.Sprintf("%s/actions/workflows/%s/dispatches", s.repoPath(repoName), url.PathEscape(workflow))
+	req, err := s.newRequest("POST", path, dispatch)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, nil)
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// appJWTLifetime is how long app JWTs are valid; GitHub allows at most ten minutes
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates app JWTs in case GitHub's clock is behind ours
+	appJWTClockSkew = time.Minute
+	// installationTokenMargin refreshes installation tokens this long before they expire
+	installationTokenMargin = 5 * time.Minute
+)
+
+// installationToken is a cached installation access token
+type installationToken struct {
+	token       string
+	expiresAt   time.Time
+	permissions map[string]string
+}
+
+// AppTokenSource authenticates as a GitHub App, exchanging a JWT signed with
+// the app's private key for an installation token per owner. Tokens are
+// cached and refreshed shortly before they expire.
+type AppTokenSource struct {
+	appID          int64
+	key            *rsa.PrivateKey
+	installationID int64
+	baseURL        string
+	client         *http.Client
+
+	mu            sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]installationToken
+
+	// lookups and refreshes share the requests in flight for an owner or
+	// installation, which are sent without holding mu
+	lookups   singleflight.Group
+	refreshes singleflight.Group
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewAppTokenSource creates an AppTokenSource from the app's PEM private key.
+// A non-zero installationID is used for every owner instead of looking up
+// each owner's installation.
+func NewAppTokenSource(appID int64, privateKeyPath string, installationID int64) (*AppTokenSource, error) {
+	data, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+
+	return &AppTokenSource{
+		appID:          appID,
+		key:            key,
+		installationID: installationID,
+		baseURL:        githubAPIURL,
+		client:         &http.Client{Timeout: 30 * time.Second},
+		installations:  make(map[string]int64),
+		tokens:         make(map[int64]installationToken),
+		nowFunc:        time.Now,
+	}, nil
+}
+
+// Token implements TokenSource. Concurrent callers for an installation wait
+// for a single refresh, without blocking callers for other installations.
+func (a *AppTokenSource) Token(owner string) (string, error) {
+	installationID, err := a.installation(owner)
+	if err != nil {
+		return "", err
+	}
+	if token, ok := a.cachedToken(installationID); ok {
+		return token.token, nil
+	}
+
+	refreshed, err, _ := a.refreshes.Do(strconv.FormatInt(installationID, 10), func() (interface{}, error) {
+		// A caller that was waiting on an earlier refresh may find it done
+		if token, ok := a.cachedToken(installationID); ok {
+			return token, nil
+		}
+
+		token, err := a.createInstallationToken(installationID)
+		if err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		a.tokens[installationID] = token
+		a.mu.Unlock()
+
+		logrus.WithFields(logrus.Fields{
+			"installation_id": installationID,
+			"expires_at":      token.expiresAt,
+		}).Info("Refreshed GitHub App installation token")
+		return token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return refreshed.(installationToken).token, nil
+}
+
+// Permissions implements PermissionSource, returning the permissions granted
+// to the owner's installation
+func (a *AppTokenSource) Permissions(owner string) (map[string]string, error) {
+	if _, err := a.Token(owner); err != nil {
+		return nil, err
+	}
+
+	installationID, err := a.installation(owner)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.tokens[installationID].permissions, nil
+}
+
+// cachedToken returns the installation's token unless it is missing or about to expire
+func (a *AppTokenSource) cachedToken(installationID int64) (installationToken, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	cached, ok := a.tokens[installationID]
+	if !ok || !a.nowFunc().Add(installationTokenMargin).Before(cached.expiresAt) {
+		return installationToken{}, false
+	}
+	return cached, true
+}
+
+// installation returns the installation ID for owner, looking it up once
+func (a *AppTokenSource) installation(owner string) (int64, error) {
+	if a.installationID != 0 {
+		return a.installationID, nil
+	}
+	a.mu.Lock()
+	id, ok := a.installations[owner]
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	found, err, _ := a.lookups.Do(owner, func() (interface{}, error) {
+		// Owners are either users or organizations
+		var installation models.Installation
+		err := a.appRequest("GET", "/users/"+owner+"/installation", http.StatusOK, &installation)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			err = a.appRequest("GET", "/orgs/"+owner+"/installation", http.StatusOK, &installation)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find GitHub App installation for %s: %w", owner, err)
+		}
+
+		a.mu.Lock()
+		a.installations[owner] = installation.ID
+		a.mu.Unlock()
+		return installation.ID, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return found.(int64), nil
+}
+
+// createInstallationToken exchanges the app JWT for an installation token
+func (a *AppTokenSource) createInstallationToken(installationID int64) (installationToken, error) {
+	var response models.InstallationToken
+	path := "/app/installations/" + strconv.FormatInt(installationID, 10) + "/access_tokens"
+	if err := a.appRequest("POST", path, http.StatusCreated, &response); err != nil {
+		return installationToken{}, fmt.Errorf("failed to create installation token: %w", err)
+	}
+
+	return installationToken{token: response.Token, expiresAt: response.ExpiresAt, permissions: response.Permissions}, nil
+}
+
+// appRequest sends a request authenticated as the app itself
+func (a *AppTokenSource) appRequest(method, path string, expectedStatus int, out interface{}) error {
+	signed, err := a.appJWT()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, a.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+signed)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	// Reuse the service's response handling; the app has no token of its own
+	return (&GitHubService{client: a.client}).do(req, expectedStatus, out)
+}
+
+// appJWT signs a short-lived JWT identifying the app
+func (a *AppTokenSource) appJWT() (string, error) {
+	now := a.nowFunc()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    strconv.FormatInt(a.appID, 10),
+		IssuedAt:  jwt.NewNumericDate(now.Add(-appJWTClockSkew)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appJWTLifetime)),
+	})
+
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAppKey writes a new RSA private key as PEM and returns it with its path
+func writeAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return key, path
+}
+
+// TestAppTokenSource tests installation lookup, token caching and refresh before expiry
+func TestAppTokenSource(t *testing.T) {
+	key, path := writeAppKey(t)
+	source, err := NewAppTokenSource(12345, path, 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	source.nowFunc = func() time.Time { return now }
+
+	var requests []string
+	created := 0
+	source.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+
+			// Every request is authenticated with a JWT issued by the app
+			signed := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			claims := jwt.RegisteredClaims{}
+			_, err := jwt.ParseWithClaims(signed, &claims, func(*jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			}, jwt.WithTimeFunc(func() time.Time { return now }))
+			assert.NoError(t, err)
+			assert.Equal(t, "12345", claims.Issuer)
+
+			status, body := http.StatusNotFound, `{"message": "Not Found"}`
+			switch req.URL.Path {
+			case "/orgs/test-org/installation":
+				status, body = http.StatusOK, `{"id": 42, "account": {"login": "test-org"}}`
+			case "/app/installations/42/access_tokens":
+				created++
+				expiresAt := now.Add(time.Hour).UTC().Format(time.RFC3339)
+				status, body = http.StatusCreated, fmt.Sprintf(`{"token": "ghs_%d", "expires_at": %q}`, created, expiresAt)
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	token, err := source.Token("test-org")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+
+	// The cached token is reused until shortly before it expires
+	now = now.Add(50 * time.Minute)
+	token, err = source.Token("test-org")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+
+	now = now.Add(6 * time.Minute)
+	token, err = source.Token("test-org")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_2", token)
+
+	assert.Equal(t, []string{
+		"GET /users/test-org/installation",
+		"GET /orgs/test-org/installation",
+		"POST /app/installations/42/access_tokens",
+		"POST /app/installations/42/access_tokens",
+	}, requests)
+
+	_, err = source.Token("unknown-owner")
+	assert.ErrorContains(t, err, "failed to find GitHub App installation for unknown-owner")
+}
+
+// TestAppTokenSourceConcurrent tests that a slow refresh is shared by its
+// installation's callers and does not block other installations
+func TestAppTokenSourceConcurrent(t *testing.T) {
+	_, path := writeAppKey(t)
+	source, err := NewAppTokenSource(12345, path, 0)
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	source.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requests[req.Method+" "+req.URL.Path]++
+			mu.Unlock()
+
+			status, body := http.StatusNotFound, `{"message": "Not Found"}`
+			expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			switch req.URL.Path {
+			case "/users/slow-user/installation":
+				status, body = http.StatusOK, `{"id": 1}`
+			case "/users/fast-user/installation":
+				status, body = http.StatusOK, `{"id": 2}`
+			case "/app/installations/1/access_tokens":
+				<-release
+				status, body = http.StatusCreated, fmt.Sprintf(`{"token": "ghs_slow", "expires_at": %q}`, expiresAt)
+			case "/app/installations/2/access_tokens":
+				status, body = http.StatusCreated, fmt.Sprintf(`{"token": "ghs_fast", "expires_at": %q}`, expiresAt)
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = source.Token("slow-user")
+		}(i)
+	}
+
+	// Other installations are served while the slow refresh is in flight
+	token, err := source.Token("fast-user")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_fast", token)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"ghs_slow", "ghs_slow", "ghs_slow", "ghs_slow", "ghs_slow"}, tokens)
+	assert.Equal(t, 1, requests["GET /users/slow-user/installation"])
+	assert.Equal(t, 1, requests["POST /app/installations/1/access_tokens"])
+}
+
+// TestNewTokenSource tests that the configuration selects the token source
+func TestNewTokenSource(t *testing.T) {
+	source, err := NewTokenSource(config.GitHubConfig{Token: "test-token"})
+	require.NoError(t, err)
+	token, err := source.Token("test-user")
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", token)
+
+	_, err = NewTokenSource(config.GitHubConfig{AppID: 1, AppPrivateKeyPath: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+
+	_, path := writeAppKey(t)
+	source, err = NewTokenSource(config.GitHubConfig{AppID: 1, AppPrivateKeyPath: path, AppInstallationID: 7})
+	require.NoError(t, err)
+	assert.IsType(t, &AppTokenSource{}, source)
+}
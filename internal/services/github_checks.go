@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// maxAnnotationsPerRequest is the number of annotations GitHub accepts per check run request
+const maxAnnotationsPerRequest = 50
+
+// CreateCommitStatus creates a commit status for a SHA. Posting a status with
+// an existing context replaces the previous status for that context.
+func (s *GitHubService) CreateCommitStatus(repoName string, sha string, status *models.CommitStatusRequest) (*models.CommitStatus, error) {
+	req, err := s.newRequest("POST", fmt.Sprintf("%s/statuses/%s", s.repoPath(repoName), sha), status)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.CommitStatus
+	if err := s.do(req, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateCheckRun creates a check run, sending annotations beyond the first
+// batch in follow-up updates
+func (s *GitHubService) CreateCheckRun(repoName string, checkRun *models.CheckRunRequest) (*models.CheckRun, error) {
+	first, batches := splitAnnotations(checkRun)
+
+	req, err := s.newRequest("POST", s.repoPath(repoName)+"/check-runs", first)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.CheckRun
+	if err := s.do(req, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+
+	return s.appendAnnotations(repoName, &result, checkRun.Output, batches)
+}
+
+// UpdateCheckRun updates a check run, sending annotations beyond the first
+// batch in follow-up updates
+func (s *GitHubService) UpdateCheckRun(repoName string, checkRunID int64, checkRun *models.CheckRunRequest) (*models.CheckRun, error) {
+	first, batches := splitAnnotations(checkRun)
+
+	var result models.CheckRun
+	if err := s.patchCheckRun(repoName, checkRunID, first, &result); err != nil {
+		return nil, err
+	}
+
+	return s.appendAnnotations(repoName, &result, checkRun.Output, batches)
+}
+
+// appendAnnotations sends the remaining annotation batches of a check run,
+// returning the check run as of the last update
+func (s *GitHubService) appendAnnotations(repoName string, checkRun *models.CheckRun, output *models.CheckRunOutput, batches [][]models.CheckRunAnnotation) (*models.CheckRun, error) {
+	for i, batch := range batches {
+		update := &models.CheckRunRequest{
+			Output: &models.CheckRunOutput{
+				Title:       output.Title,
+				Summary:     output.Summary,
+				Annotations: batch,
+			},
+		}
+
+		var result models.CheckRun
+		if err := s.patchCheckRun(repoName, checkRun.ID, update, &result); err != nil {
+			return nil, fmt.Errorf("failed to send annotation batch %d of %d: %w", i+2, len(batches)+1, err)
+		}
+		checkRun = &result
+	}
+
+	return checkRun, nil
+}
+
+// patchCheckRun sends a single check run update
+func (s *GitHubService) patchCheckRun(repoName string, checkRunID int64, update *models.CheckRunRequest, out *models.CheckRun) error {
+	req, err := s.newRequest("PATCH", fmt.Sprintf("%s/check-runs/%d", s.repoPath(repoName), checkRunID), update)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusOK, out)
+}
+
+// splitAnnotations returns a copy of the request carrying at most one batch
+// of annotations, along with the batches that remain to be sent
+func splitAnnotations(checkRun *models.CheckRunRequest) (*models.CheckRunRequest, [][]models.CheckRunAnnotation) {
+	if checkRun.Output == nil || len(checkRun.Output.Annotations) <= maxAnnotationsPerRequest {
+		return checkRun, nil
+	}
+
+	annotations := checkRun.Output.Annotations
+	output := *checkRun.Output
+	output.Annotations = annotations[:maxAnnotationsPerRequest]
+	first := *checkRun
+	first.Output = &output
+
+	var batches [][]models.CheckRunAnnotation
+	for start := maxAnnotationsPerRequest; start < len(annotations); start += maxAnnotationsPerRequest {
+		end := start + maxAnnotationsPerRequest
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		batches = append(batches, annotations[start:end])
+	}
+
+	return &first, batches
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateCheckRunBatchesAnnotations tests that annotations are sent in batches of 50
+func TestCreateCheckRunBatchesAnnotations(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var methods []string
+	var batchSizes []int
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			var sent models.CheckRunRequest
+			_ = json.NewDecoder(req.Body).Decode(&sent)
+			methods = append(methods, req.Method+" "+req.URL.Path)
+			batchSizes = append(batchSizes, len(sent.Output.Annotations))
+
+			statusCode := http.StatusOK
+			if req.Method == "POST" {
+				statusCode = http.StatusCreated
+			}
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       io.NopCloser(strings.NewReader(`{"id": 7, "name": "lint"}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	annotations := make([]models.CheckRunAnnotation, 120)
+	for i := range annotations {
+		annotations[i] = models.CheckRunAnnotation{Path: "main.go", StartLine: i + 1, EndLine: i + 1, AnnotationLevel: "warning", Message: "lint"}
+	}
+
+	result, err := service.CreateCheckRun("test-repo", &models.CheckRunRequest{
+		Name:    "lint",
+		HeadSHA: "abc123",
+		Status:  "completed",
+		Output: &models.CheckRunOutput{
+			Title:       "Lint",
+			Summary:     "120 warnings",
+			Annotations: annotations,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+	assert.Equal(t, []string{
+		"POST /repos/test-user/test-repo/check-runs",
+		"PATCH /repos/test-user/test-repo/check-runs/7",
+		"PATCH /repos/test-user/test-repo/check-runs/7",
+	}, methods)
+	assert.Equal(t, []int{50, 50, 20}, batchSizes)
+}
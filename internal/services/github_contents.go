@@ -0,0 +1,47 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// ListDirectory retrieves the entries of a directory in a repository's default branch
+func (s *GitHubService) ListDirectory(repoName string, path string) ([]models.ContentEntry, error) {
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/contents/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.ContentEntry
+	if err := s.do(req, http.StatusOK, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetFileContents retrieves a file from a repository's default branch
+func (s *GitHubService) GetFileContents(repoName string, path string) ([]byte, error) {
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/contents/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry models.ContentEntry
+	if err := s.do(req, http.StatusOK, &entry); err != nil {
+		return nil, err
+	}
+
+	if entry.Type != "file" || entry.Encoding != "base64" {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+	content, err := base64.StdEncoding.DecodeString(entry.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetFileContents tests that file contents are decoded and directories are refused
+func TestGetFileContents(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var paths []string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path)
+			body := `{"type": "file", "encoding": "base64", "content": "bmFtZTog\nQnVnIHJlcG9ydA==\n"}`
+			if strings.HasSuffix(req.URL.Path, "/ISSUE_TEMPLATE") {
+				body = `[{"name": "bug.yml", "type": "file"}]`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	content, err := service.GetFileContents("test-repo", ".github/ISSUE_TEMPLATE/bug.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "name: Bug report", string(content))
+
+	entries, err := service.ListDirectory("test-repo", ".github/ISSUE_TEMPLATE")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bug.yml", entries[0].Name)
+
+	assert.Equal(t, []string{
+		"/repos/test-user/test-repo/contents/.github/ISSUE_TEMPLATE/bug.yml",
+		"/repos/test-user/test-repo/contents/.github/ISSUE_TEMPLATE",
+	}, paths)
+}
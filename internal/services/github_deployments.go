@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// DeploymentMergedError is returned when GitHub merged the default branch
+// into the ref instead of creating a deployment. The ref can be deployed
+// again once the merge is done.
+type DeploymentMergedError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *DeploymentMergedError) Error() string {
+	return "GitHub merged the default branch instead of deploying: " + e.Message
+}
+
+// CreateDeployment creates a deployment for a ref. When auto-merge is on and
+// the ref is behind the default branch, GitHub merges the branch instead and
+// a *DeploymentMergedError is returned.
+func (s *GitHubService) CreateDeployment(repoName string, deployment *models.DeploymentRequest) (*models.Deployment, error) {
+	req, err := s.newRequest("POST", s.repoPath(repoName)+"/deployments", deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		var result models.Deployment
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return &result, nil
+	case http.StatusAccepted:
+		var merged struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&merged); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil, &DeploymentMergedError{Message: merged.Message}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		logrus.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"response":    string(body),
+		}).Error("GitHub API error")
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
+
+// ListDeployments retrieves the deployments of a repository matching the filter
+func (s *GitHubService) ListDeployments(repoName string, filter *models.DeploymentFilter) ([]models.Deployment, error) {
+	query := url.Values{}
+	query.Set("per_page", "100")
+	if filter != nil {
+		if filter.SHA != "" {
+			query.Set("sha", filter.SHA)
+		}
+		if filter.Ref != "" {
+			query.Set("ref", filter.Ref)
+		}
+		if filter.Task != "" {
+			query.Set("task", filter.Task)
+		}
+		if filter.Environment != "" {
+			query.Set("environment", filter.Environment)
+		}
+	}
+
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/deployments?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []models.Deployment
+	if err := s.do(req, http.StatusOK, &deployments); err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+// CreateDeploymentStatus creates a status for a deployment
+func (s *GitHubService) CreateDeploymentStatus(repoName string, deploymentID int64, status *models.DeploymentStatusRequest) (*models.DeploymentStatus, error) {
+	path := fmt.Sprintf("%s/deployments/%d/statuses", s.repoPath(repoName), deploymentID)
+	req, err := s.newRequest("POST", path, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.DeploymentStatus
+	if err := s.do(req, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListDeploymentStatuses retrieves the statuses of a deployment, newest first
+func (s *GitHubService) ListDeploymentStatuses(repoName string, deploymentID int64) ([]models.DeploymentStatus, error) {
+	path := fmt.Sprintf("%s/deployments/%d/statuses?per_page=100", s.repoPath(repoName), deploymentID)
+	req, err := s.newRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []models.DeploymentStatus
+	if err := s.do(req, http.StatusOK, &statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// CompleteDeployment records a deployment as in_progress and then moves it to
+// the requested final state, returning both statuses in order
+func (s *GitHubService) CompleteDeployment(repoName string, deploymentID int64, completion *models.DeploymentCompletionRequest) ([]models.DeploymentStatus, error) {
+	inProgress, err := s.CreateDeploymentStatus(repoName, deploymentID, &models.DeploymentStatusRequest{
+		State:       "in_progress",
+		LogURL:      completion.LogURL,
+		Description: completion.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark deployment in progress: %w", err)
+	}
+
+	final, err := s.CreateDeploymentStatus(repoName, deploymentID, &models.DeploymentStatusRequest{
+		State:          completion.State,
+		LogURL:         completion.LogURL,
+		EnvironmentURL: completion.EnvironmentURL,
+		Description:    completion.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark deployment %s: %w", completion.State, err)
+	}
+
+	return []models.DeploymentStatus{*inProgress, *final}, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateDeploymentMerged tests that GitHub's 202 for an auto-merge is reported with its message
+func TestCreateDeploymentMerged(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Token: "test-token", Username: "test-user"}}
+	service := NewGitHubService(cfg).(*GitHubService)
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusAccepted,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "Auto-merged main into topic on deployment."}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	deployment, err := service.CreateDeployment("test-repo", &models.DeploymentRequest{Ref: "topic"})
+	assert.Nil(t, deployment)
+	var merged *DeploymentMergedError
+	if assert.ErrorAs(t, err, &merged) {
+		assert.Equal(t, "Auto-merged main into topic on deployment.", merged.Message)
+	}
+}
+
+// TestCompleteDeployment tests that a deployment moves through in_progress to its final state
+func TestCompleteDeployment(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	// Test cases
+	tests := []struct {
+		name           string
+		failOnState    string
+		expectedStates []string
+		expectedError  bool
+	}{
+		{
+			name:           "Success",
+			expectedStates: []string{"in_progress", "success"},
+		},
+		{
+			name:           "In Progress Rejected",
+			failOnState:    "in_progress",
+			expectedStates: []string{"in_progress"},
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewGitHubService(cfg).(*GitHubService)
+
+			var states []string
+			service.client.Transport = &mockTransport{
+				mockResponse: func(req *http.Request) (*http.Response, error) {
+					var sent models.DeploymentStatusRequest
+					_ = json.NewDecoder(req.Body).Decode(&sent)
+					states = append(states, sent.State)
+
+					assert.Equal(t, "/repos/test-user/test-repo/deployments/9/statuses", req.URL.Path)
+
+					statusCode := http.StatusCreated
+					if sent.State == tc.failOnState {
+						statusCode = http.StatusUnprocessableEntity
+					}
+					return &http.Response{
+						StatusCode: statusCode,
+						Body:       io.NopCloser(strings.NewReader(`{"id": 1, "state": "` + sent.State + `"}`)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			}
+
+			statuses, err := service.CompleteDeployment("test-repo", 9, &models.DeploymentCompletionRequest{State: "success"})
+
+			assert.Equal(t, tc.expectedStates, states)
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, statuses)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, statuses, 2)
+				assert.Equal(t, "success", statuses[1].State)
+			}
+		})
+	}
+}
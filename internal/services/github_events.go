@@ -0,0 +1,57 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ListRepositoryEvents retrieves a repository's recent events. When etag
+// matches the current events GitHub answers 304, which does not count
+// against the rate limit, and the page is returned with NotModified set.
+func (s *GitHubService) ListRepositoryEvents(repoName string, etag string) (*models.EventPage, error) {
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/events?per_page=100", nil)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	page := &models.EventPage{ETag: resp.Header.Get("ETag")}
+	if interval, err := strconv.Atoi(resp.Header.Get("X-Poll-Interval")); err == nil {
+		page.PollInterval = interval
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		if page.ETag == "" {
+			page.ETag = etag
+		}
+		page.NotModified = true
+		return page, nil
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(&page.Events); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return page, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		logrus.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"response":    string(body),
+		}).Error("GitHub API error")
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListRepositoryEvents tests conditional reads of the repository events API
+func TestListRepositoryEvents(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	// Test cases
+	tests := []struct {
+		name                string
+		etag                string
+		statusCode          int
+		responseBody        string
+		expectedEvents      int
+		expectedETag        string
+		expectedNotModified bool
+		expectedError       bool
+	}{
+		{
+			name:           "Fresh Read",
+			statusCode:     http.StatusOK,
+			responseBody:   `[{"id": "2", "type": "PushEvent"}, {"id": "1", "type": "IssuesEvent"}]`,
+			expectedEvents: 2,
+			expectedETag:   `"v2"`,
+		},
+		{
+			name:                "Not Modified",
+			etag:                `"v2"`,
+			statusCode:          http.StatusNotModified,
+			expectedETag:        `"v2"`,
+			expectedNotModified: true,
+		},
+		{
+			name:          "Upstream Error",
+			statusCode:    http.StatusNotFound,
+			responseBody:  `{"message": "Not Found"}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewGitHubService(cfg).(*GitHubService)
+
+			service.client.Transport = &mockTransport{
+				mockResponse: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/repos/test-user/test-repo/events", req.URL.Path)
+					assert.Equal(t, tc.etag, req.Header.Get("If-None-Match"))
+
+					header := make(http.Header)
+					header.Set("X-Poll-Interval", "60")
+					if tc.statusCode == http.StatusOK {
+						header.Set("ETag", `"v2"`)
+					}
+					return &http.Response{
+						StatusCode: tc.statusCode,
+						Header:     header,
+						Body:       io.NopCloser(strings.NewReader(tc.responseBody)),
+					}, nil
+				},
+			}
+
+			page, err := service.ListRepositoryEvents("test-repo", tc.etag)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, page)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, page.Events, tc.expectedEvents)
+			assert.Equal(t, tc.expectedETag, page.ETag)
+			assert.Equal(t, tc.expectedNotModified, page.NotModified)
+			assert.Equal(t, 60, page.PollInterval)
+		})
+	}
+}
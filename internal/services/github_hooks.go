@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// ErrReceiverNotConfigured is returned by EnsureHook when the service's own
+// webhook receiver URL or secret is not configured
+var ErrReceiverNotConfigured = errors.New("webhook receiver URL and secret must be configured")
+
+// DefaultHookEvents are the events subscribed to by EnsureHook when none are given
+var DefaultHookEvents = []string{"push", "issues", "pull_request", "release", "workflow_run"}
+
+// ListHooks retrieves the webhooks of a repository, following the Link
+// header through every page
+func (s *GitHubService) ListHooks(repoName string) ([]models.Hook, error) {
+	var hooks []models.Hook
+	for path := s.repoPath(repoName) + "/hooks?per_page=100"; path != ""; {
+		req, err := s.newRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.Hook
+		header, err := s.doWithHeader(req, http.StatusOK, &page)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, page...)
+		path = nextPagePath(header)
+	}
+
+	return hooks, nil
+}
+
+// CreateHook creates a repository webhook
+func (s *GitHubService) CreateHook(repoName string, hook *models.HookRequest) (*models.Hook, error) {
+	req, err := s.newRequest("POST", s.repoPath(repoName)+"/hooks", hook)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.Hook
+	if err := s.do(req, http.StatusCreated, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateHook updates a repository webhook
+func (s *GitHubService) UpdateHook(repoName string, hookID int64, hook *models.HookRequest) (*models.Hook, error) {
+	req, err := s.newRequest("PATCH", fmt.Sprintf("%s/hooks/%d", s.repoPath(repoName), hookID), hook)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.Hook
+	if err := s.do(req, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteHook deletes a repository webhook
+func (s *GitHubService) DeleteHook(repoName string, hookID int64) error {
+	req, err := s.newRequest("DELETE", fmt.Sprintf("%s/hooks/%d", s.repoPath(repoName), hookID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, nil)
+}
+
+// PingHook triggers a ping event for a repository webhook
+func (s *GitHubService) PingHook(repoName string, hookID int64) error {
+	req, err := s.newRequest("POST", fmt.Sprintf("%s/hooks/%d/pings", s.repoPath(repoName), hookID), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, nil)
+}
+
+// ListHookDeliveries retrieves the recent deliveries of a repository webhook
+func (s *GitHubService) ListHookDeliveries(repoName string, hookID int64) ([]models.HookDelivery, error) {
+	req, err := s.newRequest("GET", fmt.Sprintf("%s/hooks/%d/deliveries?per_page=50", s.repoPath(repoName), hookID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.HookDelivery
+	if err := s.do(req, http.StatusOK, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverHookDelivery asks GitHub to send a past delivery again
+func (s *GitHubService) RedeliverHookDelivery(repoName string, hookID int64, deliveryID int64) error {
+	path := fmt.Sprintf("%s/hooks/%d/deliveries/%d/attempts", s.repoPath(repoName), hookID, deliveryID)
+	req, err := s.newRequest("POST", path, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusAccepted, nil)
+}
+
+// EnsureHook makes sure the repository has exactly one active webhook pointing
+// at this service's receiver. An existing hook for the receiver URL is updated
+// in place and any duplicates of it are deleted, so the call can be repeated
+// safely. It reports whether a new hook was created.
+func (s *GitHubService) EnsureHook(repoName string, events []string) (*models.Hook, bool, error) {
+	receiverURL := s.config.Webhooks.PublicURL
+	if receiverURL == "" || s.config.Webhooks.Secret == "" {
+		return nil, false, ErrReceiverNotConfigured
+	}
+
+	if len(events) == 0 {
+		events = DefaultHookEvents
+	}
+
+	active := true
+	desired := &models.HookRequest{
+		Name:   "web",
+		Active: &active,
+		Events: events,
+		Config: &models.HookConfig{
+			URL:         receiverURL,
+			ContentType: "json",
+			Secret:      s.config.Webhooks.Secret,
+			InsecureSSL: "0",
+		},
+	}
+
+	hooks, err := s.ListHooks(repoName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var matches []models.Hook
+	for _, hook := range hooks {
+		if hook.Config.URL == receiverURL {
+			matches = append(matches, hook)
+		}
+	}
+	if len(matches) == 0 {
+		created, err := s.CreateHook(repoName, desired)
+		return created, err == nil, err
+	}
+
+	// The secret is write-only, so the hook is always updated to guarantee
+	// it matches the configured secret.
+	updated, err := s.UpdateHook(repoName, matches[0].ID, desired)
+	if err != nil {
+		return nil, false, err
+	}
+	// Duplicates would deliver every event more than once
+	for _, duplicate := range matches[1:] {
+		if err := s.DeleteHook(repoName, duplicate.ID); err != nil {
+			return nil, false, fmt.Errorf("failed to delete duplicate hook %d: %w", duplicate.ID, err)
+		}
+	}
+	return updated, false, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnsureHook tests that EnsureHook updates a matching hook or creates a new one
+func TestEnsureHook(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+		Webhooks: config.WebhookConfig{
+			Secret:    "hook-secret",
+			PublicURL: "https://svc.example.com/webhooks/github",
+		},
+	}
+
+	// Test cases
+	tests := []struct {
+		name            string
+		existingHooks   string
+		expectedCalls   []string
+		expectedCreated bool
+	}{
+		{
+			name:            "Create When Missing",
+			existingHooks:   `[{"id": 1, "config": {"url": "https://other.example.com/hook"}}]`,
+			expectedCalls:   []string{"GET /repos/test-user/test-repo/hooks", "POST /repos/test-user/test-repo/hooks"},
+			expectedCreated: true,
+		},
+		{
+			name:            "Update When Present",
+			existingHooks:   `[{"id": 1, "config": {"url": "https://other.example.com/hook"}}, {"id": 2, "config": {"url": "https://svc.example.com/webhooks/github"}}]`,
+			expectedCalls:   []string{"GET /repos/test-user/test-repo/hooks", "PATCH /repos/test-user/test-repo/hooks/2"},
+			expectedCreated: false,
+		},
+		{
+			name:          "Delete Duplicates",
+			existingHooks: `[{"id": 2, "config": {"url": "https://svc.example.com/webhooks/github"}}, {"id": 3, "config": {"url": "https://svc.example.com/webhooks/github"}}, {"id": 4, "config": {"url": "https://svc.example.com/webhooks/github"}}]`,
+			expectedCalls: []string{
+				"GET /repos/test-user/test-repo/hooks",
+				"PATCH /repos/test-user/test-repo/hooks/2",
+				"DELETE /repos/test-user/test-repo/hooks/3",
+				"DELETE /repos/test-user/test-repo/hooks/4",
+			},
+			expectedCreated: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewGitHubService(cfg).(*GitHubService)
+
+			var calls []string
+			service.client.Transport = &mockTransport{
+				mockResponse: func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, req.Method+" "+req.URL.Path)
+
+					if req.Method == "GET" {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(tc.existingHooks)),
+							Header:     make(http.Header),
+						}, nil
+					}
+					if req.Method == "DELETE" {
+						return &http.Response{
+							StatusCode: http.StatusNoContent,
+							Body:       io.NopCloser(strings.NewReader("")),
+							Header:     make(http.Header),
+						}, nil
+					}
+
+					var sent models.HookRequest
+					_ = json.NewDecoder(req.Body).Decode(&sent)
+					assert.Equal(t, "https://svc.example.com/webhooks/github", sent.Config.URL)
+					assert.Equal(t, "hook-secret", sent.Config.Secret)
+					assert.Equal(t, DefaultHookEvents, sent.Events)
+
+					statusCode := http.StatusOK
+					if req.Method == "POST" {
+						statusCode = http.StatusCreated
+					}
+					return &http.Response{
+						StatusCode: statusCode,
+						Body:       io.NopCloser(strings.NewReader(`{"id": 2, "active": true}`)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			}
+
+			hook, created, err := service.EnsureHook("test-repo", nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), hook.ID)
+			assert.Equal(t, tc.expectedCreated, created)
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+// TestListHooksPagination tests that ListHooks follows the Link header to every page
+func TestListHooksPagination(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var calls []string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, req.URL.RequestURI())
+
+			header := make(http.Header)
+			body := `[{"id": 3}]`
+			if req.URL.Query().Get("page") == "" {
+				header.Set("Link", `<https://api.github.com/repositories/1/hooks?per_page=100&page=2>; rel="next", <https://api.github.com/repositories/1/hooks?per_page=100&page=2>; rel="last"`)
+				body = `[{"id": 1}, {"id": 2}]`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     header,
+			}, nil
+		},
+	}
+
+	hooks, err := service.ListHooks("test-repo")
+
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 3)
+	assert.Equal(t, int64(3), hooks[2].ID)
+	assert.Equal(t, []string{"/repos/test-user/test-repo/hooks?per_page=100", "/repositories/1/hooks?per_page=100&page=2"}, calls)
+
+	// Links to other hosts are not followed
+	assert.Empty(t, nextPagePath(http.Header{"Link": {`<https://evil.example.com/hooks?page=2>; rel="next"`}}))
+}
+
+// TestEnsureHookNotConfigured tests that EnsureHook requires the receiver settings
+func TestEnsureHookNotConfigured(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg)
+	_, _, err := service.EnsureHook("test-repo", nil)
+
+	assert.ErrorIs(t, err, ErrReceiverNotConfigured)
+}
@@ -1,11 +1,19 @@
 package services
 
 import (
+	"io"
+
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
 )
 
 // GitHubServiceInterface defines the interface for GitHub service operations
 type GitHubServiceInterface interface {
+	// WithToken returns a service that acts as the owner of token
+	WithToken(token string) GitHubServiceInterface
+
+	// CheckTokens reports the scopes or permissions of each of the service's tokens
+	CheckTokens() ([]*models.TokenCheck, error)
+
 	// GetUserProfile retrieves the user's GitHub profile
 	GetUserProfile() (*models.GithubProfile, error)
 
@@ -14,4 +22,97 @@ type GitHubServiceInterface interface {
 
 	// CreateIssue creates a new issue in a repository
 	CreateIssue(repoName string, issue *models.IssueRequest) (*models.IssueResponse, error)
+
+	// SearchIssues searches a repository's open issues
+	SearchIssues(repoName string, terms string) ([]models.IssueResponse, error)
+
+	// CreateIssueComment comments on an issue
+	CreateIssueComment(repoName string, number int, body string) error
+
+	// ListDirectory retrieves the entries of a directory in a repository
+	ListDirectory(repoName string, path string) ([]models.ContentEntry, error)
+
+	// GetFileContents retrieves a file from a repository
+	GetFileContents(repoName string, path string) ([]byte, error)
+
+	// ListIssues retrieves the most recently updated issues in a repository
+	ListIssues(repoName string, state string) ([]models.IssueResponse, error)
+
+	// ListReleases retrieves the published releases of a repository
+	ListReleases(repoName string) ([]models.Release, error)
+
+	// ListUserEvents retrieves the user's recent public activity
+	ListUserEvents() ([]models.Event, error)
+
+	// ListRepositoryEvents retrieves a repository's recent events unless they match etag
+	ListRepositoryEvents(repoName string, etag string) (*models.EventPage, error)
+
+	// ListWorkflows retrieves the workflows defined in a repository
+	ListWorkflows(repoName string) ([]models.Workflow, error)
+
+	// ListWorkflowRuns retrieves workflow runs matching the filter
+	ListWorkflowRuns(repoName string, filter *models.WorkflowRunFilter) (*models.WorkflowRunList, error)
+
+	// ListWorkflowRunJobs retrieves the jobs and steps of a workflow run
+	ListWorkflowRunJobs(repoName string, runID int64) ([]models.WorkflowJob, error)
+
+	// DownloadWorkflowRunLogs streams the log archive of a workflow run
+	DownloadWorkflowRunLogs(repoName string, runID int64) (io.ReadCloser, error)
+
+	// RerunFailedJobs re-runs the failed jobs of a workflow run
+	RerunFailedJobs(repoName string, runID int64) error
+
+	// CancelWorkflowRun cancels a workflow run
+	CancelWorkflowRun(repoName string, runID int64) error
+
+	// DispatchWorkflow triggers a workflow_dispatch event
+	DispatchWorkflow(repoName string, workflow string, dispatch *models.WorkflowDispatchRequest) error
+
+	// CreateCommitStatus creates a commit status for a SHA
+	CreateCommitStatus(repoName string, sha string, status *models.CommitStatusRequest) (*models.CommitStatus, error)
+
+	// CreateCheckRun creates a check run
+	CreateCheckRun(repoName string, checkRun *models.CheckRunRequest) (*models.CheckRun, error)
+
+	// UpdateCheckRun updates a check run
+	UpdateCheckRun(repoName string, checkRunID int64, checkRun *models.CheckRunRequest) (*models.CheckRun, error)
+
+	// CreateDeployment creates a deployment for a ref
+	CreateDeployment(repoName string, deployment *models.DeploymentRequest) (*models.Deployment, error)
+
+	// ListDeployments retrieves the deployments of a repository
+	ListDeployments(repoName string, filter *models.DeploymentFilter) ([]models.Deployment, error)
+
+	// CreateDeploymentStatus creates a status for a deployment
+	CreateDeploymentStatus(repoName string, deploymentID int64, status *models.DeploymentStatusRequest) (*models.DeploymentStatus, error)
+
+	// ListDeploymentStatuses retrieves the statuses of a deployment
+	ListDeploymentStatuses(repoName string, deploymentID int64) ([]models.DeploymentStatus, error)
+
+	// CompleteDeployment moves a deployment through in_progress to a final state
+	CompleteDeployment(repoName string, deploymentID int64, completion *models.DeploymentCompletionRequest) ([]models.DeploymentStatus, error)
+
+	// ListHooks retrieves the webhooks of a repository
+	ListHooks(repoName string) ([]models.Hook, error)
+
+	// CreateHook creates a repository webhook
+	CreateHook(repoName string, hook *models.HookRequest) (*models.Hook, error)
+
+	// UpdateHook updates a repository webhook
+	UpdateHook(repoName string, hookID int64, hook *models.HookRequest) (*models.Hook, error)
+
+	// DeleteHook deletes a repository webhook
+	DeleteHook(repoName string, hookID int64) error
+
+	// PingHook triggers a ping event for a repository webhook
+	PingHook(repoName string, hookID int64) error
+
+	// ListHookDeliveries retrieves the recent deliveries of a repository webhook
+	ListHookDeliveries(repoName string, hookID int64) ([]models.HookDelivery, error)
+
+	// RedeliverHookDelivery asks GitHub to send a past delivery again
+	RedeliverHookDelivery(repoName string, hookID int64, deliveryID int64) error
+
+	// EnsureHook points a single repository webhook at this service's receiver
+	EnsureHook(repoName string, events []string) (*models.Hook, bool, error)
 }
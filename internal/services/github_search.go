@@ -0,0 +1,37 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// SearchIssues searches a repository's open issues. Terms use GitHub's
+// search syntax and are combined with qualifiers limiting the search to the repository.
+func (s *GitHubService) SearchIssues(repoName string, terms string) ([]models.IssueResponse, error) {
+	query := fmt.Sprintf("repo:%s/%s is:issue is:open %s", s.config.GitHub.Username, repoName, terms)
+	req, err := s.newRequest("GET", "/search/issues?per_page=20&q="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.IssueSearchResult
+	if err := s.do(req, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Items, nil
+}
+
+// CreateIssueComment comments on an issue
+func (s *GitHubService) CreateIssueComment(repoName string, number int, body string) error {
+	path := fmt.Sprintf("%s/issues/%d/comments", s.repoPath(repoName), number)
+	req, err := s.newRequest("POST", path, map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusCreated, nil)
+}
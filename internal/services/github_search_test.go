@@ -0,0 +1,73 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearchIssues tests that searches are limited to the repository's open issues
+func TestSearchIssues(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var query string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query().Get("q")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"total_count": 1, "items": [{"number": 3, "title": "App crashes"}]}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	issues, err := service.SearchIssues("test-repo", "app OR crashes")
+	require.NoError(t, err)
+	assert.Equal(t, "repo:test-user/test-repo is:issue is:open app OR crashes", query)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 3, issues[0].Number)
+}
+
+// TestCreateIssueComment tests that comments are posted to the issue
+func TestCreateIssueComment(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var path string
+	var sent map[string]string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			path = req.Method + " " + req.URL.Path
+			_ = json.NewDecoder(req.Body).Decode(&sent)
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"id": 1}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	require.NoError(t, service.CreateIssueComment("test-repo", 12, "Possible duplicate"))
+	assert.Equal(t, "POST /repos/test-user/test-repo/issues/12/comments", path)
+	assert.Equal(t, "Possible duplicate", sent["body"])
+}
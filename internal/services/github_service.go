@@ -6,26 +6,58 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
 	"github.com/sirupsen/logrus"
 )
 
+// githubAPIURL is the base URL of the GitHub REST API
+const githubAPIURL = "https://api.github.com"
+
+// APIError is returned when the GitHub API responds with an unexpected status code
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API returned status code %d", e.StatusCode)
+}
+
 // GitHubService provides methods for interacting with the GitHub API
 type GitHubService struct {
 	config *config.Config
 	client *http.Client
+	tokens TokenSource
+	// userToken replaces the configured token for a signed-in user's requests
+	userToken string
 }
 
-// NewGitHubService creates a new GitHubService
+// NewGitHubService creates a new GitHubService authenticated with the configured token
 func NewGitHubService(config *config.Config) GitHubServiceInterface {
+	return NewGitHubServiceWithTokens(config, StaticTokenSource(config.GitHub.Token))
+}
+
+// NewGitHubServiceWithTokens creates a new GitHubService that takes its tokens from tokens
+func NewGitHubServiceWithTokens(config *config.Config, tokens TokenSource) GitHubServiceInterface {
 	return &GitHubService{
 		config: config,
 		client: &http.Client{},
+		tokens: tokens,
 	}
 }
 
+// WithToken returns a copy of the service that sends requests with token
+// instead of the configured service token
+func (s *GitHubService) WithToken(token string) GitHubServiceInterface {
+	copied := *s
+	copied.userToken = token
+	return &copied
+}
+
 // GetUserProfile retrieves the user's GitHub profile
 func (s *GitHubService) GetUserProfile() (*models.GithubProfile, error) {
 	// Get user data
@@ -48,34 +80,14 @@ func (s *GitHubService) GetUserProfile() (*models.GithubProfile, error) {
 
 // GetRepository retrieves details about a specific repository
 func (s *GitHubService) GetRepository(repoName string) (*models.Repository, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", s.config.GitHub.Username, repoName)
-
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := s.newRequest("GET", s.repoPath(repoName), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", s.config.GitHub.Token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		logrus.WithFields(logrus.Fields{
-			"status_code": resp.StatusCode,
-			"response":    string(body),
-		}).Error("GitHub API error")
-		return nil, fmt.Errorf("GitHub API returned status code %d", resp.StatusCode)
+		return nil, err
 	}
 
 	var repo models.Repository
-	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.do(req, http.StatusOK, &repo); err != nil {
+		return nil, err
 	}
 
 	return &repo, nil
@@ -83,76 +95,77 @@ func (s *GitHubService) GetRepository(repoName string) (*models.Repository, erro
 
 // CreateIssue creates a new issue in a repository
 func (s *GitHubService) CreateIssue(repoName string, issue *models.IssueRequest) (*models.IssueResponse, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", s.config.GitHub.Username, repoName)
-
-	// Create request body
-	body, err := json.Marshal(issue)
+	req, err := s.newRequest("POST", s.repoPath(repoName)+"/issues", issue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var issueResponse models.IssueResponse
+	if err := s.do(req, http.StatusCreated, &issueResponse); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", s.config.GitHub.Token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Content-Type", "application/json")
+	return &issueResponse, nil
+}
 
-	resp, err := s.client.Do(req)
+// ListIssues retrieves the most recently updated issues in a repository
+func (s *GitHubService) ListIssues(repoName string, state string) ([]models.IssueResponse, error) {
+	path := fmt.Sprintf("%s/issues?state=%s&sort=updated&direction=desc&per_page=50", s.repoPath(repoName), state)
+	req, err := s.newRequest("GET", path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		logrus.WithFields(logrus.Fields{
-			"status_code": resp.StatusCode,
-			"response":    string(body),
-		}).Error("GitHub API error")
-		return nil, fmt.Errorf("GitHub API returned status code %d", resp.StatusCode)
+		return nil, err
 	}
 
-	var issueResponse models.IssueResponse
-	if err := json.NewDecoder(resp.Body).Decode(&issueResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var issues []models.IssueResponse
+	if err := s.do(req, http.StatusOK, &issues); err != nil {
+		return nil, err
 	}
 
-	return &issueResponse, nil
+	return issues, nil
 }
 
-// getUser retrieves the user's GitHub profile
-func (s *GitHubService) getUser() (*models.UserResponse, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s", s.config.GitHub.Username)
-
-	req, err := http.NewRequest("GET", url, nil)
+// ListReleases retrieves the published releases of a repository
+func (s *GitHubService) ListReleases(repoName string) ([]models.Release, error) {
+	req, err := s.newRequest("GET", s.repoPath(repoName)+"/releases?per_page=50", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", s.config.GitHub.Token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	var releases []models.Release
+	if err := s.do(req, http.StatusOK, &releases); err != nil {
+		return nil, err
+	}
 
-	resp, err := s.client.Do(req)
+	return releases, nil
+}
+
+// ListUserEvents retrieves the user's recent public activity. The public
+// endpoint is used because the user's own token would also return private events.
+func (s *GitHubService) ListUserEvents() ([]models.Event, error) {
+	path := fmt.Sprintf("/users/%s/events/public?per_page=50", s.config.GitHub.Username)
+	req, err := s.newRequest("GET", path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		logrus.WithFields(logrus.Fields{
-			"status_code": resp.StatusCode,
-			"response":    string(body),
-		}).Error("GitHub API error")
-		return nil, fmt.Errorf("GitHub API returned status code %d", resp.StatusCode)
+	var events []models.Event
+	if err := s.do(req, http.StatusOK, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// getUser retrieves the user's GitHub profile
+func (s *GitHubService) getUser() (*models.UserResponse, error) {
+	req, err := s.newRequest("GET", "/users/"+s.config.GitHub.Username, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	var user models.UserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.do(req, http.StatusOK, &user); err != nil {
+		return nil, err
 	}
 
 	return &user, nil
@@ -160,35 +173,129 @@ func (s *GitHubService) getUser() (*models.UserResponse, error) {
 
 // getUserRepositories retrieves the user's repositories
 func (s *GitHubService) getUserRepositories() ([]models.Repository, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/repos?type=owner&sort=updated&per_page=100", s.config.GitHub.Username)
+	path := fmt.Sprintf("/users/%s/repos?type=owner&sort=updated&per_page=100", s.config.GitHub.Username)
+	req, err := s.newRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []models.Repository
+	if err := s.do(req, http.StatusOK, &repos); err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// repoPath returns the API path of one of the user's repositories
+func (s *GitHubService) repoPath(repoName string) string {
+	return fmt.Sprintf("/repos/%s/%s", s.config.GitHub.Username, repoName)
+}
+
+// newRequest creates an authenticated GitHub API request, encoding body as JSON when it is not nil
+func (s *GitHubService) newRequest(method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(payload)
+	}
+
+	req, err := http.NewRequest(method, githubAPIURL+path, reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", s.config.GitHub.Token))
+	token, err := s.token(method)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
+	return req, nil
+}
+
+// token returns the token for a request, keeping writes on the write token
+// when the token source designates one
+func (s *GitHubService) token(method string) (string, error) {
+	if s.userToken != "" {
+		return s.userToken, nil
+	}
+	if writer, ok := s.tokens.(WriteTokenSource); ok && method != "GET" && method != "HEAD" {
+		return writer.WriteToken(s.config.GitHub.Username)
+	}
+	return s.tokens.Token(s.config.GitHub.Username)
+}
+
+// send sends a request and reports the response to the token source
+func (s *GitHubService) send(req *http.Request) (*http.Response, error) {
 	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users' own tokens are not part of the service's token source
+	if observer, ok := s.tokens.(ResponseObserver); ok && s.userToken == "" {
+		observer.Observe(strings.TrimPrefix(req.Header.Get("Authorization"), "token "), resp)
+	}
+	return resp, nil
+}
+
+// do sends a request and decodes the JSON response into out, which may be nil.
+// Any status other than expectedStatus is returned as an *APIError.
+func (s *GitHubService) do(req *http.Request, expectedStatus int, out interface{}) error {
+	_, err := s.doWithHeader(req, expectedStatus, out)
+	return err
+}
+
+// doWithHeader is like do, also returning the response headers, such as the
+// Link header of a paginated list
+func (s *GitHubService) doWithHeader(req *http.Request, expectedStatus int, out interface{}) (http.Header, error) {
+	resp, err := s.send(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != expectedStatus {
 		body, _ := io.ReadAll(resp.Body)
 		logrus.WithFields(logrus.Fields{
 			"status_code": resp.StatusCode,
 			"response":    string(body),
 		}).Error("GitHub API error")
-		return nil, fmt.Errorf("GitHub API returned status code %d", resp.StatusCode)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var repos []models.Repository
-	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
+	if out == nil {
+		return resp.Header, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return repos, nil
+	return resp.Header, nil
+}
+
+// nextPagePath returns the API path of the next page named by a Link header,
+// or "" on the last page. Links outside the GitHub API are not followed, so
+// the token is never sent elsewhere.
+func nextPagePath(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		if path, ok := strings.CutPrefix(target, githubAPIURL+"/"); ok {
+			return "/" + path
+		}
+	}
+	return ""
 }
@@ -285,3 +285,128 @@ func TestCreateIssue(t *testing.T) {
 		})
 	}
 }
+
+// TestListReleases tests the ListReleases function
+func TestListReleases(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var requestedURL string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			requestedURL = req.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`[{"id": 1, "tag_name": "v1.0.0"}]`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	releases, err := service.ListReleases("test-repo")
+
+	assert.NoError(t, err)
+	assert.Len(t, releases, 1)
+	assert.Equal(t, "v1.0.0", releases[0].TagName)
+	assert.Equal(t, "https://api.github.com/repos/test-user/test-repo/releases?per_page=50", requestedURL)
+}
+
+// TestAPIError tests that unexpected status codes are returned as APIError
+func TestAPIError(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "/users/test-user/events/public", req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	_, err := service.ListUserEvents()
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "GitHub API returned status code 404", err.Error())
+}
+
+// TestWithToken tests that a user's token replaces the service token without changing the original service
+func TestWithToken(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var authorizations []string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			authorizations = append(authorizations, req.Header.Get("Authorization"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`[]`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	_, err := service.WithToken("user-token").ListReleases("test-repo")
+	assert.NoError(t, err)
+	_, err = service.ListReleases("test-repo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"token user-token", "token test-token"}, authorizations)
+}
+
+// TestListWorkflowRuns tests that workflow run filters are sent to GitHub
+func TestListWorkflowRuns(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:    "test-token",
+			Username: "test-user",
+		},
+	}
+
+	service := NewGitHubService(cfg).(*GitHubService)
+
+	var requestedURL string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			requestedURL = req.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"total_count": 1, "workflow_runs": [{"id": 42}]}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	runs, err := service.ListWorkflowRuns("test-repo", &models.WorkflowRunFilter{
+		Workflow: "ci.yml",
+		Branch:   "main",
+		Status:   "failure",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), runs.WorkflowRuns[0].ID)
+	assert.Equal(t, "https://api.github.com/repos/test-user/test-repo/actions/workflows/ci.yml/runs?branch=main&status=failure", requestedURL)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Token check modes selected by config.GitHubConfig.TokenCheck
+const (
+	TokenCheckOff    = "off"
+	TokenCheckWarn   = "warn"
+	TokenCheckStrict = "strict"
+)
+
+// TokenRequirement is the access a feature needs from the service's token
+type TokenRequirement struct {
+	Feature string
+	// Scopes lists the classic OAuth scopes, any of which grants the access
+	Scopes []string
+	// Permission and Access name the GitHub App permission that grants it
+	Permission string
+	Access     string
+}
+
+// TokenRequirements returns the access needed by the features the configuration enables
+func TokenRequirements(cfg *config.Config) []TokenRequirement {
+	requirements := []TokenRequirement{
+		{Feature: "issues", Scopes: []string{"repo", "public_repo"}, Permission: "issues", Access: "write"},
+		{Feature: "actions", Scopes: []string{"repo", "public_repo"}, Permission: "actions", Access: "write"},
+		{Feature: "commit statuses", Scopes: []string{"repo", "public_repo", "repo:status"}, Permission: "statuses", Access: "write"},
+		{Feature: "deployments", Scopes: []string{"repo", "public_repo", "repo_deployment"}, Permission: "deployments", Access: "write"},
+	}
+	if cfg.Webhooks.PublicURL != "" {
+		requirements = append(requirements, TokenRequirement{
+			Feature:    "webhook registration",
+			Scopes:     []string{"admin:repo_hook", "write:repo_hook"},
+			Permission: "repository_hooks",
+			Access:     "write",
+		})
+	}
+	return requirements
+}
+
+// MissingRequirements returns the requirements the checked token does not
+// meet. Nothing is missing when the token cannot be introspected.
+func MissingRequirements(check *models.TokenCheck, requirements []TokenRequirement) []TokenRequirement {
+	var missing []TokenRequirement
+	for _, requirement := range requirements {
+		if check.Permissions != nil {
+			access := check.Permissions[requirement.Permission]
+			if access == "write" || (access == "read" && requirement.Access == "read") {
+				continue
+			}
+			missing = append(missing, requirement)
+			continue
+		}
+		if check.Introspectable && !hasAnyScope(check.Scopes, requirement.Scopes) {
+			missing = append(missing, requirement)
+		}
+	}
+	return missing
+}
+
+// CheckTokens verifies the service's tokens and reports their scopes or
+// permissions. Every token of a pool is checked, since any of them may be
+// sent; otherwise the token used for writes is.
+func (s *GitHubService) CheckTokens() ([]*models.TokenCheck, error) {
+	var tokens []string
+	if pool, ok := s.tokens.(PooledTokenSource); ok && s.userToken == "" {
+		tokens = pool.Tokens()
+	} else {
+		token, err := s.token("POST")
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	checks := make([]*models.TokenCheck, 0, len(tokens))
+	for _, token := range tokens {
+		check, err := s.checkToken(token)
+		if err != nil {
+			return nil, fmt.Errorf("token %s: %w", tokenLabel(token), err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// checkToken reports the scopes or permissions of one token
+func (s *GitHubService) checkToken(token string) (*models.TokenCheck, error) {
+	// The rate limit endpoint accepts every kind of token and does not count against the limit
+	req, err := s.newRequest("GET", "/rate_limit", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	check := &models.TokenCheck{Token: tokenLabel(token)}
+	if _, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]; ok {
+		check.Introspectable = true
+		for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				check.Scopes = append(check.Scopes, scope)
+			}
+		}
+		return check, nil
+	}
+
+	if permissions, ok := s.tokens.(PermissionSource); ok {
+		check.Permissions, err = permissions.Permissions(s.config.GitHub.Username)
+		if err != nil {
+			return nil, err
+		}
+		check.Introspectable = true
+	}
+	return check, nil
+}
+
+// VerifyToken checks the service's tokens against the requirements of the
+// enabled features. Problems are logged in warn mode and returned as an
+// error in strict mode.
+func VerifyToken(service GitHubServiceInterface, cfg *config.Config) error {
+	mode := cfg.GitHub.TokenCheck
+	if mode == TokenCheckOff {
+		return nil
+	}
+
+	checks, err := service.CheckTokens()
+	if err != nil {
+		if mode == TokenCheckStrict {
+			return fmt.Errorf("failed to verify GitHub token: %w", err)
+		}
+		logrus.WithError(err).Warn("Failed to verify GitHub token")
+		return nil
+	}
+
+	var problems []string
+	for _, check := range checks {
+		if !check.Introspectable {
+			logrus.WithField("token", check.Token).Warn("GitHub token is valid but its permissions cannot be introspected; features may fail if it lacks access")
+			continue
+		}
+
+		missing := MissingRequirements(check, TokenRequirements(cfg))
+		if len(missing) == 0 {
+			logrus.WithFields(logrus.Fields{
+				"token":  check.Token,
+				"scopes": check.Scopes,
+			}).Info("Verified GitHub token")
+			continue
+		}
+
+		features := make([]string, 0, len(missing))
+		for _, requirement := range missing {
+			features = append(features, requirement.Feature)
+			logrus.WithFields(logrus.Fields{
+				"token":      check.Token,
+				"feature":    requirement.Feature,
+				"scopes":     strings.Join(requirement.Scopes, " or "),
+				"permission": requirement.Permission + ":" + requirement.Access,
+			}).Warn("GitHub token lacks access needed by an enabled feature")
+		}
+		problems = append(problems, fmt.Sprintf("token %s lacks access for %s", check.Token, strings.Join(features, ", ")))
+	}
+	if mode == TokenCheckStrict && len(problems) > 0 {
+		return fmt.Errorf("GitHub %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// hasAnyScope reports whether granted contains any of wanted
+func hasAnyScope(granted, wanted []string) bool {
+	for _, scope := range wanted {
+		for _, g := range granted {
+			if g == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
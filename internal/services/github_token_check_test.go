@@ -0,0 +1,157 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenCheckService creates a service whose rate limit endpoint answers with status and scopes
+func newTokenCheckService(cfg *config.Config, status int, scopes *string) *GitHubService {
+	service := NewGitHubService(cfg).(*GitHubService)
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			header := make(http.Header)
+			if scopes != nil {
+				header.Set("X-OAuth-Scopes", *scopes)
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Header:     header,
+			}, nil
+		},
+	}
+	return service
+}
+
+// TestCheckToken tests that scopes are read from classic tokens and bad tokens are reported
+func TestCheckToken(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Token: "test-token", Username: "test-user"}}
+
+	scopes := "repo, admin:repo_hook"
+	checks, err := newTokenCheckService(cfg, http.StatusOK, &scopes).CheckTokens()
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	check := checks[0]
+	assert.Equal(t, tokenLabel("test-token"), check.Token)
+	assert.True(t, check.Introspectable)
+	assert.Equal(t, []string{"repo", "admin:repo_hook"}, check.Scopes)
+
+	// Fine-grained tokens carry no scopes header
+	checks, err = newTokenCheckService(cfg, http.StatusOK, nil).CheckTokens()
+	require.NoError(t, err)
+	assert.False(t, checks[0].Introspectable)
+
+	_, err = newTokenCheckService(cfg, http.StatusUnauthorized, nil).CheckTokens()
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+}
+
+// TestCheckPooledTokens tests that every token of a pool is checked, not only the write token
+func TestCheckPooledTokens(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{
+		Tokens:     []string{"write-token", "read-token"},
+		Username:   "test-user",
+		TokenCheck: TokenCheckStrict,
+	}}
+	pool, err := NewTokenPool(cfg.GitHub.Tokens, "", time.Hour)
+	require.NoError(t, err)
+	service := NewGitHubServiceWithTokens(cfg, pool).(*GitHubService)
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			header := make(http.Header)
+			header.Set("X-OAuth-Scopes", "repo")
+			if req.Header.Get("Authorization") == "token read-token" {
+				header.Set("X-OAuth-Scopes", "read:user")
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Header:     header,
+			}, nil
+		},
+	}
+
+	checks, err := service.CheckTokens()
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.Equal(t, tokenLabel("write-token"), checks[0].Token)
+	assert.Equal(t, []string{"read:user"}, checks[1].Scopes)
+
+	err = VerifyToken(service, cfg)
+	assert.ErrorContains(t, err, "token "+tokenLabel("read-token")+" lacks access")
+	assert.NotContains(t, err.Error(), tokenLabel("write-token"))
+}
+
+// TestMissingRequirements tests comparing scopes and app permissions with feature requirements
+func TestMissingRequirements(t *testing.T) {
+	cfg := &config.Config{Webhooks: config.WebhookConfig{PublicURL: "https://service.example.com/webhooks/github"}}
+	requirements := TokenRequirements(cfg)
+	assert.Len(t, requirements, 5)
+	assert.Len(t, TokenRequirements(&config.Config{}), 4)
+
+	features := func(missing []TokenRequirement) []string {
+		var names []string
+		for _, requirement := range missing {
+			names = append(names, requirement.Feature)
+		}
+		return names
+	}
+
+	// Test cases
+	tests := []struct {
+		name            string
+		check           *models.TokenCheck
+		expectedMissing []string
+	}{
+		{
+			name:  "Full Classic Token",
+			check: &models.TokenCheck{Introspectable: true, Scopes: []string{"repo", "admin:repo_hook"}},
+		},
+		{
+			name:            "Status Only Classic Token",
+			check:           &models.TokenCheck{Introspectable: true, Scopes: []string{"repo:status"}},
+			expectedMissing: []string{"issues", "actions", "deployments", "webhook registration"},
+		},
+		{
+			name:  "Fine-Grained Token",
+			check: &models.TokenCheck{},
+		},
+		{
+			name: "App With Read Access",
+			check: &models.TokenCheck{Introspectable: true, Permissions: map[string]string{
+				"issues": "write", "actions": "read", "statuses": "write", "deployments": "write", "repository_hooks": "write",
+			}},
+			expectedMissing: []string{"actions"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMissing, features(MissingRequirements(tc.check, requirements)))
+		})
+	}
+}
+
+// TestVerifyToken tests that missing access only fails startup in strict mode
+func TestVerifyToken(t *testing.T) {
+	scopes := "read:user"
+	for mode, expectError := range map[string]bool{TokenCheckOff: false, TokenCheckWarn: false, TokenCheckStrict: true} {
+		cfg := &config.Config{GitHub: config.GitHubConfig{Token: "test-token", Username: "test-user", TokenCheck: mode}}
+		err := VerifyToken(newTokenCheckService(cfg, http.StatusOK, &scopes), cfg)
+		assert.Equal(t, expectError, err != nil, mode)
+	}
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{Token: "test-token", Username: "test-user", TokenCheck: TokenCheckStrict}}
+	err := VerifyToken(newTokenCheckService(cfg, http.StatusUnauthorized, nil), cfg)
+	assert.ErrorContains(t, err, "failed to verify GitHub token")
+	assert.NoError(t, VerifyToken(newTokenCheckService(cfg, http.StatusOK, nil), cfg))
+}
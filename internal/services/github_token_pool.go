@@ -0,0 +1,208 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultRateLimit is assumed for tokens GitHub has not yet reported a limit for
+const defaultRateLimit = 5000
+
+var (
+	// ErrNoHealthyToken is returned when every token in a pool is quarantined
+	ErrNoHealthyToken = errors.New("no healthy GitHub token available")
+
+	tokenRemaining   = metrics.Default.NewGauge("github_token_rate_limit_remaining", "Requests left in the current rate limit window of each GitHub token", "token")
+	tokenHealthy     = metrics.Default.NewGauge("github_token_healthy", "Whether each GitHub token is in use (1) or quarantined (0)", "token")
+	tokenQuarantines = metrics.Default.NewCounter("github_token_quarantines_total", "GitHub tokens quarantined after GitHub rejected them", "token")
+)
+
+// WriteTokenSource is implemented by token sources that keep writes on a
+// designated token so that the authorship of created content stays consistent
+type WriteTokenSource interface {
+	WriteToken(owner string) (string, error)
+}
+
+// ResponseObserver is implemented by token sources that learn from the
+// responses GitHub returns to each token
+type ResponseObserver interface {
+	Observe(token string, resp *http.Response)
+}
+
+// pooledToken is a token and what GitHub last reported about it
+type pooledToken struct {
+	token string
+	// label identifies the token in logs and metrics without revealing it
+	label            string
+	limit            int
+	remaining        int
+	resetAt          time.Time
+	quarantinedUntil time.Time
+}
+
+// available returns the requests the token is expected to have left at now
+func (t *pooledToken) available(now time.Time) int {
+	if now.After(t.resetAt) {
+		return t.limit
+	}
+	return t.remaining
+}
+
+// healthy reports whether the token is out of quarantine at now. The health
+// gauge is updated to match, since a quarantine ends without any response.
+func (t *pooledToken) healthy(now time.Time) bool {
+	if now.Before(t.quarantinedUntil) {
+		tokenHealthy.Set(0, t.label)
+		return false
+	}
+	tokenHealthy.Set(1, t.label)
+	return true
+}
+
+// TokenPool spreads reads across several tokens, choosing the one with the
+// most rate limit remaining, and sends writes with a designated token.
+// Tokens that GitHub rejects are quarantined for a while.
+type TokenPool struct {
+	quarantine time.Duration
+
+	mu     sync.Mutex
+	tokens []*pooledToken
+	writer *pooledToken
+
+	// nowFunc returns the current time; it is replaced in tests
+	nowFunc func() time.Time
+}
+
+// NewTokenPool creates a TokenPool. The write token is added to the pool
+// when it is not one of tokens; an empty write token designates the first token.
+func NewTokenPool(tokens []string, writeToken string, quarantine time.Duration) (*TokenPool, error) {
+	pool := &TokenPool{
+		quarantine: quarantine,
+		nowFunc:    time.Now,
+	}
+
+	seen := make(map[string]bool)
+	for _, token := range append([]string{writeToken}, tokens...) {
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		pooled := &pooledToken{
+			token:     token,
+			label:     tokenLabel(token),
+			limit:     defaultRateLimit,
+			remaining: defaultRateLimit,
+		}
+		pool.tokens = append(pool.tokens, pooled)
+		tokenRemaining.Set(defaultRateLimit, pooled.label)
+		tokenHealthy.Set(1, pooled.label)
+	}
+	if len(pool.tokens) == 0 {
+		return nil, errors.New("token pool requires at least one token")
+	}
+
+	pool.writer = pool.tokens[0]
+	return pool, nil
+}
+
+// tokenLabel identifies a token in logs and metrics without revealing it
+func tokenLabel(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Tokens implements PooledTokenSource, returning the write token first
+func (p *TokenPool) Tokens() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tokens := make([]string, 0, len(p.tokens))
+	for _, t := range p.tokens {
+		tokens = append(tokens, t.token)
+	}
+	return tokens
+}
+
+// Token implements TokenSource, returning the healthy token with the most
+// rate limit remaining
+func (p *TokenPool) Token(string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.nowFunc()
+	var best *pooledToken
+	for _, t := range p.tokens {
+		if !t.healthy(now) {
+			continue
+		}
+		if best == nil || t.available(now) > best.available(now) {
+			best = t
+		}
+	}
+	if best == nil {
+		return "", ErrNoHealthyToken
+	}
+	return best.token, nil
+}
+
+// WriteToken implements WriteTokenSource. Writes fail rather than move to
+// another token while the write token is quarantined.
+func (p *TokenPool) WriteToken(string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.writer.healthy(p.nowFunc()) {
+		return "", ErrNoHealthyToken
+	}
+	return p.writer.token, nil
+}
+
+// Observe implements ResponseObserver, recording the token's rate limit and
+// quarantining it when GitHub rejects it
+func (p *TokenPool) Observe(token string, resp *http.Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var t *pooledToken
+	for _, candidate := range p.tokens {
+		if candidate.token == token {
+			t = candidate
+			break
+		}
+	}
+	if t == nil {
+		return
+	}
+
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		t.limit = limit
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		t.remaining = remaining
+		tokenRemaining.Set(float64(remaining), t.label)
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		t.resetAt = time.Unix(reset, 0)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		t.quarantinedUntil = p.nowFunc().Add(p.quarantine)
+		tokenHealthy.Set(0, t.label)
+		tokenQuarantines.Inc(t.label)
+		logrus.WithFields(logrus.Fields{
+			"token":             t.label,
+			"quarantined_until": t.quarantinedUntil,
+		}).Warn("Quarantined GitHub token rejected with 401")
+		return
+	}
+	t.healthy(p.nowFunc())
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitResponse creates a response reporting a token's remaining rate limit
+func rateLimitResponse(status, remaining int, resetAt time.Time) *http.Response {
+	header := make(http.Header)
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+	return &http.Response{StatusCode: status, Header: header}
+}
+
+// TestTokenPool tests rate-limit-aware selection, the write token and quarantine
+func TestTokenPool(t *testing.T) {
+	_, err := NewTokenPool(nil, "", time.Hour)
+	assert.Error(t, err)
+
+	pool, err := NewTokenPool([]string{"read-1", "read-2", "writer"}, "writer", time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	pool.nowFunc = func() time.Time { return now }
+	reset := now.Add(30 * time.Minute)
+
+	pool.Observe("writer", rateLimitResponse(http.StatusOK, 100, reset))
+	pool.Observe("read-1", rateLimitResponse(http.StatusOK, 3000, reset))
+	pool.Observe("read-2", rateLimitResponse(http.StatusOK, 4000, reset))
+
+	token, err := pool.Token("test-user")
+	require.NoError(t, err)
+	assert.Equal(t, "read-2", token)
+	assert.Equal(t, float64(4000), tokenRemaining.Value(pool.tokens[2].label))
+
+	// Writes stay on the write token whatever its rate limit
+	token, err = pool.WriteToken("test-user")
+	require.NoError(t, err)
+	assert.Equal(t, "writer", token)
+
+	// A rejected token is skipped until its quarantine ends
+	label := pool.tokens[2].label
+	quarantines := tokenQuarantines.Value(label)
+	pool.Observe("read-2", rateLimitResponse(http.StatusUnauthorized, 4000, reset))
+	token, _ = pool.Token("test-user")
+	assert.Equal(t, "read-1", token)
+	assert.Equal(t, float64(0), tokenHealthy.Value(label))
+	assert.Equal(t, float64(1), tokenQuarantines.Value(label)-quarantines)
+
+	// Once a window resets the token is assumed to have its full limit again
+	now = now.Add(31 * time.Minute)
+	pool.Observe("read-2", rateLimitResponse(http.StatusOK, 10, now.Add(time.Hour)))
+	token, _ = pool.Token("test-user")
+	assert.Equal(t, "writer", token)
+	assert.Equal(t, float64(0), tokenHealthy.Value(label))
+
+	// The token is reported healthy again once its quarantine ends, without waiting for a response
+	now = now.Add(time.Hour)
+	token, _ = pool.Token("test-user")
+	assert.Equal(t, "writer", token)
+	assert.Equal(t, float64(1), tokenHealthy.Value(label))
+
+	pool.Observe("writer", rateLimitResponse(http.StatusUnauthorized, 0, now))
+	_, err = pool.WriteToken("test-user")
+	assert.ErrorIs(t, err, ErrNoHealthyToken)
+	for _, token := range []string{"read-1", "read-2"} {
+		pool.Observe(token, rateLimitResponse(http.StatusUnauthorized, 0, now))
+	}
+	_, err = pool.Token("test-user")
+	assert.ErrorIs(t, err, ErrNoHealthyToken)
+}
+
+// TestServiceTokenPool tests that the service reads and writes with the pool's tokens and reports responses
+func TestServiceTokenPool(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Token:           "writer",
+			Username:        "test-user",
+			Tokens:          []string{"reader"},
+			TokenQuarantine: time.Hour,
+		},
+	}
+	tokens, err := NewTokenSource(cfg.GitHub)
+	require.NoError(t, err)
+	pool := tokens.(*TokenPool)
+
+	service := NewGitHubServiceWithTokens(cfg, tokens).(*GitHubService)
+
+	var authorizations []string
+	service.client.Transport = &mockTransport{
+		mockResponse: func(req *http.Request) (*http.Response, error) {
+			authorizations = append(authorizations, req.Method+" "+req.Header.Get("Authorization"))
+			resp := rateLimitResponse(http.StatusOK, 4999, time.Now().Add(time.Hour))
+			if req.Method == "POST" {
+				resp.StatusCode = http.StatusCreated
+			} else if req.Header.Get("Authorization") == "token writer" {
+				resp.StatusCode = http.StatusUnauthorized
+			}
+			resp.Body = io.NopCloser(strings.NewReader(`{}`))
+			return resp, nil
+		},
+	}
+
+	// Both tokens start with the default limit, so the first read goes to the write token
+	_, err = service.GetRepository("test-repo")
+	assert.Error(t, err)
+	_, err = service.GetRepository("test-repo")
+	assert.NoError(t, err)
+	_, err = service.CreateIssue("test-repo", &models.IssueRequest{Title: "Bug", Body: "Details"})
+	assert.ErrorIs(t, err, ErrNoHealthyToken)
+
+	assert.Equal(t, []string{"GET token writer", "GET token reader"}, authorizations)
+	assert.Equal(t, float64(4999), tokenRemaining.Value(pool.tokens[1].label))
+}
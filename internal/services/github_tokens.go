@@ -0,0 +1,45 @@
+package services
+
+import (
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
+)
+
+// TokenSource supplies the token used to call GitHub on behalf of an owner
+type TokenSource interface {
+	// Token returns a token that can act on the owner's repositories
+	Token(owner string) (string, error)
+}
+
+// PooledTokenSource is implemented by token sources that hold several
+// tokens, any of which may be sent, so that each can be checked
+type PooledTokenSource interface {
+	Tokens() []string
+}
+
+// PermissionSource is implemented by token sources whose tokens carry
+// permissions that GitHub does not report in the X-OAuth-Scopes header
+type PermissionSource interface {
+	Permissions(owner string) (map[string]string, error)
+}
+
+// StaticTokenSource always returns the same token, such as a personal access token
+type StaticTokenSource string
+
+// Token implements TokenSource
+func (s StaticTokenSource) Token(string) (string, error) {
+	return string(s), nil
+}
+
+// NewTokenSource returns the token source selected by the configuration: a
+// GitHub App when an app ID is set, a pool when several tokens are set, and
+// the configured token otherwise
+func NewTokenSource(cfg config.GitHubConfig) (TokenSource, error) {
+	switch {
+	case cfg.AppID != 0:
+		return NewAppTokenSource(cfg.AppID, cfg.AppPrivateKeyPath, cfg.AppInstallationID)
+	case len(cfg.Tokens) > 0:
+		return NewTokenPool(cfg.Tokens, cfg.Token, cfg.TokenQuarantine)
+	default:
+		return StaticTokenSource(cfg.Token), nil
+	}
+}
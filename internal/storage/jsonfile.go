@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONFile persists a single JSON document on disk. Writes go to a temporary
+// file that is renamed into place, so a crash never leaves a partial document.
+type JSONFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONFile creates a JSONFile at path, creating its directory if needed
+func NewJSONFile(path string) (*JSONFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	return &JSONFile{path: path}, nil
+}
+
+// Load decodes the document into v. A missing file leaves v untouched.
+func (f *JSONFile) Load(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.path, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Save encodes v and atomically replaces the document
+func (f *JSONFile) Save(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", f.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", f.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", f.path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", f.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+)
+
+// DeliveryCache remembers recently seen delivery IDs so that redeliveries
+// of an already processed event are ignored
+type DeliveryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	seen    map[string]time.Time
+	nowFunc func() time.Time
+}
+
+// NewDeliveryCache creates a DeliveryCache that remembers IDs for ttl
+func NewDeliveryCache(ttl time.Duration) *DeliveryCache {
+	return &DeliveryCache{
+		ttl:     ttl,
+		seen:    make(map[string]time.Time),
+		nowFunc: time.Now,
+	}
+}
+
+// MarkSeen records a delivery ID, returning false if it was already recorded
+func (d *DeliveryCache) MarkSeen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.nowFunc()
+	d.evictExpired(now)
+
+	if _, ok := d.seen[id]; ok {
+		return false
+	}
+	d.seen[id] = now.Add(d.ttl)
+	return true
+}
+
+// Forget removes a delivery ID so that a redelivery is processed again
+func (d *DeliveryCache) Forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, id)
+}
+
+// evictExpired removes expired IDs; callers must hold the lock
+func (d *DeliveryCache) evictExpired(now time.Time) {
+	for id, expiresAt := range d.seen {
+		if now.After(expiresAt) {
+			delete(d.seen, id)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AllEvents registers a handler for every event type
+const AllEvents = "*"
+
+// HandlerFunc processes a webhook event. Handlers run on the request path and
+// should hand off slow work instead of blocking.
+type HandlerFunc func(event *Event) error
+
+// Dispatcher routes webhook events to the in-process handlers registered for them
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+}
+
+// NewDispatcher creates an empty Dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string][]HandlerFunc),
+	}
+}
+
+// Register adds a handler for an event type, or for all events with AllEvents
+func (d *Dispatcher) Register(eventType string, handler HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Dispatch runs every handler registered for the event. All handlers run even
+// if one fails; their errors are joined and returned.
+func (d *Dispatcher) Dispatch(event *Event) error {
+	d.mu.RLock()
+	handlers := append(append([]HandlerFunc{}, d.handlers[event.Type]...), d.handlers[AllEvents]...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := runHandler(handler, event); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"event":       event.Type,
+				"delivery_id": event.DeliveryID,
+			}).Error("Webhook handler failed")
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runHandler calls a handler, converting a panic into an error
+func runHandler(handler HandlerFunc, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webhook handler panicked: %v", r)
+		}
+	}()
+	return handler(event)
+}
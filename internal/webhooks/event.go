@@ -0,0 +1,88 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+)
+
+// Event types parsed into typed payloads
+const (
+	EventPush        = "push"
+	EventIssues      = "issues"
+	EventPullRequest = "pull_request"
+	EventRelease     = "release"
+	EventWorkflowRun = "workflow_run"
+	EventPing        = "ping"
+)
+
+// Event is a verified webhook delivery
+type Event struct {
+	// Type is the value of the X-GitHub-Event header
+	Type string
+	// DeliveryID is the value of the X-GitHub-Delivery header
+	DeliveryID string
+	// Action is the payload's action field, if it has one
+	Action string
+	// Repository is the full name of the repository the event belongs to
+	Repository string
+	// Payload is the typed payload for known event types, or nil
+	Payload interface{}
+	// Raw is the original request body
+	Raw json.RawMessage
+	// ReceivedAt is when the delivery was received
+	ReceivedAt time.Time
+}
+
+// envelope holds the fields common to all webhook payloads
+type envelope struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// ParseEvent parses a webhook body into an Event. Unknown event types are
+// accepted with a nil Payload so that handlers can still use the raw body.
+func ParseEvent(eventType, deliveryID string, body []byte) (*Event, error) {
+	var common envelope
+	if err := json.Unmarshal(body, &common); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	event := &Event{
+		Type:       eventType,
+		DeliveryID: deliveryID,
+		Action:     common.Action,
+		Repository: common.Repository.FullName,
+		Raw:        json.RawMessage(body),
+		ReceivedAt: time.Now().UTC(),
+	}
+
+	var payload interface{}
+	switch eventType {
+	case EventPush:
+		payload = &models.PushEvent{}
+	case EventIssues:
+		payload = &models.IssuesEvent{}
+	case EventPullRequest:
+		payload = &models.PullRequestEvent{}
+	case EventRelease:
+		payload = &models.ReleaseEvent{}
+	case EventWorkflowRun:
+		payload = &models.WorkflowRunEvent{}
+	case EventPing:
+		payload = &models.PingEvent{}
+	default:
+		return event, nil
+	}
+
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s payload: %w", eventType, err)
+	}
+	event.Payload = payload
+
+	return event, nil
+}
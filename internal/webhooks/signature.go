@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// signaturePrefix is the prefix of the X-Hub-Signature-256 header value
+const signaturePrefix = "sha256="
+
+// Sign computes the X-Hub-Signature-256 header value for a payload
+func Sign(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks an X-Hub-Signature-256 header value against the payload
+// using a constant-time comparison
+func VerifySignature(secret []byte, payload []byte, signature string) bool {
+	if len(secret) == 0 || !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
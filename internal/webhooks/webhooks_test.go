@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVerifySignature tests HMAC signature verification
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("It's a Secret to Everybody")
+	payload := []byte("Hello, World!")
+
+	// Value taken from GitHub's webhook validation documentation
+	signature := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	assert.Equal(t, signature, Sign(secret, payload))
+	assert.True(t, VerifySignature(secret, payload, signature))
+	assert.False(t, VerifySignature(secret, []byte("Hello, World?"), signature))
+	assert.False(t, VerifySignature([]byte("other"), payload, signature))
+	assert.False(t, VerifySignature(secret, payload, "sha1=757107ea"))
+	assert.False(t, VerifySignature(nil, payload, signature))
+}
+
+// TestParseEvent tests parsing of known and unknown event types
+func TestParseEvent(t *testing.T) {
+	body := []byte(`{"action": "opened", "issue": {"number": 3, "title": "Bug"}, "repository": {"full_name": "test-user/test-repo", "created_at": 1700000000}}`)
+
+	event, err := ParseEvent(EventIssues, "delivery-1", body)
+	assert.NoError(t, err)
+	assert.Equal(t, "opened", event.Action)
+	assert.Equal(t, "test-user/test-repo", event.Repository)
+
+	issues, ok := event.Payload.(*models.IssuesEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 3, issues.Issue.Number)
+
+	event, err = ParseEvent("star", "delivery-2", body)
+	assert.NoError(t, err)
+	assert.Nil(t, event.Payload)
+	assert.Equal(t, "test-user/test-repo", event.Repository)
+
+	_, err = ParseEvent(EventPush, "delivery-3", []byte(`not json`))
+	assert.Error(t, err)
+}
+
+// TestDeliveryCache tests duplicate detection and expiry
+func TestDeliveryCache(t *testing.T) {
+	now := time.Now()
+	cache := NewDeliveryCache(time.Minute)
+	cache.nowFunc = func() time.Time { return now }
+
+	assert.True(t, cache.MarkSeen("a"))
+	assert.False(t, cache.MarkSeen("a"))
+
+	cache.Forget("a")
+	assert.True(t, cache.MarkSeen("a"))
+
+	now = now.Add(2 * time.Minute)
+	assert.True(t, cache.MarkSeen("a"))
+}
+
+// TestDispatcher tests that handlers run for their event type and for all events
+func TestDispatcher(t *testing.T) {
+	dispatcher := NewDispatcher()
+
+	var calls []string
+	dispatcher.Register(EventPush, func(event *Event) error {
+		calls = append(calls, "push")
+		return nil
+	})
+	dispatcher.Register(AllEvents, func(event *Event) error {
+		calls = append(calls, "all")
+		return errors.New("downstream unavailable")
+	})
+	dispatcher.Register(EventIssues, func(event *Event) error {
+		panic("boom")
+	})
+
+	err := dispatcher.Dispatch(&Event{Type: EventPush})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"push", "all"}, calls)
+
+	err = dispatcher.Dispatch(&Event{Type: EventIssues})
+	assert.ErrorContains(t, err, "panicked")
+}
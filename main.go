@@ -1,28 +1,108 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/config"
 	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/api/routes"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/auth"
+	"github.com/Sparker0i/Cactro-Backend-09-Mar-25/internal/services"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
+	createAPIKey := flag.String("create-api-key", "", "create an API key with this name, print it and exit")
+	scopes := flag.String("scopes", auth.ScopeRead, "comma separated scopes of the key created by -create-api-key")
+	flag.Parse()
+
 	// Load configuration
 	cfg := config.LoadConfig()
 
 	// Configure logging
 	configureLogging(cfg.LogLevel)
 
+	// Issue a key, e.g. the first admin key, without starting the server
+	if *createAPIKey != "" {
+		createKey(cfg, *createAPIKey, strings.Split(*scopes, ","))
+		return
+	}
+
+	// Find an unusable token before users do
+	verifyToken(cfg)
+
 	// Setup router
-	router := routes.SetupRouter(cfg)
+	router, shutdown := routes.SetupRouter(cfg)
 
 	// Start server
-	addr := fmt.Sprintf(":%s", cfg.Server.Port)
-	logrus.WithField("address", addr).Info("Starting server")
-	if err := router.Run(addr); err != nil {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler: router,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		logrus.WithField("address", server.Addr).Info("Starting server")
+		serverErr <- server.ListenAndServe()
+	}()
+
+	// Stop on SIGINT or SIGTERM, letting in-flight requests finish
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-serverErr:
+		shutdown()
 		logrus.WithError(err).Fatal("Failed to start server")
+	case <-signals.Done():
+	}
+
+	logrus.Info("Shutting down server")
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		// Long-lived streams may still be open; close them rather than wait
+		logrus.WithError(err).Warn("Timed out waiting for requests to finish")
+		server.Close()
+	}
+	shutdown()
+	logrus.Info("Server stopped")
+}
+
+// createKey creates an API key and prints it; the key cannot be shown again
+func createKey(cfg *config.Config, name string, scopes []string) {
+	if cfg.Auth.APIKeysPath == "" {
+		logrus.Fatal("AUTH_API_KEYS_PATH is required to create API keys")
+	}
+
+	keys, err := auth.NewKeyStore(cfg.Auth.APIKeysPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load API keys")
+	}
+
+	key, token, err := keys.CreateKey(name, scopes, 0)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create API key")
+	}
+
+	fmt.Printf("Created API key %s with scopes %s:\n%s\n", key.ID, strings.Join(key.Scopes, ","), token)
+}
+
+// verifyToken checks that the GitHub token has the access the enabled
+// features need, exiting in strict mode when it does not
+func verifyToken(cfg *config.Config) {
+	tokens, err := services.NewTokenSource(cfg.GitHub)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to configure GitHub authentication")
+	}
+
+	service := services.NewGitHubServiceWithTokens(cfg, tokens)
+	if err := services.VerifyToken(service, cfg); err != nil {
+		logrus.WithError(err).Fatal("GitHub token check failed")
 	}
 }
 